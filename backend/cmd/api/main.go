@@ -2,8 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
+	"foundation-sprint/internal/agents"
+	"foundation-sprint/internal/agents/mcp"
+	"foundation-sprint/internal/agents/tools"
 	"foundation-sprint/internal/database"
 	"foundation-sprint/internal/handlers"
+	"foundation-sprint/internal/mcpserver"
 	"foundation-sprint/internal/middleware"
 	"log"
 	"net/http"
@@ -43,6 +48,22 @@ func main() {
 	}
 	log.Println("Database connected successfully")
 	
+	// Initialize agents at boot so invalid custom agent or MCP server configs stop the server
+	if err := handlers.InitAgentService(); err != nil {
+		if errors.Is(err, agents.ErrInvalidAgentConfig) || errors.Is(err, mcp.ErrInvalidConfig) {
+			log.Fatalf("Failed to load agent configuration: %v", err)
+		}
+		log.Printf("Agent service not initialized, will retry on first request: %v", err)
+	}
+	
+	// Load phase exit criteria at boot so an invalid PHASE_GATES_FILE stops the server
+	if err := handlers.InitPhaseGates(); err != nil {
+		log.Fatalf("Failed to load phase gates: %v", err)
+	}
+	if err := handlers.InitDeckThemes(); err != nil {
+		log.Fatalf("Failed to load pitch deck themes: %v", err)
+	}
+	
 	// 创建 Gin 路由器
 	r := gin.Default()
 
@@ -69,20 +90,91 @@ func main() {
 		{
 			foundation.POST("/rooms", handlers.CreateRoom)
 			foundation.GET("/rooms/:id", handlers.GetRoom)
+			foundation.POST("/rooms/:id/clone", handlers.CloneRoom)
+			foundation.PUT("/rooms/:id/settings", handlers.UpdateRoomSettings)
 			foundation.PUT("/rooms/:id/foundation", handlers.UpdateFoundation)
 			foundation.PUT("/rooms/:id/differentiation", handlers.UpdateDifferentiation)
+			foundation.GET("/rooms/:id/differentiation/matrix", handlers.GetMatrixAnalysis)
 			foundation.PUT("/rooms/:id/approach", handlers.UpdateApproach)
+			foundation.GET("/rooms/:id/approach/decision", handlers.GetApproachDecision)
 			foundation.PUT("/rooms/:id/status", handlers.UpdateRoomStatus)
+			foundation.GET("/rooms/:id/phase-gate", handlers.GetPhaseGate)
+			foundation.GET("/rooms/:id/report", handlers.GetRoomReport)
+			foundation.GET("/rooms/:id/pitch-deck", handlers.GetPitchDeck)
+			
+			// 基础信息卡片
+			foundation.POST("/rooms/:id/foundation/cards", handlers.CreateFoundationCard)
+			foundation.PUT("/rooms/:id/foundation/cards/:card_id", handlers.UpdateFoundationCard)
+			foundation.DELETE("/rooms/:id/foundation/cards/:card_id", handlers.DeleteFoundationCard)
+			
+			// 房间模板
+			foundation.GET("/templates", handlers.GetTemplates)
+			foundation.POST("/templates", handlers.CreateTemplate)
+			foundation.GET("/templates/:id", handlers.GetTemplate)
+			foundation.PUT("/templates/:id", handlers.UpdateTemplate)
+			foundation.DELETE("/templates/:id", handlers.DeleteTemplate)
+			
+			// 重复卡片聚类与合并
+			foundation.GET("/rooms/:id/duplicates", handlers.GetDuplicates)
+			foundation.POST("/rooms/:id/duplicates/merge", handlers.MergeDuplicates)
+			
+			// Agent 提议
+			foundation.GET("/rooms/:id/proposals", handlers.GetProposals)
+			foundation.POST("/proposals/:id/accept", handlers.AcceptProposal)
+			foundation.POST("/proposals/:id/reject", handlers.RejectProposal)
+			
+			// 风险登记表
+			foundation.GET("/rooms/:id/risks", handlers.GetRisks)
+			foundation.POST("/rooms/:id/risks", handlers.CreateRisk)
+			foundation.GET("/rooms/:id/risks/heatmap", handlers.GetRiskHeatmap)
+			foundation.GET("/risks/:id", handlers.GetRisk)
+			foundation.PUT("/risks/:id", handlers.UpdateRisk)
+			foundation.DELETE("/risks/:id", handlers.DeleteRisk)
+			
+			// 创始假设
+			foundation.GET("/rooms/:id/hypotheses", handlers.GetHypotheses)
+			foundation.POST("/rooms/:id/hypotheses", handlers.CreateHypothesis)
+			foundation.POST("/rooms/:id/hypotheses/draft", handlers.DraftHypotheses)
+			foundation.GET("/rooms/:id/hypotheses/validation", handlers.GetHypothesisValidations)
+			foundation.GET("/hypotheses/:id", handlers.GetHypothesis)
+			foundation.PUT("/hypotheses/:id", handlers.UpdateHypothesis)
+			foundation.DELETE("/hypotheses/:id", handlers.DeleteHypothesis)
+			foundation.GET("/hypotheses/:id/validation", handlers.GetHypothesisValidation)
+			
+			// 访谈与计分卡（Design Sprint 测试）
+			foundation.GET("/rooms/:id/interviews", handlers.GetInterviews)
+			foundation.POST("/rooms/:id/interviews", handlers.CreateInterview)
+			foundation.GET("/interviews/:id", handlers.GetInterview)
+			foundation.PUT("/interviews/:id", handlers.UpdateInterview)
+			foundation.DELETE("/interviews/:id", handlers.DeleteInterview)
+			foundation.POST("/interviews/:id/scorecards", handlers.CreateScorecard)
+			foundation.PUT("/scorecards/:id", handlers.UpdateScorecard)
+			foundation.DELETE("/scorecards/:id", handlers.DeleteScorecard)
+			
+			// 附件（证据材料）
+			foundation.POST("/rooms/:id/attachments", handlers.UploadAttachment)
+			foundation.GET("/rooms/:id/attachments", handlers.GetAttachments)
+			foundation.GET("/attachments/:id", handlers.GetAttachment)
+			foundation.GET("/attachments/:id/download", handlers.DownloadAttachment)
+			foundation.POST("/attachments/:id/reprocess", handlers.ReprocessAttachment)
+			foundation.DELETE("/attachments/:id", handlers.DeleteAttachment)
+			
+			// 审计日志（仅主持人）
+			foundation.GET("/rooms/:id/audit", handlers.GetRoomAudit)
 		}
 		
 		// AI Agents
 		agents := api.Group("/agents")
 		{
 			// Standard endpoints
+			agents.GET("", handlers.ListAgents)
 			agents.POST("/think", handlers.ThinkAgent)
 			agents.POST("/critique", handlers.CritiqueAgent)
 			agents.POST("/research", handlers.ResearchAgent)
 			
+			// Multi-agent panel (parallel or debate mode)
+			agents.POST("/panel", handlers.AgentPanel)
+			
 			// Interactive endpoints (support multi-turn dialogue)
 			agents.POST("/think/interactive", handlers.ThinkAgentInteractive)
 			agents.POST("/critique/interactive", handlers.CritiqueAgentInteractive)
@@ -94,18 +186,51 @@ func main() {
 			agents.DELETE("/sessions/:session_id", handlers.DeleteSession)
 		}
 		
+		// LLM 用量与预算
+		usage := api.Group("/usage")
+		{
+			usage.GET("/rooms/:id", handlers.GetRoomUsage)
+			usage.PUT("/rooms/:id/budget", handlers.SetRoomBudget)
+			usage.GET("/daily", handlers.GetDailyUsage)
+			usage.PUT("/budget", handlers.SetWorkspaceBudget)
+			usage.GET("/pricing", handlers.GetPricing)
+		}
+		
+		// 知识库
+		knowledgeBase := api.Group("/knowledge")
+		{
+			knowledgeBase.GET("/search", handlers.SearchKnowledge)
+			knowledgeBase.GET("/documents", handlers.ListKnowledgeDocuments)
+			knowledgeBase.POST("/documents", handlers.CreateKnowledgeDocument)
+			knowledgeBase.GET("/documents/:id", handlers.GetKnowledgeDocument)
+			knowledgeBase.DELETE("/documents/:id", handlers.DeleteKnowledgeDocument)
+			knowledgeBase.POST("/rooms/:id/index", handlers.IndexRoomKnowledge)
+			knowledgeBase.POST("/reindex", handlers.ReindexKnowledge)
+		}
+		
 		// 协作功能
 		collaboration := api.Group("/collaboration")
 		{
 			collaboration.POST("/rooms/:id/vote", handlers.CreateVote)
 			collaboration.GET("/rooms/:id/votes", handlers.GetVotes)
 			collaboration.PUT("/votes/:id", handlers.UpdateVote)
+			collaboration.GET("/votes/:id/results", handlers.GetVoteResults)
+			collaboration.PUT("/votes/:id/status", handlers.UpdateVoteStatus)
+			collaboration.POST("/votes/:id/decision", handlers.DecideVote)
+			collaboration.GET("/votes/:id/audit", handlers.GetVoteAudit)
 		}
 	}
 
 	// WebSocket 路由
 	r.GET("/ws/:roomId", handlers.HandleWebSocket)
 
+	// MCP 服务（可选），房间修改通过 WebSocket 同步给网页端
+	if os.Getenv("MCP_SERVER_ENABLED") == "true" {
+		mcpServer := mcpserver.New(db, tools.DefaultRegistry, handlers.BroadcastToRoom)
+		r.POST("/mcp", gin.WrapH(mcpServer.HTTPHandler(mcpserver.DefaultUserID())))
+		log.Println("MCP server enabled at /mcp")
+	}
+
 	log.Println("Starting Foundation Sprint API server on :8080")
 	log.Fatal(http.ListenAndServe(":8080", r))
 }
\ No newline at end of file
@@ -0,0 +1,88 @@
+// Command mcp-server exposes sprint rooms and agent tools to MCP clients over stdio or HTTP.
+package main
+
+import (
+	"context"
+	"flag"
+	"foundation-sprint/internal/agents"
+	"foundation-sprint/internal/agents/tools"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/mcpserver"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	transport := flag.String("transport", "stdio", "transport to serve: stdio or http")
+	addr := flag.String("addr", ":8090", "listen address for the http transport")
+	flag.Parse()
+
+	// stdout carries protocol messages on stdio, so all logging goes to stderr
+	log.SetOutput(os.Stderr)
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	// Agent tools need no LLM client, and MCP client tools are included when configured
+	if err := agents.RegisterTools(); err != nil {
+		log.Fatalf("Failed to register tools: %v", err)
+	}
+	defer agents.MCPServers.Close()
+
+	// Think tools answer from templates unless an LLM is configured
+	if os.Getenv("THINK_TOOLS_LLM") != "false" {
+		if adapter, err := agents.NewLLMClientAdapter(); err == nil {
+			agents.ThinkToolModel.SetCompleter(agents.NewToolCompleter(adapter))
+		} else {
+			log.Printf("No LLM configured, think tools use templates: %v", err)
+		}
+	}
+
+	server := mcpserver.New(db, tools.DefaultRegistry, nil)
+	userID := mcpserver.DefaultUserID()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch *transport {
+	case "stdio":
+		if userID == "" {
+			log.Println("MCP_USER_ID is not set, room changes are disabled")
+		}
+		if err := server.ServeStdio(ctx, os.Stdin, os.Stdout, userID); err != nil {
+			log.Fatalf("stdio transport failed: %v", err)
+		}
+
+	case "http":
+		mux := http.NewServeMux()
+		mux.Handle("/mcp", server.HTTPHandler(userID))
+		httpServer := &http.Server{Addr: *addr, Handler: mux}
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			httpServer.Shutdown(shutdownCtx)
+		}()
+
+		log.Printf("MCP server listening on %s/mcp", *addr)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("http transport failed: %v", err)
+		}
+
+	default:
+		log.Fatalf("Unknown transport %q, expected stdio or http", *transport)
+	}
+}
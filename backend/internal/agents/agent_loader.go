@@ -0,0 +1,197 @@
+package agents
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"foundation-sprint/internal/agents/tools"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidAgentConfig is wrapped by every agent definition validation error
+var ErrInvalidAgentConfig = errors.New("invalid agent config")
+
+// builtinAgentNames are reserved for the agents implemented in Go
+var builtinAgentNames = map[string]bool{
+	"ThinkAgent":    true,
+	"CritiqueAgent": true,
+	"ResearchAgent": true,
+}
+
+// AgentDefinition describes an agent in a YAML config file, one agent per file
+type AgentDefinition struct {
+	Name                string   `yaml:"name" json:"name"`
+	Role                string   `yaml:"role" json:"role"`
+	BackgroundKnowledge string   `yaml:"background_knowledge" json:"background_knowledge"`
+	Responsibility      string   `yaml:"responsibility" json:"responsibility"`
+	Tools               []string `yaml:"tools" json:"tools"`
+	Model               string   `yaml:"model" json:"model,omitempty"`
+	Temperature         *float64 `yaml:"temperature" json:"temperature,omitempty"`
+	MaxIterations       int      `yaml:"max_iterations" json:"max_iterations"`
+	Source              string   `yaml:"-" json:"source"`
+}
+
+// Validate checks the definition against the registered tools
+func (d *AgentDefinition) Validate() error {
+	var problems []string
+
+	if strings.TrimSpace(d.Name) == "" {
+		problems = append(problems, "name is required")
+	} else if builtinAgentNames[d.Name] {
+		problems = append(problems, fmt.Sprintf("name %q is reserved for a built-in agent", d.Name))
+	}
+	if strings.TrimSpace(d.Role) == "" {
+		problems = append(problems, "role is required")
+	}
+	for _, name := range d.Tools {
+		if _, exists := tools.Get(name); !exists {
+			problems = append(problems, fmt.Sprintf("unknown tool %q", name))
+		}
+	}
+	if d.Temperature != nil && (*d.Temperature < 0 || *d.Temperature > 2) {
+		problems = append(problems, "temperature must be between 0 and 2")
+	}
+	if d.MaxIterations < 0 || d.MaxIterations > 20 {
+		problems = append(problems, "max_iterations must be between 0 and 20")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidAgentConfig, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// temperature returns the configured temperature, defaulting to the built-in agents' 0.7
+func (d *AgentDefinition) temperature() float64 {
+	if d.Temperature == nil {
+		return 0.7
+	}
+	return *d.Temperature
+}
+
+// LoadAgentDefinitions reads and validates every *.yaml and *.yml file in dir.
+// All problems are reported together, each prefixed with its file name.
+func LoadAgentDefinitions(dir string) ([]AgentDefinition, error) {
+	files, err := agentConfigFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []AgentDefinition
+	var errs []error
+	seen := make(map[string]string)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(file), err))
+			continue
+		}
+
+		var definition AgentDefinition
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&definition); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w: %v", filepath.Base(file), ErrInvalidAgentConfig, err))
+			continue
+		}
+		definition.Source = filepath.Base(file)
+
+		if err := definition.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", definition.Source, err))
+			continue
+		}
+		if other, exists := seen[definition.Name]; exists {
+			errs = append(errs, fmt.Errorf("%s: %w: agent %q is already defined in %s",
+				definition.Source, ErrInvalidAgentConfig, definition.Name, other))
+			continue
+		}
+		seen[definition.Name] = definition.Source
+
+		definitions = append(definitions, definition)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return definitions, nil
+}
+
+// agentConfigFiles lists the agent config files in dir in name order
+func agentConfigFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent config dir: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml":
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// agentConfigSignature summarizes the config files so the watcher can detect changes
+func agentConfigSignature(dir string) string {
+	files, err := agentConfigFiles(dir)
+	if err != nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", file, info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(parts, "|")
+}
+
+// WatchAgentConfigs polls dir and reloads the config agents whenever a file is added, changed or removed.
+// It returns a function that stops the watcher.
+func (s *Service) WatchAgentConfigs(dir string, interval time.Duration) func() {
+	stop := make(chan struct{})
+	signature := agentConfigSignature(dir)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				current := agentConfigSignature(dir)
+				if current == signature {
+					continue
+				}
+				signature = current
+
+				if err := s.LoadAgentConfigs(dir); err != nil {
+					log.Printf("Agent config reload failed, keeping previous agents: %v", err)
+					continue
+				}
+				log.Printf("Reloaded agent configs from %s", dir)
+			}
+		}
+	}()
+
+	return func() { close(stop) }
+}
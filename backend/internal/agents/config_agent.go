@@ -0,0 +1,51 @@
+package agents
+
+import (
+	"context"
+	"foundation-sprint/internal/agents/tools"
+)
+
+// ConfigAgent is an agent defined in a config file instead of Go code
+type ConfigAgent struct {
+	BaseAgent
+	processor  *ReActProcessor
+	definition AgentDefinition
+}
+
+// NewConfigAgent creates an agent from a validated definition
+func NewConfigAgent(definition AgentDefinition, llmClient LLMClient) *ConfigAgent {
+	agent := &ConfigAgent{
+		BaseAgent: BaseAgent{
+			Name:                definition.Name,
+			Role:                definition.Role,
+			BackgroundKnowledge: definition.BackgroundKnowledge,
+			Responsibility:      definition.Responsibility,
+			LLMClient:           llmClient,
+			MaxIterations:       definition.MaxIterations,
+		},
+		definition: definition,
+	}
+
+	// Publish the tool list so the registry resolves it like the built-in agents
+	tools.SetAgentTools(definition.Name, definition.Tools)
+	agent.Tools = GetToolsForAgent(definition.Name)
+
+	agent.processor = NewReActProcessor(agent, llmClient, agent.Tools, definition.MaxIterations)
+	agent.processor.SetModelSettings(definition.Model, definition.temperature())
+
+	return agent
+}
+
+// Definition returns the definition the agent was built from
+func (a *ConfigAgent) Definition() AgentDefinition {
+	return a.definition
+}
+
+// Process executes the agent's ReAct loop
+func (a *ConfigAgent) Process(ctx context.Context, input AgentInput) (*AgentOutput, error) {
+	output, err := a.processor.Process(ctx, input)
+	if err != nil {
+		return nil, NewAgentError(a.Name, input.Phase, "processing failed", err)
+	}
+	return output, nil
+}
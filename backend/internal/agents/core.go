@@ -3,6 +3,7 @@ package agents
 import (
 	"context"
 	"fmt"
+	"foundation-sprint/internal/models"
 )
 
 // Agent represents a Sub-Agent with specific role and capabilities
@@ -28,21 +29,28 @@ type AgentInput struct {
 	Query   string                 `json:"query"`    // User's question or request
 	Context string                 `json:"context"`  // Current sprint context
 	RoomID  string                 `json:"room_id"`  // Room identifier for collaboration
+	UserID  string                 `json:"user_id"`  // User who made the request, for usage attribution
 	History []ConversationHistory  `json:"history"`  // Previous conversation
 	Phase   string                 `json:"phase"`    // Current sprint phase (foundation/differentiation/approach)
 	Data    map[string]interface{} `json:"data"`     // Additional phase-specific data
+	
+	RoomContext string `json:"room_context,omitempty"` // Rendered live room state, filled by Service.EnrichInput
 }
 
 // AgentOutput represents output from an agent
 type AgentOutput struct {
-	Response     string                 `json:"response"`      // Main response text
-	Reasoning    []ReActStep            `json:"reasoning"`     // ReAct reasoning steps
-	Tools        []ToolExecution        `json:"tools"`         // Tools used during processing
-	Suggestions  []string               `json:"suggestions"`   // Additional suggestions
-	References   []Reference            `json:"references"`    // External references or sources
-	Confidence   float64                `json:"confidence"`    // Confidence score (0-1)
-	NextActions  []string               `json:"next_actions"`  // Recommended next steps
-	Metadata     map[string]interface{} `json:"metadata"`      // Additional metadata
+	Response    string                 `json:"response"`             // Main response text
+	Reasoning   []ReActStep            `json:"reasoning"`            // ReAct reasoning steps
+	Tools       []ToolExecution        `json:"tools"`                // Tools used during processing
+	Suggestions []string               `json:"suggestions"`          // Additional suggestions
+	References  []Reference            `json:"references"`           // External references or sources
+	Confidence  float64                `json:"confidence"`           // Confidence score (0-1)
+	NextActions []string               `json:"next_actions"`         // Recommended next steps
+	Metadata    map[string]interface{} `json:"metadata"`             // Additional metadata
+	Usage       TokenUsage             `json:"usage"`                // Tokens used by all LLM calls
+	Proposals   []*models.Proposal     `json:"proposals"`            // Structured room changes awaiting facilitator review
+	Risks       []*models.Risk         `json:"risks,omitempty"`      // Risks added to the room's risk register during this run
+	Hypotheses  []*models.Hypothesis   `json:"hypotheses,omitempty"` // Hypotheses recorded from assumption_checker during this run
 }
 
 // ReActStep represents a single step in the ReAct reasoning process
@@ -123,7 +131,7 @@ type Tool interface {
 
 // LLMClient represents the interface to communicate with LLM providers
 type LLMClient interface {
-	Complete(ctx context.Context, prompt string, options ...LLMOption) (string, error)
+	Complete(ctx context.Context, prompt string, options ...LLMOption) (*LLMResponse, error)
 	CompleteWithTools(ctx context.Context, prompt string, tools []Tool, options ...LLMOption) (*LLMResponse, error)
 	Stream(ctx context.Context, prompt string, options ...LLMOption) (<-chan string, error)
 }
@@ -133,6 +141,7 @@ type LLMResponse struct {
 	Content      string         `json:"content"`
 	ToolCalls    []ToolCall     `json:"tool_calls,omitempty"`
 	FinishReason string         `json:"finish_reason"`
+	Model        string         `json:"model"`
 	Usage        TokenUsage     `json:"usage"`
 }
 
@@ -208,6 +217,11 @@ func (e *AgentError) Error() string {
 	return fmt.Sprintf("agent %s error in phase %s: %s", e.Agent, e.Phase, e.Message)
 }
 
+// Unwrap returns the underlying cause
+func (e *AgentError) Unwrap() error {
+	return e.Cause
+}
+
 // NewAgentError creates a new agent error
 func NewAgentError(agent, phase, message string, cause error) *AgentError {
 	return &AgentError{
@@ -25,9 +25,9 @@ func NewCritiqueAgent(llmClient LLMClient) *CritiqueAgent {
 - 投资尽职调查
 我见过许多创业项目的成功和失败，深知常见的陷阱和误区。`,
 			Responsibility: `帮助用户：
-1. 识别和挑战隐含假设
+1. 识别和挑战隐含假设，用 assumption_checker 记录为待验证的假设
 2. 评估市场真实需求
-3. 分析竞争威胁和风险
+3. 分析竞争威胁和风险，用 risk_assessor 为具体风险评分并记入风险登记表
 4. 验证商业模式可行性
 5. 提供现实的改进建议`,
 			LLMClient:     llmClient,
@@ -0,0 +1,153 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Debate stages in the order they run
+const (
+	DebateStageProposal  = "proposal"
+	DebateStageCritique  = "critique"
+	DebateStageEvidence  = "evidence"
+	DebateStageSynthesis = "synthesis"
+)
+
+// moderatorName attributes the synthesis call in usage records and traces
+const moderatorName = "Moderator"
+
+// DebateTurn records who said what at one stage of a debate
+type DebateTurn struct {
+	Stage     string       `json:"stage"`
+	Agent     string       `json:"agent"`
+	Query     string       `json:"query"`
+	Output    *AgentOutput `json:"output,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Duration  int64        `json:"duration_ms"`
+	StartedAt time.Time    `json:"started_at"`
+}
+
+// DebateResult is the outcome of a Think → Critique → Research debate
+type DebateResult struct {
+	Recommendation string       `json:"recommendation"`
+	Trace          []DebateTurn `json:"trace"`
+	Usage          TokenUsage   `json:"usage"`
+}
+
+// ProcessDebate has CritiqueAgent challenge ThinkAgent's answer and ResearchAgent ground both,
+// then synthesizes a recommendation. Only a failing ThinkAgent aborts the debate; later failures
+// are recorded in the trace and the synthesis works with what is available.
+func (s *Service) ProcessDebate(ctx context.Context, input AgentInput) (*DebateResult, error) {
+	if err := s.CheckBudget(ctx, input.RoomID); err != nil {
+		return nil, err
+	}
+	s.EnrichInput(ctx, &input)
+
+	result := &DebateResult{Trace: []DebateTurn{}}
+
+	proposal := s.debateTurn(ctx, result, DebateStageProposal, "ThinkAgent", input, input.Query)
+	if proposal.Output == nil {
+		return nil, fmt.Errorf("debate aborted, ThinkAgent failed: %s", proposal.Error)
+	}
+
+	critique := s.debateTurn(ctx, result, DebateStageCritique, "CritiqueAgent", input, fmt.Sprintf(
+		`Critically examine the following answer to "%s". Point out flawed assumptions, risks and blind spots.
+
+ThinkAgent's answer:
+%s`, input.Query, proposal.Output.Response))
+
+	evidenceQuery := fmt.Sprintf(
+		`Find evidence that supports or refutes the ideas and objections below for "%s".
+
+ThinkAgent's answer:
+%s`, input.Query, proposal.Output.Response)
+	if critique.Output != nil {
+		evidenceQuery += fmt.Sprintf("\n\nCritiqueAgent's objections:\n%s", critique.Output.Response)
+	}
+	s.debateTurn(ctx, result, DebateStageEvidence, "ResearchAgent", input, evidenceQuery)
+
+	result.Recommendation = s.synthesizeDebate(ctx, result, input)
+
+	return result, nil
+}
+
+// debateTurn runs one agent as a stage of the debate and appends it to the trace
+func (s *Service) debateTurn(ctx context.Context, result *DebateResult, stage, agentName string, input AgentInput, query string) DebateTurn {
+	turn := DebateTurn{
+		Stage:     stage,
+		Agent:     agentName,
+		Query:     query,
+		StartedAt: time.Now(),
+	}
+
+	agent, err := s.GetAgent(agentName)
+	if err == nil {
+		input.Query = query
+		turn.Output, err = agent.Process(ctx, input)
+	}
+	turn.Duration = time.Since(turn.StartedAt).Milliseconds()
+
+	if err != nil {
+		turn.Error = err.Error()
+	} else {
+		s.SaveProposals(ctx, turn.Output)
+		s.SaveRisks(ctx, input.RoomID, agentName, turn.Output)
+		s.SaveHypotheses(ctx, input.RoomID, agentName, turn.Output)
+		addUsage(&result.Usage, turn.Output.Usage)
+	}
+
+	result.Trace = append(result.Trace, turn)
+	return turn
+}
+
+// synthesizeDebate asks the LLM for a final recommendation, falling back to the debate transcript
+func (s *Service) synthesizeDebate(ctx context.Context, result *DebateResult, input AgentInput) string {
+	transcript := make([]string, 0, len(result.Trace))
+	for _, turn := range result.Trace {
+		if turn.Output != nil {
+			transcript = append(transcript, fmt.Sprintf("## %s (%s)\n%s", turn.Agent, turn.Stage, turn.Output.Response))
+		}
+	}
+
+	turn := DebateTurn{
+		Stage:     DebateStageSynthesis,
+		Agent:     moderatorName,
+		StartedAt: time.Now(),
+	}
+
+	prompt := fmt.Sprintf(`You are moderating a Foundation Sprint debate about: %s
+%s
+Here is what each agent said:
+
+%s
+
+Write a balanced recommendation for the team: what to do, which objections matter most,
+what the evidence says, and the open questions that remain.`,
+		input.Query, input.RoomContext, strings.Join(transcript, "\n\n"))
+	turn.Query = prompt
+
+	ctx = WithUsageScope(ctx, UsageScope{
+		RoomID:    input.RoomID,
+		UserID:    input.UserID,
+		AgentName: moderatorName,
+		CallType:  DebateStageSynthesis,
+	})
+
+	recommendation := strings.Join(transcript, "\n\n")
+	response, err := s.LLMClient.Complete(ctx, prompt,
+		WithTemperature(0.5),
+		WithMaxTokens(800))
+	if err != nil {
+		turn.Error = err.Error()
+	} else {
+		recommendation = response.Content
+		turn.Output = &AgentOutput{Response: response.Content, Usage: response.Usage}
+		addUsage(&result.Usage, response.Usage)
+	}
+	turn.Duration = time.Since(turn.StartedAt).Milliseconds()
+
+	result.Trace = append(result.Trace, turn)
+	return recommendation
+}
@@ -0,0 +1,95 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"foundation-sprint/internal/agents/tools"
+	"foundation-sprint/internal/models"
+	"log"
+	"strings"
+)
+
+// HypothesisStore persists the room's founding hypotheses
+type HypothesisStore interface {
+	Create(ctx context.Context, hypothesis *models.Hypothesis) error
+	GetByRoom(ctx context.Context, roomID string, status string) ([]*models.Hypothesis, error)
+}
+
+// SetHypothesisStore sets the store that receives the assumptions listed by assumption_checker
+func (s *Service) SetHypothesisStore(store HypothesisStore) {
+	s.hypotheses = store
+}
+
+// SaveHypotheses records the assumptions an agent passed to assumption_checker as untested
+// hypotheses of the room and lists them in output.Hypotheses. Like SaveRisks it skips the generic
+// checklist and statements the room already has.
+func (s *Service) SaveHypotheses(ctx context.Context, roomID, agentName string, output *AgentOutput) {
+	if s.hypotheses == nil || roomID == "" || output == nil {
+		return
+	}
+
+	checked := checkedAssumptions(output.Tools)
+	if len(checked) == 0 {
+		return
+	}
+
+	existing, err := s.hypotheses.GetByRoom(ctx, roomID, "")
+	if err != nil {
+		log.Printf("Failed to load hypotheses of room %s: %v", roomID, err)
+		return
+	}
+	known := make(map[string]bool, len(existing))
+	for _, hypothesis := range existing {
+		known[strings.ToLower(hypothesis.Statement)] = true
+	}
+
+	for _, item := range checked {
+		statement := strings.TrimSpace(item.Assumption)
+		if known[strings.ToLower(statement)] {
+			continue
+		}
+
+		hypothesis := models.NewHypothesis(roomID)
+		hypothesis.Statement = statement
+		hypothesis.Confidence = item.Confidence
+		hypothesis.ValidationMethod = item.Validation
+		hypothesis.SuccessMetric = item.SuccessMetric
+		hypothesis.Source = models.HypothesisSourceAgent
+		hypothesis.AgentName = agentName
+		if err := hypothesis.Validate(); err != nil {
+			log.Printf("Dropping invalid hypothesis from %s: %v", agentName, err)
+			continue
+		}
+		if err := s.hypotheses.Create(ctx, hypothesis); err != nil {
+			log.Printf("Failed to save hypothesis from %s: %v", agentName, err)
+			continue
+		}
+
+		known[strings.ToLower(statement)] = true
+		output.Hypotheses = append(output.Hypotheses, hypothesis)
+	}
+}
+
+// checkedAssumptions collects the assumptions passed to successful assumption_checker calls
+func checkedAssumptions(executions []ToolExecution) []tools.CheckedAssumption {
+	var assumptions []tools.CheckedAssumption
+	for _, execution := range executions {
+		if execution.ToolName != "assumption_checker" || !execution.Success {
+			continue
+		}
+
+		data, err := json.Marshal(execution.Output)
+		if err != nil {
+			continue
+		}
+		var result struct {
+			Source      string                    `json:"source"`
+			Assumptions []tools.CheckedAssumption `json:"identified_assumptions"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil || result.Source != "input" {
+			continue
+		}
+		assumptions = append(assumptions, result.Assumptions...)
+	}
+	return assumptions
+}
@@ -164,8 +164,20 @@ func (p *InteractiveReActProcessor) ProcessInteractive(
 		session = p.mergeInputWithSession(session, input)
 	}
 	
+	// Attribute every LLM call to this session
+	ctx = WithUsageScope(ctx, UsageScope{
+		RoomID:    session.OriginalInput.RoomID,
+		UserID:    session.OriginalInput.UserID,
+		AgentName: session.AgentName,
+		SessionID: session.SessionID,
+	})
+	ctx = withToolRoomContext(ctx, session.OriginalInput)
+	
 	// Execute ReAct loop with interruption points
 	output, needsInteraction, err := p.executeInteractiveLoop(ctx, session)
+	if err == nil && !needsInteraction {
+		attachProposals(output, session.OriginalInput, session.AgentName)
+	}
 	
 	// Save session state
 	if err := p.sessionStore.Save(ctx, session); err != nil {
@@ -232,7 +244,7 @@ func (p *InteractiveReActProcessor) executeInteractiveLoop(
 		// Generate thought and action
 		thoughtPrompt := p.buildThoughtPromptWithHistory(session)
 		response, err := p.llmClient.CompleteWithTools(
-			ctx,
+			withCallType(ctx, "thought"),
 			thoughtPrompt,
 			p.getToolList(),
 			WithSystemPrompt(session.SystemPrompt),
@@ -244,6 +256,7 @@ func (p *InteractiveReActProcessor) executeInteractiveLoop(
 			session.Error = err.Error()
 			return output, false, err
 		}
+		addUsage(&output.Usage, response.Usage)
 		
 		// Parse response
 		thought, action, actionInput, isFinal := p.parseReActResponse(response.Content)
@@ -287,17 +300,21 @@ func (p *InteractiveReActProcessor) executeInteractiveLoop(
 			step.Observation = observation
 			if toolExec != nil {
 				output.Tools = append(output.Tools, *toolExec)
+				addToolReferences(output, toolExec)
 			}
 			
 			// Generate reflection
 			reflectionPrompt := p.buildReflectionPrompt(step)
-			reflection, _ := p.llmClient.Complete(
-				ctx,
+			reflection, err := p.llmClient.Complete(
+				withCallType(ctx, "reflection"),
 				reflectionPrompt,
 				WithTemperature(0.5),
 				WithMaxTokens(200),
 			)
-			step.Reflection = reflection
+			if err == nil {
+				step.Reflection = reflection.Content
+				addUsage(&output.Usage, reflection.Usage)
+			}
 		}
 		
 		// Save completed step
@@ -450,13 +467,16 @@ func (p *InteractiveReActProcessor) processPendingAction(
 			
 			// Generate reflection on user input
 			reflectionPrompt := p.buildReflectionPrompt(*lastStep)
-			reflection, _ := p.llmClient.Complete(
-				ctx,
+			reflection, err := p.llmClient.Complete(
+				withCallType(ctx, "reflection"),
 				reflectionPrompt,
 				WithTemperature(0.5),
 				WithMaxTokens(200),
 			)
-			lastStep.Reflection = reflection
+			if err == nil {
+				lastStep.Reflection = reflection.Content
+				addUsage(&output.Usage, reflection.Usage)
+			}
 		}
 	}
 	
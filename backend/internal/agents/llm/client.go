@@ -75,8 +75,13 @@ func NewClient(provider Provider) (*Client, error) {
 	}, nil
 }
 
+// Model returns the default model used when no WithModel option is given
+func (c *Client) Model() string {
+	return c.model
+}
+
 // Complete generates a completion for the given prompt
-func (c *Client) Complete(ctx context.Context, prompt string, options ...Option) (string, error) {
+func (c *Client) Complete(ctx context.Context, prompt string, options ...Option) (*Response, error) {
 	config := &Config{
 		Temperature: 0.7,
 		MaxTokens:   1000,
@@ -93,7 +98,7 @@ func (c *Client) Complete(ctx context.Context, prompt string, options ...Option)
 	case ProviderAnthropic:
 		return c.completeAnthropic(ctx, prompt, config)
 	default:
-		return "", fmt.Errorf("unsupported provider: %s", c.provider)
+		return nil, fmt.Errorf("unsupported provider: %s", c.provider)
 	}
 }
 
@@ -142,7 +147,7 @@ func (c *Client) Stream(ctx context.Context, prompt string, options ...Option) (
 }
 
 // completeOpenAI handles OpenAI completions
-func (c *Client) completeOpenAI(ctx context.Context, prompt string, config *Config) (string, error) {
+func (c *Client) completeOpenAI(ctx context.Context, prompt string, config *Config) (*Response, error) {
 	messages := []map[string]string{
 		{"role": "user", "content": prompt},
 	}
@@ -162,12 +167,12 @@ func (c *Client) completeOpenAI(ctx context.Context, prompt string, config *Conf
 
 	body, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/chat/completions", bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -175,36 +180,57 @@ func (c *Client) completeOpenAI(ctx context.Context, prompt string, config *Conf
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	var response struct {
+		Model   string `json:"model"`
 		Choices []struct {
 			Message struct {
 				Content string `json:"content"`
 			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
 		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			TotalTokens      int `json:"total_tokens"`
+		} `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("no response from API")
+		return nil, fmt.Errorf("no response from API")
 	}
 
-	return response.Choices[0].Message.Content, nil
+	model := response.Model
+	if model == "" {
+		model = config.Model
+	}
+
+	return &Response{
+		Content:      response.Choices[0].Message.Content,
+		FinishReason: response.Choices[0].FinishReason,
+		Model:        model,
+		Usage: TokenUsage{
+			PromptTokens:     response.Usage.PromptTokens,
+			CompletionTokens: response.Usage.CompletionTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+		},
+	}, nil
 }
 
 // completeAnthropic handles Anthropic completions
-func (c *Client) completeAnthropic(ctx context.Context, prompt string, config *Config) (string, error) {
+func (c *Client) completeAnthropic(ctx context.Context, prompt string, config *Config) (*Response, error) {
 	messages := []map[string]string{
 		{"role": "user", "content": prompt},
 	}
@@ -222,12 +248,12 @@ func (c *Client) completeAnthropic(ctx context.Context, prompt string, config *C
 
 	body, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/messages", bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -236,30 +262,50 @@ func (c *Client) completeAnthropic(ctx context.Context, prompt string, config *C
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	var response struct {
+		Model   string `json:"model"`
 		Content []struct {
 			Text string `json:"text"`
 		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if len(response.Content) == 0 {
-		return "", fmt.Errorf("no response from API")
+		return nil, fmt.Errorf("no response from API")
+	}
+
+	model := response.Model
+	if model == "" {
+		model = config.Model
 	}
 
-	return response.Content[0].Text, nil
+	return &Response{
+		Content:      response.Content[0].Text,
+		FinishReason: response.StopReason,
+		Model:        model,
+		Usage: TokenUsage{
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
+		},
+	}, nil
 }
 
 // completeWithToolsOpenAI handles OpenAI completions with tools
@@ -324,6 +370,7 @@ func (c *Client) completeWithToolsOpenAI(ctx context.Context, prompt string, too
 	}
 
 	var apiResponse struct {
+		Model   string `json:"model"`
 		Choices []struct {
 			Message struct {
 				Content   string `json:"content"`
@@ -353,9 +400,14 @@ func (c *Client) completeWithToolsOpenAI(ctx context.Context, prompt string, too
 	}
 
 	choice := apiResponse.Choices[0]
+	model := apiResponse.Model
+	if model == "" {
+		model = config.Model
+	}
 	response := &Response{
 		Content:      choice.Message.Content,
 		FinishReason: choice.FinishReason,
+		Model:        model,
 		Usage: TokenUsage{
 			PromptTokens:     apiResponse.Usage.PromptTokens,
 			CompletionTokens: apiResponse.Usage.CompletionTokens,
@@ -401,20 +453,11 @@ Arguments: [JSON arguments]
 
 Otherwise, provide your response directly.`, prompt, strings.Join(toolDescriptions, "\n"))
 
-	content, err := c.completeAnthropic(ctx, enhancedPrompt, config)
+	response, err := c.completeAnthropic(ctx, enhancedPrompt, config)
 	if err != nil {
 		return nil, err
 	}
-
-	// Parse for tool calls
-	response := &Response{
-		Content:      content,
-		FinishReason: "stop",
-		Usage: TokenUsage{
-			// Anthropic doesn't provide token usage in the same way
-			TotalTokens: len(content) / 4, // Rough estimate
-		},
-	}
+	content := response.Content
 
 	// Simple parsing for tool calls
 	if strings.Contains(content, "Tool:") && strings.Contains(content, "Arguments:") {
@@ -463,11 +506,12 @@ func (c *Client) streamOpenAI(ctx context.Context, prompt string, config *Config
 	go func() {
 		defer close(ch)
 
-		content, err := c.completeOpenAI(ctx, prompt, config)
+		response, err := c.completeOpenAI(ctx, prompt, config)
 		if err != nil {
 			ch <- fmt.Sprintf("Error: %v", err)
 			return
 		}
+		content := response.Content
 
 		// Simulate streaming by sending chunks
 		words := strings.Fields(content)
@@ -495,11 +539,12 @@ func (c *Client) streamAnthropic(ctx context.Context, prompt string, config *Con
 	go func() {
 		defer close(ch)
 
-		content, err := c.completeAnthropic(ctx, prompt, config)
+		response, err := c.completeAnthropic(ctx, prompt, config)
 		if err != nil {
 			ch <- fmt.Sprintf("Error: %v", err)
 			return
 		}
+		content := response.Content
 
 		// Simulate streaming by sending chunks
 		words := strings.Fields(content)
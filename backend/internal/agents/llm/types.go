@@ -47,6 +47,7 @@ type Response struct {
 	Content      string     `json:"content"`
 	ToolCalls    []ToolCall `json:"tool_calls,omitempty"`
 	FinishReason string     `json:"finish_reason"`
+	Model        string     `json:"model"`
 	Usage        TokenUsage `json:"usage"`
 }
 
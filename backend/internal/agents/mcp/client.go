@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ToolInfo is a tool advertised by a server in tools/list
+type ToolInfo struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// Content is one item of a tool result
+type Content struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Data     string `json:"data,omitempty"`
+	Resource *struct {
+		URI  string `json:"uri"`
+		Text string `json:"text,omitempty"`
+	} `json:"resource,omitempty"`
+}
+
+// CallToolResult is the result of tools/call
+type CallToolResult struct {
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
+}
+
+// ServerInfo identifies a connected server
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Client is a connection to one MCP server
+type Client struct {
+	config     ServerConfig
+	transport  transport
+	ServerInfo ServerInfo
+}
+
+// Connect starts or dials the server and performs the initialize handshake
+func Connect(ctx context.Context, config ServerConfig) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	var t transport
+	var err error
+	switch config.Transport {
+	case TransportStdio:
+		t, err = newStdioTransport(config)
+	case TransportHTTP:
+		t = newHTTPTransport(config)
+	case TransportSSE:
+		t, err = newSSETransport(ctx, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{config: config, transport: t}
+	if err := client.initialize(ctx); err != nil {
+		t.close()
+		return nil, fmt.Errorf("initialize failed: %w", err)
+	}
+	return client, nil
+}
+
+func (c *Client) initialize(ctx context.Context) error {
+	result, err := c.transport.call(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo": map[string]string{
+			"name":    "foundation-sprint",
+			"version": "1.0.0",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		ServerInfo ServerInfo `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(result, &response); err != nil {
+		return fmt.Errorf("invalid initialize result: %w", err)
+	}
+	c.ServerInfo = response.ServerInfo
+
+	return c.transport.notify(ctx, "notifications/initialized", nil)
+}
+
+// Name returns the configured server name
+func (c *Client) Name() string {
+	return c.config.Name
+}
+
+// ListTools returns every tool of the server, following pagination
+func (c *Client) ListTools(ctx context.Context) ([]ToolInfo, error) {
+	var tools []ToolInfo
+	cursor := ""
+	for {
+		params := map[string]interface{}{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		result, err := c.transport.call(ctx, "tools/list", params)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Tools      []ToolInfo `json:"tools"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(result, &page); err != nil {
+			return nil, fmt.Errorf("invalid tools/list result: %w", err)
+		}
+		tools = append(tools, page.Tools...)
+
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool invokes a tool by its server-side name
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*CallToolResult, error) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout())
+	defer cancel()
+
+	result, err := c.transport.call(ctx, "tools/call", map[string]interface{}{
+		"name":      name,
+		"arguments": arguments,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response CallToolResult
+	if err := json.Unmarshal(result, &response); err != nil {
+		return nil, fmt.Errorf("invalid tools/call result: %w", err)
+	}
+	return &response, nil
+}
+
+// Close shuts the connection down
+func (c *Client) Close() error {
+	return c.transport.close()
+}
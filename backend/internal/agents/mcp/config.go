@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Transport names accepted in the config
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+	TransportSSE   = "sse"
+)
+
+// ErrInvalidConfig is wrapped by every MCP server config error
+var ErrInvalidConfig = errors.New("invalid mcp config")
+
+// serverNamePattern keeps namespaced tool names valid as LLM function names
+var serverNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+// ServerConfig describes one MCP server
+type ServerConfig struct {
+	Name           string            `yaml:"name"`
+	Transport      string            `yaml:"transport"` // stdio (default), http or sse
+	Command        string            `yaml:"command"`
+	Args           []string          `yaml:"args"`
+	Env            map[string]string `yaml:"env"`
+	Dir            string            `yaml:"dir"`
+	URL            string            `yaml:"url"`
+	Headers        map[string]string `yaml:"headers"`
+	Agents         []string          `yaml:"agents"` // Agents that get every tool of this server
+	TimeoutSeconds int               `yaml:"timeout_seconds"`
+	Disabled       bool              `yaml:"disabled"`
+}
+
+// Timeout returns the per-call timeout, 30 seconds by default
+func (c ServerConfig) Timeout() time.Duration {
+	if c.TimeoutSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// Validate checks that the transport has what it needs
+func (c *ServerConfig) Validate() error {
+	if !serverNamePattern.MatchString(c.Name) {
+		return fmt.Errorf("%w: server name %q must be 1-32 letters, digits, '_' or '-'", ErrInvalidConfig, c.Name)
+	}
+	if strings.Contains(c.Name, "__") {
+		return fmt.Errorf("%w: server name %q must not contain '__'", ErrInvalidConfig, c.Name)
+	}
+
+	if c.Transport == "" {
+		c.Transport = TransportStdio
+	}
+	switch c.Transport {
+	case TransportStdio:
+		if c.Command == "" {
+			return fmt.Errorf("%w: server %s: command is required for stdio", ErrInvalidConfig, c.Name)
+		}
+	case TransportHTTP, TransportSSE:
+		if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+			return fmt.Errorf("%w: server %s: an http(s) url is required for %s", ErrInvalidConfig, c.Name, c.Transport)
+		}
+	default:
+		return fmt.Errorf("%w: server %s: unknown transport %q", ErrInvalidConfig, c.Name, c.Transport)
+	}
+	return nil
+}
+
+// Config is the MCP servers file
+type Config struct {
+	Servers []ServerConfig `yaml:"servers"`
+}
+
+// LoadConfig reads an MCP servers file (YAML or JSON). ${VAR} references in env and
+// header values are expanded so secrets can stay in the environment.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mcp config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range config.Servers {
+		server := &config.Servers[i]
+		if err := server.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[server.Name] {
+			return nil, fmt.Errorf("%w: duplicate server name %q", ErrInvalidConfig, server.Name)
+		}
+		seen[server.Name] = true
+
+		for key, value := range server.Env {
+			server.Env[key] = os.ExpandEnv(value)
+		}
+		for key, value := range server.Headers {
+			server.Headers[key] = os.ExpandEnv(value)
+		}
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/agents/tools"
+	"log"
+	"sort"
+	"sync"
+)
+
+// Manager owns the connections to the configured servers and their registered tools
+type Manager struct {
+	registry *tools.Registry
+	clients  map[string]*Client
+	tools    map[string][]string // server name -> registered tool names
+	mu       sync.Mutex
+}
+
+// NewManager creates a manager that registers tools in registry
+func NewManager(registry *tools.Registry) *Manager {
+	return &Manager{
+		registry: registry,
+		clients:  make(map[string]*Client),
+		tools:    make(map[string][]string),
+	}
+}
+
+// Start connects to every enabled server and registers its tools. A server that cannot be
+// reached is logged and skipped so one broken integration does not take the agents down.
+func (m *Manager) Start(ctx context.Context, servers []ServerConfig) {
+	for _, server := range servers {
+		if server.Disabled {
+			continue
+		}
+
+		names, err := m.connect(ctx, server)
+		if err != nil {
+			log.Printf("MCP server %s unavailable: %v", server.Name, err)
+			continue
+		}
+		log.Printf("MCP server %s connected with %d tools", server.Name, len(names))
+	}
+}
+
+func (m *Manager) connect(ctx context.Context, server ServerConfig) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, server.Timeout())
+	defer cancel()
+
+	client, err := Connect(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := client.ListTools(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("tools/list failed: %w", err)
+	}
+
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		tool := NewRemoteTool(client, info)
+		if err := m.registry.Register(tool); err != nil {
+			log.Printf("Skipping MCP tool %s: %v", tool.GetName(), err)
+			continue
+		}
+		names = append(names, tool.GetName())
+	}
+	for _, agent := range server.Agents {
+		m.registry.AddAgentTools(agent, names...)
+	}
+
+	m.mu.Lock()
+	m.clients[server.Name] = client
+	m.tools[server.Name] = names
+	m.mu.Unlock()
+
+	return names, nil
+}
+
+// Servers returns the names of the connected servers
+func (m *Manager) Servers() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tools returns the registered tool names of a server
+func (m *Manager) Tools(server string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.tools[server]...)
+}
+
+// Close disconnects every server
+func (m *Manager) Close() {
+	m.mu.Lock()
+	clients := m.clients
+	m.clients = make(map[string]*Client)
+	m.mu.Unlock()
+
+	for name, client := range clients {
+		if err := client.Close(); err != nil {
+			log.Printf("Failed to close MCP server %s: %v", name, err)
+		}
+	}
+}
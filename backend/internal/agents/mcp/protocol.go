@@ -0,0 +1,196 @@
+// Package mcp connects agents to external Model Context Protocol tool servers.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ProtocolVersion is the MCP revision this client speaks
+const ProtocolVersion = "2025-03-26"
+
+// JSON-RPC error codes used when answering server requests
+const (
+	codeMethodNotFound = -32601
+)
+
+// ErrClosed is returned for calls on a closed connection
+var ErrClosed = errors.New("mcp connection closed")
+
+// message is a JSON-RPC 2.0 request, notification or response
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  interface{}      `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *RPCError        `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC error returned by a server
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+// transport carries JSON-RPC calls to one server
+type transport interface {
+	call(ctx context.Context, method string, params interface{}) (json.RawMessage, error)
+	notify(ctx context.Context, method string, params interface{}) error
+	close() error
+}
+
+// rpcConn matches responses to pending calls for transports that receive messages asynchronously
+type rpcConn struct {
+	nextID  atomic.Int64
+	send    func(ctx context.Context, data []byte) error
+	pending map[int64]chan *message
+	done    chan struct{}
+	err     error
+	mu      sync.Mutex
+}
+
+func newRPCConn(send func(ctx context.Context, data []byte) error) *rpcConn {
+	return &rpcConn{
+		send:    send,
+		pending: make(map[int64]chan *message),
+		done:    make(chan struct{}),
+	}
+}
+
+// newRequest encodes a request with a fresh ID
+func (c *rpcConn) newRequest(method string, params interface{}) (int64, []byte, error) {
+	id := c.nextID.Add(1)
+	rawID := json.RawMessage(fmt.Sprintf("%d", id))
+	data, err := json.Marshal(message{JSONRPC: "2.0", ID: &rawID, Method: method, Params: params})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+	return id, data, nil
+}
+
+func (c *rpcConn) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	id, data, err := c.newRequest(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *message, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.send(ctx, data); err != nil {
+		return nil, err
+	}
+
+	select {
+	case response := <-ch:
+		if response.Error != nil {
+			return nil, response.Error
+		}
+		return response.Result, nil
+	case <-c.done:
+		return nil, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *rpcConn) notify(ctx context.Context, method string, params interface{}) error {
+	data, err := json.Marshal(message{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s notification: %w", method, err)
+	}
+	return c.send(ctx, data)
+}
+
+// dispatch handles one message received from the server
+func (c *rpcConn) dispatch(data []byte) {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+
+	// Requests from the server: answer ping, refuse everything else
+	if msg.Method != "" {
+		if msg.ID != nil {
+			go c.reply(msg)
+		}
+		return
+	}
+
+	if msg.ID == nil {
+		return
+	}
+	var id int64
+	if err := json.Unmarshal(*msg.ID, &id); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	ch, ok := c.pending[id]
+	c.mu.Unlock()
+	if ok {
+		ch <- &msg
+	}
+}
+
+// reply answers a request sent by the server
+func (c *rpcConn) reply(request message) {
+	response := message{JSONRPC: "2.0", ID: request.ID}
+	if request.Method == "ping" {
+		response.Result = json.RawMessage("{}")
+	} else {
+		response.Error = &RPCError{Code: codeMethodNotFound, Message: "method not supported by client: " + request.Method}
+	}
+
+	if data, err := json.Marshal(response); err == nil {
+		c.send(context.Background(), data)
+	}
+}
+
+// fail closes the connection and fails every pending call with err
+func (c *rpcConn) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	if err == nil {
+		err = ErrClosed
+	}
+	c.err = err
+	close(c.done)
+}
+
+// decodeResponse decodes a single synchronous JSON-RPC response
+func decodeResponse(data []byte) (json.RawMessage, error) {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("invalid mcp response: %w", err)
+	}
+	if msg.Error != nil {
+		return nil, msg.Error
+	}
+	return msg.Result, nil
+}
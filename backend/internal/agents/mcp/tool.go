@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/agents/tools"
+	"regexp"
+	"strings"
+)
+
+// NamespaceSeparator joins the server name and the tool name
+const NamespaceSeparator = "__"
+
+// invalidToolChars are not allowed in LLM function names
+var invalidToolChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// RemoteTool exposes a server tool through the tools registry, proxying Execute to tools/call
+type RemoteTool struct {
+	client *Client
+	info   ToolInfo
+	name   string
+}
+
+// NewRemoteTool wraps a tool advertised by client
+func NewRemoteTool(client *Client, info ToolInfo) *RemoteTool {
+	name := client.Name() + NamespaceSeparator + invalidToolChars.ReplaceAllString(info.Name, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return &RemoteTool{client: client, info: info, name: name}
+}
+
+// GetName returns the namespaced tool name
+func (t *RemoteTool) GetName() string {
+	return t.name
+}
+
+// GetDescription returns the server's description of the tool
+func (t *RemoteTool) GetDescription() string {
+	description := strings.TrimSpace(t.info.Description)
+	if description == "" {
+		description = t.info.Name
+	}
+	return fmt.Sprintf("[%s] %s", t.client.Name(), description)
+}
+
+// InputSchema returns the tool's JSON Schema
+func (t *RemoteTool) InputSchema() map[string]interface{} {
+	if t.info.InputSchema == nil {
+		return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	return t.info.InputSchema
+}
+
+// ValidateInput checks the schema's required properties
+func (t *RemoteTool) ValidateInput(input map[string]interface{}) error {
+	for _, field := range tools.SchemaRequired(t.InputSchema()) {
+		if _, exists := input[field]; !exists {
+			return fmt.Errorf("required field '%s' is missing", field)
+		}
+	}
+	return nil
+}
+
+// Execute calls the tool on the server
+func (t *RemoteTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	result, err := t.client.CallTool(ctx, t.info.Name, input)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.name, err)
+	}
+
+	text := contentText(result.Content)
+	if result.IsError {
+		return nil, fmt.Errorf("%s failed: %s", t.name, text)
+	}
+	if result.StructuredContent != nil {
+		return result.StructuredContent, nil
+	}
+	return text, nil
+}
+
+// contentText flattens tool result content into text for the agent
+func contentText(content []Content) string {
+	parts := make([]string, 0, len(content))
+	for _, item := range content {
+		switch item.Type {
+		case "text":
+			parts = append(parts, item.Text)
+		case "resource":
+			if item.Resource != nil {
+				if item.Resource.Text != "" {
+					parts = append(parts, item.Resource.Text)
+				} else {
+					parts = append(parts, fmt.Sprintf("[resource %s]", item.Resource.URI))
+				}
+			}
+		default:
+			parts = append(parts, fmt.Sprintf("[%s content omitted]", firstNonEmpty(item.MimeType, item.Type)))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,394 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxMessageSize bounds a single message read from a server
+const maxMessageSize = 16 << 20
+
+// stdioTransport runs a server as a child process speaking newline-delimited JSON-RPC on stdin/stdout
+type stdioTransport struct {
+	*rpcConn
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	writeMu sync.Mutex
+	exited  chan struct{}
+}
+
+func newStdioTransport(cfg ServerConfig) (*stdioTransport, error) {
+	cmd := exec.Command(cfg.Command, cfg.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range cfg.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	if cfg.Dir != "" {
+		cmd.Dir = cfg.Dir
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdout: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stderr: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", cfg.Command, err)
+	}
+
+	t := &stdioTransport{cmd: cmd, stdin: stdin, exited: make(chan struct{})}
+	t.rpcConn = newRPCConn(t.write)
+
+	// Servers log to stderr; keep it in our log so failures are diagnosable
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Printf("[mcp:%s] %s", cfg.Name, scanner.Text())
+		}
+	}()
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+		for scanner.Scan() {
+			if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+				t.dispatch(append([]byte(nil), line...))
+			}
+		}
+		err := cmd.Wait()
+		if err == nil {
+			err = fmt.Errorf("%w: server process exited", ErrClosed)
+		} else {
+			err = fmt.Errorf("%w: server process exited: %v", ErrClosed, err)
+		}
+		t.fail(err)
+		close(t.exited)
+	}()
+
+	return t, nil
+}
+
+func (t *stdioTransport) write(_ context.Context, data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to server: %w", err)
+	}
+	return nil
+}
+
+// close asks the server to exit by closing stdin and kills it if it does not
+func (t *stdioTransport) close() error {
+	t.stdin.Close()
+	select {
+	case <-t.exited:
+	case <-time.After(3 * time.Second):
+		t.cmd.Process.Kill()
+		<-t.exited
+	}
+	return nil
+}
+
+// httpTransport implements the streamable HTTP transport: every message is POSTed to one endpoint
+// and the response comes back as JSON or as an SSE stream
+type httpTransport struct {
+	endpoint   string
+	headers    map[string]string
+	httpClient *http.Client
+	nextID     int64
+	sessionID  string
+	mu         sync.Mutex
+}
+
+func newHTTPTransport(cfg ServerConfig) *httpTransport {
+	return &httpTransport{
+		endpoint:   cfg.URL,
+		headers:    cfg.Headers,
+		httpClient: &http.Client{},
+	}
+}
+
+func (t *httpTransport) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	t.mu.Lock()
+	t.nextID++
+	id := t.nextID
+	t.mu.Unlock()
+
+	rawID := json.RawMessage(fmt.Sprintf("%d", id))
+	body, err := json.Marshal(message{JSONRPC: "2.0", ID: &rawID, Method: method, Params: params})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	resp, err := t.post(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if sessionID := resp.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		t.mu.Lock()
+		t.sessionID = sessionID
+		t.mu.Unlock()
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var result json.RawMessage
+		var resultErr error
+		found := false
+		err := readSSE(resp.Body, func(event, data string) bool {
+			var msg message
+			if json.Unmarshal([]byte(data), &msg) != nil || msg.ID == nil || string(*msg.ID) != string(rawID) {
+				return true
+			}
+			found = true
+			result, resultErr = decodeResponse([]byte(data))
+			return false
+		})
+		if !found {
+			if err == nil {
+				err = fmt.Errorf("stream ended without a response to %s", method)
+			}
+			return nil, err
+		}
+		return result, resultErr
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return decodeResponse(data)
+}
+
+func (t *httpTransport) notify(ctx context.Context, method string, params interface{}) error {
+	body, err := json.Marshal(message{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s notification: %w", method, err)
+	}
+	resp, err := t.post(ctx, body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// post sends one JSON-RPC message and checks the status code
+func (t *httpTransport) post(ctx context.Context, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(req)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("mcp server error (status %d): %s", resp.StatusCode, string(data))
+	}
+	return resp, nil
+}
+
+func (t *httpTransport) setHeaders(req *http.Request) {
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	t.mu.Lock()
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+	t.mu.Unlock()
+}
+
+// close ends the server session
+func (t *httpTransport) close() error {
+	t.mu.Lock()
+	sessionID := t.sessionID
+	t.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "DELETE", t.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req)
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// sseTransport implements the older HTTP+SSE transport: responses arrive on a long-lived
+// event stream and requests are POSTed to the endpoint announced by that stream
+type sseTransport struct {
+	*rpcConn
+	headers    map[string]string
+	httpClient *http.Client
+	endpoint   chan string
+	postURL    string
+	cancel     context.CancelFunc
+}
+
+func newSSETransport(ctx context.Context, cfg ServerConfig) (*sseTransport, error) {
+	streamURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	t := &sseTransport{
+		headers:    cfg.Headers,
+		httpClient: &http.Client{},
+		endpoint:   make(chan string, 1),
+		cancel:     cancel,
+	}
+	t.rpcConn = newRPCConn(t.postMessage)
+
+	req, err := http.NewRequestWithContext(streamCtx, "GET", cfg.URL, nil)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to open event stream: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("failed to open event stream: status %d", resp.StatusCode)
+	}
+
+	go func() {
+		defer resp.Body.Close()
+		err := readSSE(resp.Body, func(event, data string) bool {
+			switch event {
+			case "endpoint":
+				if endpoint, err := streamURL.Parse(strings.TrimSpace(data)); err == nil {
+					select {
+					case t.endpoint <- endpoint.String():
+					default:
+					}
+				}
+			case "", "message":
+				t.dispatch([]byte(data))
+			}
+			return true
+		})
+		if err == nil {
+			err = fmt.Errorf("%w: event stream ended", ErrClosed)
+		}
+		t.fail(err)
+	}()
+
+	// The server announces where to POST before anything else
+	select {
+	case t.postURL = <-t.endpoint:
+	case <-t.done:
+		cancel()
+		return nil, t.err
+	case <-ctx.Done():
+		cancel()
+		return nil, fmt.Errorf("server did not announce its endpoint: %w", ctx.Err())
+	}
+
+	return t, nil
+}
+
+func (t *sseTransport) postMessage(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", t.postURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("mcp server error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func (t *sseTransport) close() error {
+	t.cancel()
+	t.fail(ErrClosed)
+	return nil
+}
+
+// readSSE parses a server-sent event stream, calling fn for each event until fn returns false
+func readSSE(r io.Reader, fn func(event, data string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+
+	event := ""
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if !fn(event, strings.Join(data, "\n")) {
+					return nil
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// Comment, used as keep-alive
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if len(data) > 0 {
+		fn(event, strings.Join(data, "\n"))
+	}
+	return scanner.Err()
+}
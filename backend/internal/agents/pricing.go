@@ -0,0 +1,110 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// FallbackPriceKey is the price table entry charged for models without a price of their own
+const FallbackPriceKey = "*"
+
+// datedSuffix matches the snapshot suffix providers append to a model name, such as
+// "2024-08-06", "20240229", "0613", "1106-preview" or "latest"
+var datedSuffix = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}|\d{8}|\d{4}|latest)(-preview)?$`)
+
+// ModelPrice is the price of a model in USD per one million tokens
+type ModelPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// PriceTable maps model names (or name prefixes) to their prices
+type PriceTable map[string]ModelPrice
+
+// DefaultPriceTable returns list prices for the models the LLM client defaults to
+func DefaultPriceTable() PriceTable {
+	return PriceTable{
+		"gpt-4.1":           {Input: 2.00, Output: 8.00},
+		"gpt-4.1-mini":      {Input: 0.40, Output: 1.60},
+		"gpt-4.1-nano":      {Input: 0.10, Output: 0.40},
+		"gpt-4o":            {Input: 2.50, Output: 10.00},
+		"gpt-4o-mini":       {Input: 0.15, Output: 0.60},
+		"gpt-4-turbo":       {Input: 10.00, Output: 30.00},
+		"gpt-4":             {Input: 30.00, Output: 60.00},
+		"gpt-3.5-turbo":     {Input: 0.50, Output: 1.50},
+		"o1":                {Input: 15.00, Output: 60.00},
+		"o1-mini":           {Input: 1.10, Output: 4.40},
+		"o3-mini":           {Input: 1.10, Output: 4.40},
+		"claude-3-opus":     {Input: 15.00, Output: 75.00},
+		"claude-3-7-sonnet": {Input: 3.00, Output: 15.00},
+		"claude-3-5-sonnet": {Input: 3.00, Output: 15.00},
+		"claude-3-5-haiku":  {Input: 0.80, Output: 4.00},
+		"claude-3-sonnet":   {Input: 3.00, Output: 15.00},
+		"claude-3-haiku":    {Input: 0.25, Output: 1.25},
+	}
+}
+
+// LoadPriceTable returns the default price table extended by the JSON file in LLM_PRICING_FILE.
+// The file maps model names to {"input": ..., "output": ...} in USD per million tokens; a "*" entry
+// is charged for models that have no price of their own.
+func LoadPriceTable() (PriceTable, error) {
+	table := DefaultPriceTable()
+
+	path := os.Getenv("LLM_PRICING_FILE")
+	if path == "" {
+		return table, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing file: %w", err)
+	}
+
+	var overrides PriceTable
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing file: %w", err)
+	}
+
+	for model, price := range overrides {
+		table[model] = price
+	}
+
+	return table, nil
+}
+
+// Lookup finds the price for a model. Besides exact names it accepts a priced name followed by a
+// dated snapshot suffix, so "gpt-4o-2024-08-06" resolves to "gpt-4o" but "gpt-4.1" and
+// "gpt-4o-mini" never resolve to "gpt-4" or "gpt-4o".
+func (p PriceTable) Lookup(model string) (ModelPrice, bool) {
+	if model == FallbackPriceKey {
+		return ModelPrice{}, false
+	}
+	if price, ok := p[model]; ok {
+		return price, true
+	}
+
+	for name, price := range p {
+		if name == FallbackPriceKey || !strings.HasPrefix(model, name+"-") {
+			continue
+		}
+		if datedSuffix.MatchString(strings.TrimPrefix(model, name+"-")) {
+			return price, true
+		}
+	}
+
+	return ModelPrice{}, false
+}
+
+// Cost calculates the USD cost of a call and reports whether the model has a price. Unpriced
+// models are charged the "*" entry when the table has one, and nothing otherwise.
+func (p PriceTable) Cost(model string, usage TokenUsage) (float64, bool) {
+	price, ok := p.Lookup(model)
+	if !ok {
+		price = p[FallbackPriceKey]
+	}
+
+	return (float64(usage.PromptTokens)*price.Input + float64(usage.CompletionTokens)*price.Output) / 1_000_000, ok
+}
@@ -0,0 +1,114 @@
+package agents
+
+import (
+	"context"
+	"foundation-sprint/internal/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPriceTableLookup(t *testing.T) {
+	prices := DefaultPriceTable()
+	tests := []struct {
+		model string
+		want  string // price table entry, "" when the model is unpriced
+	}{
+		{"gpt-4o", "gpt-4o"},
+		{"gpt-4o-2024-08-06", "gpt-4o"},
+		{"gpt-4o-mini", "gpt-4o-mini"},
+		{"gpt-4o-mini-2024-07-18", "gpt-4o-mini"},
+		{"gpt-4", "gpt-4"},
+		{"gpt-4-0613", "gpt-4"},
+		{"gpt-4-1106-preview", "gpt-4"},
+		{"gpt-4-turbo-2024-04-09", "gpt-4-turbo"},
+		{"gpt-4.1", "gpt-4.1"},
+		{"gpt-4.1-mini-2025-04-14", "gpt-4.1-mini"},
+		{"gpt-3.5-turbo-0125", "gpt-3.5-turbo"},
+		{"claude-3-opus-20240229", "claude-3-opus"},
+		{"claude-3-5-sonnet-latest", "claude-3-5-sonnet"},
+		{"claude-3-5-haiku-20241022", "claude-3-5-haiku"},
+		{"claude-3-7-sonnet-20250219", "claude-3-7-sonnet"},
+		{"o1", "o1"},
+		{"o1-2024-12-17", "o1"},
+		{"gpt-4o-audio-preview", ""},
+		{"gpt-4-vision", ""},
+		{"gpt-5", ""},
+		{"claude-opus-4", ""},
+		{"", ""},
+		{FallbackPriceKey, ""},
+	}
+	for _, tt := range tests {
+		price, ok := prices.Lookup(tt.model)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("Lookup(%q) = %+v, want unpriced", tt.model, price)
+			}
+			continue
+		}
+		if !ok || price != prices[tt.want] {
+			t.Errorf("Lookup(%q) = %+v %v, want the %s price %+v", tt.model, price, ok, tt.want, prices[tt.want])
+		}
+	}
+}
+
+func TestPriceTableCost(t *testing.T) {
+	usage := TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 500_000}
+	prices := PriceTable{"gpt-4o": {Input: 2.5, Output: 10}}
+
+	if cost, priced := prices.Cost("gpt-4o-2024-08-06", usage); !priced || math.Abs(cost-7.5) > 1e-9 {
+		t.Errorf("priced model: cost %v priced %v, want 7.5 and true", cost, priced)
+	}
+	if cost, priced := prices.Cost("gpt-5", usage); priced || cost != 0 {
+		t.Errorf("unpriced model without fallback: cost %v priced %v, want 0 and false", cost, priced)
+	}
+
+	prices[FallbackPriceKey] = ModelPrice{Input: 10, Output: 40}
+	if cost, priced := prices.Cost("gpt-5", usage); priced || math.Abs(cost-30) > 1e-9 {
+		t.Errorf("unpriced model with fallback: cost %v priced %v, want 30 and false", cost, priced)
+	}
+}
+
+// memoryUsageStore keeps usage records in memory
+type memoryUsageStore struct {
+	records []*models.LLMUsage
+}
+
+func (s *memoryUsageStore) Create(ctx context.Context, usage *models.LLMUsage) error {
+	s.records = append(s.records, usage)
+	return nil
+}
+
+func (s *memoryUsageStore) TotalCost(ctx context.Context, roomID string, since time.Time) (float64, error) {
+	total := 0.0
+	for _, record := range s.records {
+		if roomID == "" || record.RoomID == roomID {
+			total += record.CostUSD
+		}
+	}
+	return total, nil
+}
+
+func (s *memoryUsageStore) GetBudget(ctx context.Context, scope, scopeID string) (float64, bool, error) {
+	return 0, false, nil
+}
+
+func TestUsageTrackerFlagsUnpricedModels(t *testing.T) {
+	store := &memoryUsageStore{}
+	tracker := NewUsageTracker(DefaultPriceTable(), BudgetConfig{})
+	tracker.SetStore(store)
+
+	usage := TokenUsage{PromptTokens: 1000, CompletionTokens: 1000}
+	tracker.Record(context.Background(), "gpt-4o", usage)
+	tracker.Record(context.Background(), "gpt-5", usage)
+
+	if len(store.records) != 2 {
+		t.Fatalf("recorded %d calls, want 2", len(store.records))
+	}
+	if priced := store.records[0]; priced.Unpriced || priced.CostUSD == 0 {
+		t.Errorf("gpt-4o record = %+v, want a priced cost", priced)
+	}
+	if unpriced := store.records[1]; !unpriced.Unpriced || unpriced.CostUSD != 0 {
+		t.Errorf("gpt-5 record = %+v, want unpriced with no cost", unpriced)
+	}
+}
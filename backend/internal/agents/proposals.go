@@ -0,0 +1,86 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"foundation-sprint/internal/models"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// ProposalStore persists agent proposals
+type ProposalStore interface {
+	Create(ctx context.Context, proposal *models.Proposal) error
+}
+
+// proposalBlockPattern matches a fenced ```proposals block in an agent answer
+var proposalBlockPattern = regexp.MustCompile("(?s)```proposals\\s*\\n(.*?)```")
+
+// proposalInstructions tells the agent how to emit structured proposals for a room
+const proposalInstructions = `
+
+If your answer recommends concrete additions to the sprint board, append them at the end of your
+final answer as a fenced block (at most 5 items, only when you are confident they help):
+` + "```proposals" + `
+[
+  {"type": "add_customer", "text": "...", "rationale": "..."},
+  {"type": "add_problem", "text": "...", "rationale": "..."},
+  {"type": "add_factor", "factor": {"name": "...", "description": "..."}, "rationale": "..."},
+  {"type": "position_product", "product": {"name": "Competitor", "x": 0-100, "y": 0-100, "is_us": false}, "rationale": "..."},
+  {"type": "add_path", "path": {"name": "...", "description": "...", "pros": ["..."], "cons": ["..."]}, "rationale": "..."}
+]
+` + "```"
+
+// proposalDraft is a proposal as written by the LLM
+type proposalDraft struct {
+	Type      string `json:"type"`
+	Rationale string `json:"rationale"`
+	models.ProposalPayload
+}
+
+// ExtractProposals removes the proposals block from an answer and parses its valid entries.
+// Malformed blocks and invalid entries are dropped so they never reach the user as raw JSON.
+func ExtractProposals(response, roomID, agentName string) (string, []*models.Proposal) {
+	match := proposalBlockPattern.FindStringSubmatchIndex(response)
+	if match == nil {
+		return response, nil
+	}
+
+	block := response[match[2]:match[3]]
+	cleaned := strings.TrimSpace(response[:match[0]] + response[match[1]:])
+
+	var drafts []proposalDraft
+	if err := json.Unmarshal([]byte(block), &drafts); err != nil {
+		log.Printf("Failed to parse proposals from %s: %v", agentName, err)
+		return cleaned, nil
+	}
+
+	proposals := make([]*models.Proposal, 0, len(drafts))
+	for _, draft := range drafts {
+		proposal := models.NewProposal(roomID, draft.Type, agentName)
+		proposal.Rationale = draft.Rationale
+		proposal.ProposalPayload = draft.ProposalPayload
+		if err := proposal.Validate(); err != nil {
+			log.Printf("Dropping invalid proposal from %s: %v", agentName, err)
+			continue
+		}
+		proposals = append(proposals, proposal)
+	}
+
+	return cleaned, proposals
+}
+
+// attachProposals moves the proposals in the response into output.Proposals; only room-bound requests get proposals
+func attachProposals(output *AgentOutput, input AgentInput, agentName string) {
+	if output == nil || input.RoomID == "" {
+		return
+	}
+
+	response, proposals := ExtractProposals(output.Response, input.RoomID, agentName)
+	output.Response = response
+	for _, proposal := range proposals {
+		proposal.RequestedBy = input.UserID
+	}
+	output.Proposals = append(output.Proposals, proposals...)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"foundation-sprint/internal/agents/tools"
 	"strings"
 	"time"
 )
@@ -21,6 +22,8 @@ type ReActProcessor struct {
 	llmClient     LLMClient
 	tools         map[string]Tool
 	maxIterations int
+	model         string
+	temperature   float64
 }
 
 // NewReActProcessor creates a new ReAct processor
@@ -39,9 +42,26 @@ func NewReActProcessor(agent Agent, llmClient LLMClient, tools []Tool, maxIterat
 		llmClient:     llmClient,
 		tools:         toolMap,
 		maxIterations: maxIterations,
+		temperature:   0.7,
 	}
 }
 
+// SetModelSettings overrides the model and temperature used for thoughts and final answers.
+// An empty model keeps the client's default.
+func (r *ReActProcessor) SetModelSettings(model string, temperature float64) {
+	r.model = model
+	r.temperature = temperature
+}
+
+// llmOptions returns the model options shared by every call of this processor
+func (r *ReActProcessor) llmOptions(temperature float64, options ...LLMOption) []LLMOption {
+	options = append(options, WithTemperature(temperature))
+	if r.model != "" {
+		options = append(options, WithModel(r.model))
+	}
+	return options
+}
+
 // Process executes the ReAct loop
 func (r *ReActProcessor) Process(ctx context.Context, input AgentInput) (*AgentOutput, error) {
 	output := &AgentOutput{
@@ -52,6 +72,14 @@ func (r *ReActProcessor) Process(ctx context.Context, input AgentInput) (*AgentO
 		Metadata:    make(map[string]interface{}),
 	}
 	
+	// Attribute every LLM call in this run
+	ctx = WithUsageScope(ctx, UsageScope{
+		RoomID:    input.RoomID,
+		UserID:    input.UserID,
+		AgentName: r.agent.GetName(),
+	})
+	ctx = withToolRoomContext(ctx, input)
+	
 	// Build the initial prompt
 	systemPrompt := r.buildSystemPrompt()
 	userPrompt := r.buildUserPrompt(input)
@@ -73,17 +101,18 @@ func (r *ReActProcessor) Process(ctx context.Context, input AgentInput) (*AgentO
 		fmt.Printf("=== REACT STEP %d ===\n", i+1)
 		fmt.Printf("Prompt: %s\n", thoughtPrompt[:min(200, len(thoughtPrompt))])
 		response, err := r.llmClient.CompleteWithTools(
-			ctx,
+			withCallType(ctx, "thought"),
 			thoughtPrompt,
 			r.getToolList(),
-			WithSystemPrompt(systemPrompt),
-			WithTemperature(0.7),
-			WithMaxTokens(1000),
+			r.llmOptions(r.temperature,
+				WithSystemPrompt(systemPrompt),
+				WithMaxTokens(1000))...,
 		)
 		if err != nil {
 			fmt.Printf("ERROR calling LLM: %v\n", err)
 			return nil, fmt.Errorf("failed to generate thought: %w", err)
 		}
+		addUsage(&output.Usage, response.Usage)
 		fmt.Printf("LLM Response: %s\n", response.Content[:min(200, len(response.Content))])
 		
 		// Parse the response for thought and action
@@ -106,18 +135,19 @@ func (r *ReActProcessor) Process(ctx context.Context, input AgentInput) (*AgentO
 			step.Observation = observation
 			if toolExec != nil {
 				output.Tools = append(output.Tools, *toolExec)
+				addToolReferences(output, toolExec)
 			}
 			
 			// Generate reflection on the observation
 			reflectionPrompt := r.buildReflectionPrompt(step)
 			reflection, err := r.llmClient.Complete(
-				ctx,
+				withCallType(ctx, "reflection"),
 				reflectionPrompt,
-				WithTemperature(0.5),
-				WithMaxTokens(200),
+				r.llmOptions(0.5, WithMaxTokens(200))...,
 			)
 			if err == nil {
-				step.Reflection = reflection
+				step.Reflection = reflection.Content
+				addUsage(&output.Usage, reflection.Usage)
 			}
 		}
 		
@@ -144,6 +174,9 @@ func (r *ReActProcessor) Process(ctx context.Context, input AgentInput) (*AgentO
 		output.Confidence = r.calculateConfidence(output)
 	}
 	
+	// Move structured proposals out of the answer
+	attachProposals(output, input, r.agent.GetName())
+	
 	// Extract suggestions and next actions
 	output.Suggestions = r.extractSuggestions(output)
 	output.NextActions = r.extractNextActions(input, output)
@@ -155,8 +188,14 @@ func (r *ReActProcessor) Process(ctx context.Context, input AgentInput) (*AgentO
 func (r *ReActProcessor) buildSystemPrompt() string {
 	toolDescriptions := []string{}
 	for name, tool := range r.tools {
-		toolDescriptions = append(toolDescriptions, 
-			fmt.Sprintf("- %s: %s", name, tool.GetDescription()))
+		description := fmt.Sprintf("- %s: %s", name, tool.GetDescription())
+		// Tools with a schema take a JSON object as Action Input
+		if provider, ok := tool.(tools.SchemaProvider); ok {
+			if schema, err := json.Marshal(provider.InputSchema()); err == nil {
+				description += fmt.Sprintf("\n  Input (JSON): %s", schema)
+			}
+		}
+		toolDescriptions = append(toolDescriptions, description)
 	}
 	
 	return fmt.Sprintf(`You are %s, an AI agent with the following characteristics:
@@ -204,10 +243,27 @@ func (r *ReActProcessor) buildUserPrompt(input AgentInput) string {
 		phaseInfo = fmt.Sprintf("\nCurrent Phase: %s", input.Phase)
 	}
 	
-	return fmt.Sprintf(`Query: %s%s%s
+	roomInfo := ""
+	if input.RoomContext != "" {
+		roomInfo = fmt.Sprintf("\n\nSprint Room State:\n%s", input.RoomContext)
+	}
+	
+	dataInfo := ""
+	if len(input.Data) > 0 {
+		if data, err := json.MarshalIndent(input.Data, "", "  "); err == nil {
+			dataInfo = fmt.Sprintf("\n\nAdditional Data:\n%s", data)
+		}
+	}
+	
+	proposalInfo := ""
+	if input.RoomID != "" {
+		proposalInfo = proposalInstructions
+	}
+	
+	return fmt.Sprintf(`Query: %s%s%s%s%s
 
-Please help with this request using your expertise and available tools.`,
-		input.Query, contextInfo, phaseInfo)
+Please help with this request using your expertise and available tools.%s`,
+		input.Query, contextInfo, phaseInfo, roomInfo, dataInfo, proposalInfo)
 }
 
 // buildThoughtPrompt creates a prompt for generating the next thought
@@ -263,6 +319,14 @@ func (r *ReActProcessor) parseReActResponse(response string) (thought, action, a
 	return
 }
 
+// withToolRoomContext passes the room state, or the client-supplied context, to the tools run with ctx
+func withToolRoomContext(ctx context.Context, input AgentInput) context.Context {
+	if input.RoomContext != "" {
+		return tools.WithRoomContext(ctx, input.RoomContext)
+	}
+	return tools.WithRoomContext(ctx, input.Context)
+}
+
 // executeTool executes a tool and returns the observation
 func (r *ReActProcessor) executeTool(ctx context.Context, toolName, input string) (string, *ToolExecution) {
 	tool, exists := r.tools[toolName]
@@ -387,22 +451,25 @@ func (r *ReActProcessor) generateFinalAnswer(ctx context.Context, input AgentInp
 
 Query: %s
 Context: %s
-
+%s
 Key Findings:
 %s
 
 Provide a clear, actionable response that directly addresses the query.`,
-		input.Query, input.Context, strings.Join(findings, "\n"))
+		input.Query, input.Context, input.RoomContext, strings.Join(findings, "\n"))
+	if input.RoomID != "" {
+		prompt += proposalInstructions
+	}
 	
-	response, err := r.llmClient.Complete(ctx, prompt,
-		WithTemperature(0.7),
-		WithMaxTokens(500))
+	response, err := r.llmClient.Complete(withCallType(ctx, "final_answer"), prompt,
+		r.llmOptions(r.temperature, WithMaxTokens(500))...)
 	
 	if err != nil {
 		return r.synthesizeResponse(ctx, input, output)
 	}
+	addUsage(&output.Usage, response.Usage)
 	
-	return response
+	return response.Content
 }
 
 // generateDefaultResponse creates a helpful default response based on agent type
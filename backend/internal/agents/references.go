@@ -0,0 +1,63 @@
+package agents
+
+import (
+	"foundation-sprint/internal/agents/tools"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/models"
+)
+
+// addToolReferences records the sources found by a tool execution as output references, skipping duplicate URLs
+func addToolReferences(output *AgentOutput, execution *ToolExecution) {
+	if execution == nil || !execution.Success {
+		return
+	}
+
+	var found []Reference
+	switch result := execution.Output.(type) {
+	case *tools.SearchResponse:
+		for _, hit := range result.Results {
+			found = append(found, Reference{
+				Type:   "website",
+				Title:  hit.Title,
+				URL:    hit.URL,
+				Author: hit.Source,
+				Date:   hit.Published,
+				Quote:  hit.Snippet,
+			})
+		}
+	case *knowledge.SearchResponse:
+		for _, hit := range result.Results {
+			referenceType := "document"
+			if hit.SourceType == models.KnowledgeSourceRoom {
+				referenceType = "sprint"
+			}
+			found = append(found, Reference{
+				Type:  referenceType,
+				Title: hit.Title,
+				URL:   hit.URI,
+				Quote: hit.Snippet,
+			})
+		}
+	case *tools.Page:
+		found = append(found, Reference{
+			Type:   "article",
+			Title:  result.Title,
+			URL:    result.FinalURL,
+			Author: result.Author,
+			Date:   result.Published,
+			Quote:  result.Description,
+		})
+	}
+
+	seen := make(map[string]bool, len(output.References))
+	for _, reference := range output.References {
+		seen[reference.URL] = true
+	}
+	for _, reference := range found {
+		if seen[reference.URL] {
+			continue
+		}
+		seen[reference.URL] = true
+		output.References = append(output.References, reference)
+	}
+}
@@ -192,21 +192,5 @@ func (a *ResearchAgent) enhanceOutput(output *AgentOutput, input AgentInput) *Ag
 		"relevance":    "聚焦于决策相关信息",
 	}
 	
-	// Add references if available
-	output.References = append(output.References,
-		Reference{
-			Type:  "report",
-			Title: "行业研究报告",
-			Date:  "2024",
-			Quote: "需要查找具体行业的最新研究报告",
-		},
-		Reference{
-			Type:  "article",
-			Title: "市场趋势分析",
-			Date:  "2024",
-			Quote: "关注最新的市场动态和用户行为变化",
-		},
-	)
-	
 	return output
 }
\ No newline at end of file
@@ -0,0 +1,118 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"foundation-sprint/internal/agents/tools"
+	"foundation-sprint/internal/models"
+	"log"
+	"strings"
+)
+
+// RiskStore persists the room risk register
+type RiskStore interface {
+	Create(ctx context.Context, risk *models.Risk) error
+	GetByRoom(ctx context.Context, roomID string, status string) ([]*models.Risk, error)
+}
+
+// SetRiskStore sets the store that receives the risks scored by risk_assessor
+func (s *Service) SetRiskStore(store RiskStore) {
+	s.risks = store
+}
+
+// SaveRisks records the risks an agent scored with risk_assessor in the room's risk register and
+// lists them in output.Risks. The generic checklist is not recorded, and risks whose title is
+// already in the register are skipped so repeated critiques do not pile up duplicates.
+func (s *Service) SaveRisks(ctx context.Context, roomID, agentName string, output *AgentOutput) {
+	if s.risks == nil || roomID == "" || output == nil {
+		return
+	}
+
+	assessed := assessedRisks(output.Tools)
+	if len(assessed) == 0 {
+		return
+	}
+
+	existing, err := s.risks.GetByRoom(ctx, roomID, "")
+	if err != nil {
+		log.Printf("Failed to load risk register of room %s: %v", roomID, err)
+		return
+	}
+	known := make(map[string]bool, len(existing))
+	for _, risk := range existing {
+		known[strings.ToLower(risk.Title)] = true
+	}
+
+	paths := s.roomPathIDs(ctx, roomID)
+	for _, item := range assessed {
+		title := strings.TrimSpace(item.Risk)
+		if known[strings.ToLower(title)] {
+			continue
+		}
+
+		risk := models.NewRisk(roomID, title)
+		risk.Category = item.Category
+		risk.Likelihood = item.LikelihoodScore
+		risk.Impact = item.ImpactScore
+		risk.Mitigation = item.Mitigation
+		risk.Assumption = item.Assumption
+		// Drop links to paths the room does not have, e.g. names the model made up
+		if paths[item.PathID] {
+			risk.PathID = item.PathID
+		}
+		risk.Source = models.RiskSourceAgent
+		risk.AgentName = agentName
+		if err := risk.Validate(); err != nil {
+			log.Printf("Dropping invalid risk from %s: %v", agentName, err)
+			continue
+		}
+		if err := s.risks.Create(ctx, risk); err != nil {
+			log.Printf("Failed to save risk from %s: %v", agentName, err)
+			continue
+		}
+
+		known[strings.ToLower(title)] = true
+		output.Risks = append(output.Risks, risk)
+	}
+}
+
+// assessedRisks collects the risks passed to successful risk_assessor calls. Outputs are decoded
+// through JSON because interactive sessions restore tool outputs as plain maps.
+func assessedRisks(executions []ToolExecution) []tools.AssessedRisk {
+	var risks []tools.AssessedRisk
+	for _, execution := range executions {
+		if execution.ToolName != "risk_assessor" || !execution.Success {
+			continue
+		}
+
+		data, err := json.Marshal(execution.Output)
+		if err != nil {
+			continue
+		}
+		var result struct {
+			Source string               `json:"source"`
+			Risks  []tools.AssessedRisk `json:"risks"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil || result.Source != "input" {
+			continue
+		}
+		risks = append(risks, result.Risks...)
+	}
+	return risks
+}
+
+// roomPathIDs returns the IDs of the room's approach paths
+func (s *Service) roomPathIDs(ctx context.Context, roomID string) map[string]bool {
+	ids := make(map[string]bool)
+	if s.rooms == nil {
+		return ids
+	}
+	room, err := s.rooms.rooms.Get(ctx, roomID)
+	if err != nil {
+		return ids
+	}
+	for _, path := range room.Approach.Paths {
+		ids[path.ID] = true
+	}
+	return ids
+}
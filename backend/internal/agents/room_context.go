@@ -0,0 +1,454 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/models"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RoomSource loads rooms for the context builder
+type RoomSource interface {
+	Get(ctx context.Context, id string) (*models.Room, error)
+}
+
+// VoteSource loads the voting sessions of a room
+type VoteSource interface {
+	GetByRoom(ctx context.Context, roomID string) ([]*models.Vote, error)
+}
+
+// EvidenceSource searches the indexed attachments of a room
+type EvidenceSource interface {
+	Search(ctx context.Context, query string, opts knowledge.SearchOptions) (*knowledge.SearchResponse, error)
+}
+
+// sprintPhases lists the Foundation Sprint phases in order
+var sprintPhases = []string{"foundation", "differentiation", "approach"}
+
+const (
+	defaultContextMaxTokens = 1500
+	defaultRecentVotes      = 5
+	defaultEvidenceChunks   = 3
+	maxEvidenceChars        = 300
+)
+
+// RoomContext is the rendered sprint state of a room
+type RoomContext struct {
+	Room            *models.Room
+	Phase           string
+	Text            string
+	EstimatedTokens int
+	Truncated       bool
+}
+
+// RoomContextBuilder renders the live room state into a prompt section within a token budget
+type RoomContextBuilder struct {
+	rooms       RoomSource
+	votes       VoteSource
+	evidence    EvidenceSource
+	maxTokens   int
+	recentVotes int
+}
+
+// NewRoomContextBuilder creates a context builder; the token budget comes from AGENT_CONTEXT_MAX_TOKENS.
+// votes may be nil, in which case votes and vote decisions are omitted.
+func NewRoomContextBuilder(rooms RoomSource, votes VoteSource) *RoomContextBuilder {
+	maxTokens := defaultContextMaxTokens
+	if value, err := strconv.Atoi(os.Getenv("AGENT_CONTEXT_MAX_TOKENS")); err == nil && value > 0 {
+		maxTokens = value
+	}
+
+	return &RoomContextBuilder{
+		rooms:       rooms,
+		votes:       votes,
+		maxTokens:   maxTokens,
+		recentVotes: defaultRecentVotes,
+	}
+}
+
+// SetEvidenceSource enables the evidence section: attachment excerpts relevant to the request
+func (b *RoomContextBuilder) SetEvidenceSource(source EvidenceSource) {
+	b.evidence = source
+}
+
+// Build loads the room and renders its state. The phase defaults to the room's status.
+// Sections are added by priority: current phase, decisions, recent votes, evidence for the
+// query, then the other phases.
+func (b *RoomContextBuilder) Build(ctx context.Context, roomID, phase, query string) (*RoomContext, error) {
+	room, err := b.rooms.Get(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	var votes []*models.Vote
+	if b.votes != nil {
+		votes, err = b.votes.GetByRoom(ctx, roomID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load votes: %w", err)
+		}
+	}
+
+	if phase == "" {
+		phase = room.Status
+	}
+
+	header := []string{fmt.Sprintf("Room: %s", room.Name), fmt.Sprintf("Current Phase: %s", phase)}
+	for _, item := range room.Settings.PhaseAgenda(phase) {
+		header = append(header, fmt.Sprintf("Agenda: %s (%d min)", item.Title, item.Minutes))
+	}
+	if instructions := room.Settings.Agents.Instructions; instructions != "" {
+		header = append(header, "Playbook instructions: "+instructions)
+	}
+	sections := [][]string{header}
+
+	// A completed sprint leads with its final phase; unknown phases lead with the first one
+	current := phase
+	switch current {
+	case "foundation", "differentiation", "approach":
+	case "completed":
+		current = "approach"
+	default:
+		current = "foundation"
+	}
+	sections = append(sections, renderPhase(room, current, true))
+	sections = append(sections, renderDecisions(room, votes))
+	sections = append(sections, b.renderRecentVotes(votes))
+	sections = append(sections, b.renderEvidence(ctx, roomID, query))
+	for _, other := range sprintPhases {
+		if other != current {
+			sections = append(sections, renderPhase(room, other, false))
+		}
+	}
+
+	text, tokens, truncated := fitSections(sections, b.maxTokens)
+
+	return &RoomContext{
+		Room:            room,
+		Phase:           phase,
+		Text:            text,
+		EstimatedTokens: tokens,
+		Truncated:       truncated,
+	}, nil
+}
+
+// fitSections joins sections line by line until the token budget is spent
+func fitSections(sections [][]string, maxTokens int) (string, int, bool) {
+	var builder strings.Builder
+	tokens := 0
+
+	for _, lines := range sections {
+		if len(lines) == 0 {
+			continue
+		}
+		if builder.Len() > 0 {
+			lines = append([]string{""}, lines...)
+		}
+		for _, line := range lines {
+			cost := estimateTokens(line) + 1
+			if tokens+cost > maxTokens {
+				builder.WriteString("(room context truncated to fit the token budget)\n")
+				return builder.String(), tokens, true
+			}
+			builder.WriteString(line)
+			builder.WriteString("\n")
+			tokens += cost
+		}
+	}
+
+	return builder.String(), tokens, false
+}
+
+// estimateTokens roughly estimates the token count: about 4 ASCII characters or 1 CJK character per token
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < 128 {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// renderPhase renders the data of one phase; empty non-current phases render nothing
+func renderPhase(room *models.Room, phase string, isCurrent bool) []string {
+	var lines []string
+	switch phase {
+	case "foundation":
+		lines = renderFoundation(room.Foundation)
+	case "differentiation":
+		lines = renderDifferentiation(room.Differentiation)
+	case "approach":
+		lines = renderApproach(room.Approach)
+	}
+
+	title := fmt.Sprintf("### %s phase", strings.ToUpper(phase[:1])+phase[1:])
+	if isCurrent {
+		title += " (current)"
+		if len(lines) == 0 {
+			lines = []string{"- nothing captured yet"}
+		}
+	} else if len(lines) == 0 {
+		return nil
+	}
+
+	return append([]string{title}, lines...)
+}
+
+func renderFoundation(foundation models.Foundation) []string {
+	var lines []string
+	lines = appendList(lines, "Customers", cardLabels(foundation.Customers))
+	lines = appendList(lines, "Problems", cardLabels(foundation.Problems))
+	lines = appendList(lines, "Competition", cardLabels(foundation.Competition))
+	lines = appendList(lines, "Advantages", cardLabels(foundation.Advantages))
+	return lines
+}
+
+// cardLabels renders each card's text with the attributes the team recorded
+func cardLabels(cards []models.Card) []string {
+	labels := make([]string, 0, len(cards))
+	for _, card := range cards {
+		var attributes []string
+		if card.PainIntensity != 0 {
+			attributes = append(attributes, fmt.Sprintf("pain %d/%d", card.PainIntensity, models.PainIntensityMax))
+		}
+		if card.AffectedShare != "" {
+			attributes = append(attributes, "affects "+card.AffectedShare+" customers")
+		}
+		if card.Kind != "" {
+			attributes = append(attributes, card.Kind)
+		}
+		if card.Category != "" {
+			attributes = append(attributes, card.Category)
+		}
+		label := card.Text
+		if len(attributes) > 0 {
+			label += " (" + strings.Join(attributes, ", ") + ")"
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+func renderDifferentiation(differentiation models.Differentiation) []string {
+	var lines []string
+
+	factors := append([]models.DifferentiationFactor{}, differentiation.ClassicFactors...)
+	factors = append(factors, differentiation.CustomFactors...)
+	if len(factors) > 0 {
+		lines = append(lines, "Differentiators:")
+		for _, factor := range factors {
+			line := fmt.Sprintf("  - %s", factor.Name)
+			if factor.Description != "" {
+				line += ": " + factor.Description
+			}
+			if factor.Weight != 0 {
+				line += fmt.Sprintf(" (weight %d)", factor.Weight)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	matrix := differentiation.Matrix
+	if matrix.XAxis != "" || matrix.YAxis != "" || len(matrix.Products) > 0 {
+		lines = append(lines, fmt.Sprintf("2x2 Matrix: x = %s, y = %s", matrix.XAxis, matrix.YAxis))
+		for _, product := range matrix.Products {
+			marker := ""
+			if product.IsUs {
+				marker = " [us]"
+			}
+			lines = append(lines, fmt.Sprintf("  - %s%s at (%.0f, %.0f)", product.Name, marker, product.X, product.Y))
+		}
+	}
+
+	lines = appendList(lines, "Principles", differentiation.Principles)
+	return lines
+}
+
+func renderApproach(approach models.Approach) []string {
+	var lines []string
+
+	if len(approach.Paths) > 0 {
+		lines = append(lines, "Paths:")
+		for _, path := range approach.Paths {
+			line := fmt.Sprintf("  - %s", path.Name)
+			if path.Description != "" {
+				line += ": " + path.Description
+			}
+			lines = append(lines, line)
+			if len(path.Pros) > 0 {
+				lines = append(lines, "    Pros: "+strings.Join(path.Pros, "; "))
+			}
+			if len(path.Cons) > 0 {
+				lines = append(lines, "    Cons: "+strings.Join(path.Cons, "; "))
+			}
+		}
+	}
+
+	if len(approach.MagicLenses) > 0 {
+		lines = append(lines, "Magic Lenses:")
+		for _, lens := range approach.MagicLenses {
+			scores := make([]string, 0, len(lens.Evaluations))
+			for _, evaluation := range lens.Evaluations {
+				scores = append(scores, fmt.Sprintf("%s=%.1f", pathName(approach, evaluation.PathID), evaluation.Score))
+			}
+			line := fmt.Sprintf("  - %s", lens.Name)
+			if len(scores) > 0 {
+				line += ": " + strings.Join(scores, ", ")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// renderDecisions renders what the team has already decided
+func renderDecisions(room *models.Room, votes []*models.Vote) []string {
+	var lines []string
+
+	matrix := room.Differentiation.Matrix
+	if matrix.WinningQuadrant != "" {
+		line := fmt.Sprintf("- Winning quadrant: %s (x = %s, y = %s)", matrix.WinningQuadrant, matrix.XAxis, matrix.YAxis)
+		analysis := models.BuildMatrixAnalysis(room.Differentiation)
+		switch {
+		case analysis.Us == "":
+		case analysis.Exclusive:
+			line += fmt.Sprintf("; %s holds it alone", analysis.Us)
+		case len(analysis.SharedWith) > 0:
+			line += fmt.Sprintf("; %s shares it with %s", analysis.Us, strings.Join(analysis.SharedWith, ", "))
+		case analysis.InWinningQuadrant:
+			line += fmt.Sprintf("; %s sits on a midline of it", analysis.Us)
+		default:
+			line += fmt.Sprintf("; %s is not in it yet (%s)", analysis.Us, analysis.UsQuadrant)
+		}
+		lines = append(lines, line)
+	}
+
+	if room.Approach.SelectedPath != "" {
+		line := fmt.Sprintf("- Selected path: %s", pathName(room.Approach, room.Approach.SelectedPath))
+		if room.Approach.Reasoning != "" {
+			line += " — " + room.Approach.Reasoning
+		}
+		lines = append(lines, line)
+	}
+
+	for _, vote := range votes {
+		decision := vote.Decision
+		if vote.Status != models.VoteDecided || decision == nil {
+			continue
+		}
+		line := fmt.Sprintf("- Vote \"%s\" decided: %s — %s", vote.Title, decision.OptionText, decision.Rationale)
+		if decision.Overrides {
+			line += " (the decider overrode the vote's winner)"
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]string{"### Decisions"}, lines...)
+}
+
+// renderRecentVotes renders the most recent voting sessions with their leading options
+func (b *RoomContextBuilder) renderRecentVotes(votes []*models.Vote) []string {
+	if len(votes) == 0 {
+		return nil
+	}
+
+	recent := append([]*models.Vote{}, votes...)
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].CreatedAt.After(recent[j].CreatedAt)
+	})
+	if len(recent) > b.recentVotes {
+		recent = recent[:b.recentVotes]
+	}
+
+	lines := []string{"### Recent votes"}
+	for _, vote := range recent {
+		if !vote.ResultsVisible() {
+			lines = append(lines, fmt.Sprintf("- %s [%s, %d ballots]: results hidden until revealed", vote.Title, vote.Status, vote.BallotCount()))
+			continue
+		}
+		result := vote.Tally()
+		options := result.Options
+		if len(options) > 3 {
+			options = options[:3]
+		}
+
+		tallies := make([]string, 0, len(options))
+		for _, option := range options {
+			tallies = append(tallies, fmt.Sprintf("%s (%d %s)", option.Text, option.Score, scoreUnit(result.Method)))
+		}
+		lines = append(lines, fmt.Sprintf("- %s [%s, %s, %d ballots]: %s", vote.Title, result.Method, vote.Status, result.Ballots, strings.Join(tallies, ", ")))
+	}
+
+	return lines
+}
+
+// renderEvidence renders the room's attachment excerpts most relevant to the query
+func (b *RoomContextBuilder) renderEvidence(ctx context.Context, roomID, query string) []string {
+	if b.evidence == nil || strings.TrimSpace(query) == "" {
+		return nil
+	}
+
+	response, err := b.evidence.Search(ctx, query, knowledge.SearchOptions{
+		Limit:      defaultEvidenceChunks,
+		SourceType: models.KnowledgeSourceAttachment,
+		RoomID:     roomID,
+	})
+	if err != nil {
+		log.Printf("Failed to search evidence for room %s: %v", roomID, err)
+		return nil
+	}
+	if len(response.Results) == 0 {
+		return nil
+	}
+
+	lines := []string{"### Evidence from attachments"}
+	for _, hit := range response.Results {
+		excerpt := strings.Join(strings.Fields(hit.Snippet), " ")
+		if runes := []rune(excerpt); len(runes) > maxEvidenceChars {
+			excerpt = string(runes[:maxEvidenceChars]) + "…"
+		}
+		lines = append(lines, fmt.Sprintf("- [%s] %s", hit.Title, excerpt))
+	}
+	return lines
+}
+
+// scoreUnit names what an option's score counts under a tally method
+func scoreUnit(method string) string {
+	switch method {
+	case models.TallyDots:
+		return "dots"
+	case models.RankingBorda:
+		return "Borda points"
+	case models.RankingInstantRunoff:
+		return "final-round votes"
+	default:
+		return "votes"
+	}
+}
+
+func pathName(approach models.Approach, pathID string) string {
+	for _, path := range approach.Paths {
+		if path.ID == pathID {
+			return path.Name
+		}
+	}
+	return pathID
+}
+
+func appendList(lines []string, label string, items []string) []string {
+	if len(items) == 0 {
+		return lines
+	}
+	return append(lines, fmt.Sprintf("%s: %s", label, strings.Join(items, "; ")))
+}
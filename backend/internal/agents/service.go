@@ -4,40 +4,124 @@ import (
 	"context"
 	"fmt"
 	"foundation-sprint/internal/agents/llm"
+	"foundation-sprint/internal/agents/mcp"
 	"foundation-sprint/internal/agents/tools"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/models"
+	"log"
+	"os"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
 )
 
 // Service manages all agents
 type Service struct {
-	agents    map[string]Agent
-	LLMClient LLMClient  // Exported for use in handlers
-	mu        sync.RWMutex
+	agents     map[string]Agent
+	LLMClient  LLMClient  // Exported for use in handlers
+	Usage      *UsageTracker
+	rooms      *RoomContextBuilder
+	proposals  ProposalStore
+	risks      RiskStore
+	hypotheses HypothesisStore
+	// configAgents holds the names of the agents loaded from config files
+	configAgents map[string]bool
+	mu           sync.RWMutex
+}
+
+var (
+	registerToolsOnce sync.Once
+	registerToolsErr  error
+	
+	// MCPServers holds the connections to the external tool servers in MCP_SERVERS_FILE
+	MCPServers = mcp.NewManager(tools.DefaultRegistry)
+	
+	// ThinkToolModel is the language model of the think tools; NewService connects it to the LLM client
+	ThinkToolModel = tools.NewThinkModel()
+)
+
+// RegisterTools registers the built-in tools and the tools of the configured MCP servers once per process
+func RegisterTools() error {
+	registerToolsOnce.Do(func() {
+		if err := tools.RegisterThinkTools(ThinkToolModel); err != nil {
+			registerToolsErr = fmt.Errorf("failed to register think tools: %w", err)
+			return
+		}
+		if err := tools.RegisterCritiqueTools(); err != nil {
+			registerToolsErr = fmt.Errorf("failed to register critique tools: %w", err)
+			return
+		}
+		if err := tools.RegisterResearchTools(); err != nil {
+			registerToolsErr = fmt.Errorf("failed to register research tools: %w", err)
+			return
+		}
+		store, err := knowledge.GetStore()
+		if err != nil {
+			registerToolsErr = fmt.Errorf("failed to open knowledge base: %w", err)
+			return
+		}
+		if err := tools.RegisterKnowledgeTools(store); err != nil {
+			registerToolsErr = fmt.Errorf("failed to register knowledge tools: %w", err)
+			return
+		}
+		
+		// External tools register before agents are built so agents and agent configs can use them
+		if path := os.Getenv("MCP_SERVERS_FILE"); path != "" {
+			config, err := mcp.LoadConfig(path)
+			if err != nil {
+				registerToolsErr = fmt.Errorf("failed to load mcp servers: %w", err)
+				return
+			}
+			MCPServers.Start(context.Background(), config.Servers)
+		}
+	})
+	return registerToolsErr
 }
 
 // NewService creates a new agent service
 func NewService() (*Service, error) {
 	// Initialize tools
-	if err := tools.RegisterThinkTools(); err != nil {
-		return nil, fmt.Errorf("failed to register think tools: %w", err)
-	}
-	if err := tools.RegisterCritiqueTools(); err != nil {
-		return nil, fmt.Errorf("failed to register critique tools: %w", err)
+	if err := RegisterTools(); err != nil {
+		return nil, err
 	}
-	if err := tools.RegisterResearchTools(); err != nil {
-		return nil, fmt.Errorf("failed to register research tools: %w", err)
+	
+	// Validate custom agents before anything else so config mistakes surface at boot
+	configDir := os.Getenv("AGENTS_CONFIG_DIR")
+	var definitions []AgentDefinition
+	if configDir != "" {
+		var err error
+		definitions, err = LoadAgentDefinitions(configDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load agent configs: %w", err)
+		}
 	}
 	
 	// Create LLM client adapter
-	llmClient, err := NewLLMClientAdapter()
+	adapter, err := NewLLMClientAdapter()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create LLM client: %w", err)
 	}
 	
+	// Meter every LLM call for cost reporting and budgets
+	prices, err := LoadPriceTable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load LLM pricing: %w", err)
+	}
+	usage := NewUsageTracker(prices, LoadBudgetConfig())
+	llmClient := NewMeteredLLMClient(adapter, usage)
+	
+	// Think tools run focused sub-prompts unless THINK_TOOLS_LLM=false keeps them on their templates
+	if os.Getenv("THINK_TOOLS_LLM") != "false" {
+		ThinkToolModel.SetCompleter(NewToolCompleter(llmClient))
+	}
+	
 	// Create service
 	service := &Service{
-		agents:    make(map[string]Agent),
-		LLMClient: llmClient,
+		agents:       make(map[string]Agent),
+		LLMClient:    llmClient,
+		Usage:        usage,
+		configAgents: make(map[string]bool),
 	}
 	
 	// Register agents
@@ -45,9 +129,134 @@ func NewService() (*Service, error) {
 	service.RegisterAgent(NewCritiqueAgent(llmClient))
 	service.RegisterAgent(NewResearchAgent(llmClient))
 	
+	if configDir != "" {
+		service.registerConfigAgents(definitions)
+		log.Printf("Loaded %d custom agents from %s", len(definitions), configDir)
+		
+		if interval := agentConfigReloadInterval(); interval > 0 {
+			service.WatchAgentConfigs(configDir, interval)
+		}
+	}
+	
 	return service, nil
 }
 
+// agentConfigReloadInterval reads AGENTS_CONFIG_RELOAD_SECONDS; 0 disables hot reload
+func agentConfigReloadInterval() time.Duration {
+	seconds := 10
+	if value := os.Getenv("AGENTS_CONFIG_RELOAD_SECONDS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid AGENTS_CONFIG_RELOAD_SECONDS %q, using %d", value, seconds)
+		} else {
+			seconds = parsed
+		}
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// LoadAgentConfigs replaces the config-defined agents with the definitions in dir.
+// Nothing changes if any definition is invalid.
+func (s *Service) LoadAgentConfigs(dir string) error {
+	definitions, err := LoadAgentDefinitions(dir)
+	if err != nil {
+		return err
+	}
+	s.registerConfigAgents(definitions)
+	return nil
+}
+
+// registerConfigAgents swaps the current config agents for the given definitions
+func (s *Service) registerConfigAgents(definitions []AgentDefinition) {
+	loaded := make(map[string]Agent, len(definitions))
+	for _, definition := range definitions {
+		loaded[definition.Name] = NewConfigAgent(definition, s.LLMClient)
+	}
+	
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	for name := range s.configAgents {
+		if _, exists := loaded[name]; !exists {
+			delete(s.agents, name)
+			tools.RemoveAgentTools(name)
+		}
+	}
+	s.configAgents = make(map[string]bool, len(loaded))
+	for name, agent := range loaded {
+		s.agents[name] = agent
+		s.configAgents[name] = true
+	}
+}
+
+// UnregisterAgent removes an agent
+func (s *Service) UnregisterAgent(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.agents, name)
+	delete(s.configAgents, name)
+}
+
+// SetUsageStore sets the store used to persist LLM usage and budgets
+func (s *Service) SetUsageStore(store UsageStore) {
+	s.Usage.SetStore(store)
+}
+
+// CheckBudget returns an error wrapping ErrBudgetExceeded if the room or workspace budget is spent
+func (s *Service) CheckBudget(ctx context.Context, roomID string) error {
+	return s.Usage.CheckBudget(ctx, roomID)
+}
+
+// SetRoomContextBuilder sets the builder used to load live room state into agent inputs
+func (s *Service) SetRoomContextBuilder(builder *RoomContextBuilder) {
+	s.rooms = builder
+}
+
+// EnrichInput fills the input with the live state of its room and defaults the phase to the room's status.
+// Rooms that cannot be loaded are logged and skipped so the request still runs on client-supplied context.
+func (s *Service) EnrichInput(ctx context.Context, input *AgentInput) {
+	if s.rooms == nil || input.RoomID == "" || input.RoomContext != "" {
+		return
+	}
+	
+	roomContext, err := s.rooms.Build(ctx, input.RoomID, input.Phase, input.Query)
+	if err != nil {
+		log.Printf("Failed to build context for room %s: %v", input.RoomID, err)
+		return
+	}
+	
+	input.RoomContext = roomContext.Text
+	if input.Phase == "" {
+		input.Phase = roomContext.Phase
+	}
+}
+
+// SetProposalStore sets the store used to persist agent proposals
+func (s *Service) SetProposalStore(store ProposalStore) {
+	s.proposals = store
+}
+
+// SaveProposals persists the proposals of an agent output; proposals that fail to save are dropped from the output
+func (s *Service) SaveProposals(ctx context.Context, output *AgentOutput) {
+	if output == nil || len(output.Proposals) == 0 {
+		return
+	}
+	if s.proposals == nil {
+		output.Proposals = nil
+		return
+	}
+	
+	saved := make([]*models.Proposal, 0, len(output.Proposals))
+	for _, proposal := range output.Proposals {
+		if err := s.proposals.Create(ctx, proposal); err != nil {
+			log.Printf("Failed to save proposal from %s: %v", proposal.AgentName, err)
+			continue
+		}
+		saved = append(saved, proposal)
+	}
+	output.Proposals = saved
+}
+
 // RegisterAgent registers an agent
 func (s *Service) RegisterAgent(agent Agent) {
 	s.mu.Lock()
@@ -73,7 +282,19 @@ func (s *Service) ProcessThink(ctx context.Context, input AgentInput) (*AgentOut
 	if err != nil {
 		return nil, err
 	}
-	return agent.Process(ctx, input)
+	if err := s.CheckBudget(ctx, input.RoomID); err != nil {
+		return nil, err
+	}
+	s.EnrichInput(ctx, &input)
+	
+	output, err := agent.Process(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	s.SaveProposals(ctx, output)
+	s.SaveRisks(ctx, input.RoomID, agent.GetName(), output)
+	s.SaveHypotheses(ctx, input.RoomID, agent.GetName(), output)
+	return output, nil
 }
 
 // ProcessCritique handles CritiqueAgent requests
@@ -82,7 +303,19 @@ func (s *Service) ProcessCritique(ctx context.Context, input AgentInput) (*Agent
 	if err != nil {
 		return nil, err
 	}
-	return agent.Process(ctx, input)
+	if err := s.CheckBudget(ctx, input.RoomID); err != nil {
+		return nil, err
+	}
+	s.EnrichInput(ctx, &input)
+	
+	output, err := agent.Process(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	s.SaveProposals(ctx, output)
+	s.SaveRisks(ctx, input.RoomID, agent.GetName(), output)
+	s.SaveHypotheses(ctx, input.RoomID, agent.GetName(), output)
+	return output, nil
 }
 
 // ProcessResearch handles ResearchAgent requests
@@ -91,7 +324,19 @@ func (s *Service) ProcessResearch(ctx context.Context, input AgentInput) (*Agent
 	if err != nil {
 		return nil, err
 	}
-	return agent.Process(ctx, input)
+	if err := s.CheckBudget(ctx, input.RoomID); err != nil {
+		return nil, err
+	}
+	s.EnrichInput(ctx, &input)
+	
+	output, err := agent.Process(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	s.SaveProposals(ctx, output)
+	s.SaveRisks(ctx, input.RoomID, agent.GetName(), output)
+	s.SaveHypotheses(ctx, input.RoomID, agent.GetName(), output)
+	return output, nil
 }
 
 // GetToolsForAgent returns tools for a specific agent
@@ -104,48 +349,81 @@ func GetToolsForAgent(agentName string) []Tool {
 	return agentTools
 }
 
-// ProcessMultiAgent runs multiple agents in parallel
-func (s *Service) ProcessMultiAgent(ctx context.Context, input AgentInput, agentNames []string) (map[string]*AgentOutput, error) {
+// ProcessMultiAgent runs multiple agents in parallel. It returns the outputs of the agents
+// that succeeded and the errors of those that failed; an empty list runs every registered agent.
+func (s *Service) ProcessMultiAgent(ctx context.Context, input AgentInput, agentNames []string) (map[string]*AgentOutput, map[string]error) {
 	results := make(map[string]*AgentOutput)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+	errs := make(map[string]error)
 	
-	errChan := make(chan error, len(agentNames))
+	if len(agentNames) == 0 {
+		agentNames = s.AgentNames()
+	}
 	
+	// The budget is shared by the whole panel, so check it once up front
+	if err := s.CheckBudget(ctx, input.RoomID); err != nil {
+		for _, name := range agentNames {
+			errs[name] = err
+		}
+		return results, errs
+	}
+	s.EnrichInput(ctx, &input)
+	
+	// Resolve agents before starting, skipping duplicates
+	selected := make(map[string]Agent)
 	for _, name := range agentNames {
+		if _, exists := selected[name]; exists {
+			continue
+		}
+		agent, err := s.GetAgent(name)
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+		selected[name] = agent
+	}
+	
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	
+	for name, agent := range selected {
 		wg.Add(1)
-		go func(agentName string) {
+		go func(agentName string, agent Agent) {
 			defer wg.Done()
 			
-			agent, err := s.GetAgent(agentName)
-			if err != nil {
-				errChan <- fmt.Errorf("agent %s: %w", agentName, err)
-				return
-			}
-			
 			output, err := agent.Process(ctx, input)
+			
+			mu.Lock()
+			defer mu.Unlock()
 			if err != nil {
-				errChan <- fmt.Errorf("agent %s processing: %w", agentName, err)
+				errs[agentName] = err
 				return
 			}
-			
-			mu.Lock()
 			results[agentName] = output
-			mu.Unlock()
-		}(name)
+		}(name, agent)
 	}
 	
 	wg.Wait()
-	close(errChan)
 	
-	// Check for errors
-	for err := range errChan {
-		if err != nil {
-			return results, err // Return partial results with error
-		}
+	for agentName, output := range results {
+		s.SaveProposals(ctx, output)
+		s.SaveRisks(ctx, input.RoomID, agentName, output)
+		s.SaveHypotheses(ctx, input.RoomID, agentName, output)
 	}
 	
-	return results, nil
+	return results, errs
+}
+
+// AgentNames returns the names of all registered agents in sorted order
+func (s *Service) AgentNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	
+	names := make([]string, 0, len(s.agents))
+	for name := range s.agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // LLMClientAdapter adapts the llm.Client to the agents.LLMClient interface
@@ -169,10 +447,16 @@ func NewLLMClientAdapter() (*LLMClientAdapter, error) {
 }
 
 // Complete implements the LLMClient interface
-func (a *LLMClientAdapter) Complete(ctx context.Context, prompt string, options ...LLMOption) (string, error) {
+func (a *LLMClientAdapter) Complete(ctx context.Context, prompt string, options ...LLMOption) (*LLMResponse, error) {
 	// Convert agent options to llm options
 	llmOpts := a.convertOptions(options)
-	return a.client.Complete(ctx, prompt, llmOpts...)
+	
+	response, err := a.client.Complete(ctx, prompt, llmOpts...)
+	if err != nil {
+		return nil, err
+	}
+	
+	return a.convertResponse(response), nil
 }
 
 // CompleteWithTools implements the LLMClient interface
@@ -193,16 +477,32 @@ func (a *LLMClientAdapter) CompleteWithTools(ctx context.Context, prompt string,
 	}
 	
 	// Convert response
+	return a.convertResponse(response), nil
+}
+
+// Model returns the model the underlying client is configured with
+func (a *LLMClientAdapter) Model() string {
+	return a.client.Model()
+}
+
+// convertResponse converts an llm response to an agent response
+func (a *LLMClientAdapter) convertResponse(response *llm.Response) *LLMResponse {
+	model := response.Model
+	if model == "" {
+		model = a.client.Model()
+	}
+	
 	return &LLMResponse{
 		Content:      response.Content,
 		ToolCalls:    a.convertToolCalls(response.ToolCalls),
 		FinishReason: response.FinishReason,
+		Model:        model,
 		Usage: TokenUsage{
 			PromptTokens:     response.Usage.PromptTokens,
 			CompletionTokens: response.Usage.CompletionTokens,
 			TotalTokens:      response.Usage.TotalTokens,
 		},
-	}, nil
+	}
 }
 
 // Stream implements the LLMClient interface
@@ -265,6 +565,11 @@ func (t *ToolAdapter) GetDescription() string {
 
 // GetParameters returns the tool parameters
 func (t *ToolAdapter) GetParameters() map[string]interface{} {
+	// Tools with a JSON Schema describe their own parameters
+	if properties, ok := t.schemaProperties(); ok {
+		return properties
+	}
+	
 	// For simplicity, return a basic schema
 	// In production, this should be more sophisticated
 	return map[string]interface{}{
@@ -277,5 +582,40 @@ func (t *ToolAdapter) GetParameters() map[string]interface{} {
 
 // GetRequired returns required parameters
 func (t *ToolAdapter) GetRequired() []string {
+	if _, ok := t.schemaProperties(); ok {
+		return tools.SchemaRequired(t.tool.(tools.SchemaProvider).InputSchema())
+	}
 	return []string{"query"}
-}
\ No newline at end of file
+}
+
+// schemaProperties returns the properties of the tool's JSON Schema, if it has one
+func (t *ToolAdapter) schemaProperties() (map[string]interface{}, bool) {
+	provider, ok := t.tool.(tools.SchemaProvider)
+	if !ok {
+		return nil, false
+	}
+	properties, ok := provider.InputSchema()["properties"].(map[string]interface{})
+	return properties, ok
+}
+
+// ToolCompleter lets tools run sub-prompts through the agent LLM client, metered as "tool" calls
+type ToolCompleter struct {
+	client LLMClient
+}
+
+// NewToolCompleter creates a completer backed by the LLM client
+func NewToolCompleter(client LLMClient) *ToolCompleter {
+	return &ToolCompleter{client: client}
+}
+
+// Complete implements the tools.Completer interface
+func (c *ToolCompleter) Complete(ctx context.Context, systemPrompt, prompt string) (string, error) {
+	response, err := c.client.Complete(withCallType(ctx, "tool"), prompt,
+		WithSystemPrompt(systemPrompt),
+		WithTemperature(0.7),
+		WithMaxTokens(1500))
+	if err != nil {
+		return "", err
+	}
+	return response.Content, nil
+}
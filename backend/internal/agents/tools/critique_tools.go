@@ -15,14 +15,111 @@ type AssumptionCheckerTool struct {
 func NewAssumptionCheckerTool() *AssumptionCheckerTool {
 	return &AssumptionCheckerTool{
 		BaseTool: BaseTool{
-			Name:        "assumption_checker",
-			Description: "Identify and validate assumptions in a business idea or plan",
-			Required:    []string{"statement"},
-			Optional:    []string{"domain", "evidence"},
+			Name: "assumption_checker",
+			Description: "Identify and validate assumptions in a business idea or plan. Pass the assumptions you found as " +
+				"assumptions: [{\"assumption\", \"type\", \"confidence\": low|medium|high, \"validation_method\", " +
+				"\"success_metric\"}] to record them as testable hypotheses for the room; without assumptions a generic " +
+				"checklist is returned",
+			Required: []string{"statement"},
+			Optional: []string{"domain", "evidence", "assumptions"},
 		},
 	}
 }
 
+// InputSchema describes the assumptions array so function-calling models can fill it
+func (t *AssumptionCheckerTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"statement": map[string]interface{}{"type": "string", "description": "The idea or plan to check"},
+			"domain":    map[string]interface{}{"type": "string"},
+			"evidence":  map[string]interface{}{"type": "string"},
+			"assumptions": map[string]interface{}{
+				"type":        "array",
+				"description": "Assumptions the idea depends on, each with how to test it",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"assumption":        map[string]interface{}{"type": "string"},
+						"type":              map[string]interface{}{"type": "string", "description": "e.g. market, technical, execution"},
+						"confidence":        map[string]interface{}{"type": "string", "enum": []string{"low", "medium", "high"}},
+						"validation_method": map[string]interface{}{"type": "string", "description": "How to test it, e.g. customer interviews"},
+						"success_metric":    map[string]interface{}{"type": "string", "description": "The result that confirms it"},
+					},
+					"required": []string{"assumption"},
+				},
+			},
+		},
+		"required": []string{"statement"},
+	}
+}
+
+// CheckedAssumption is an assumption listed by assumption_checker
+type CheckedAssumption struct {
+	Assumption    string   `json:"assumption"`
+	Type          string   `json:"type"`
+	RiskLevel     string   `json:"risk_level,omitempty"`
+	Confidence    string   `json:"confidence,omitempty"` // low / medium / high
+	Validation    string   `json:"validation"`
+	SuccessMetric string   `json:"success_metric,omitempty"`
+	Questions     []string `json:"questions,omitempty"`
+}
+
+// assumptionConfidenceLabels map confidence labels of LLM input onto low / medium / high
+var assumptionConfidenceLabels = map[string]string{
+	"low": "low", "低": "low",
+	"medium": "medium", "中": "medium",
+	"high": "high", "高": "high",
+}
+
+// assumptionChecklist is the generic answer when no assumptions are passed in
+var assumptionChecklist = []CheckedAssumption{
+	{
+		Assumption: "用户愿意为此付费",
+		Type:       "市场假设",
+		RiskLevel:  "高",
+		Validation: "需要通过用户访谈和支付意愿测试验证",
+		Questions: []string{
+			"用户现在如何解决这个问题？",
+			"他们为现有解决方案支付多少钱？",
+			"什么会让他们转换到新方案？",
+		},
+	},
+	{
+		Assumption: "技术可以实现预期功能",
+		Type:       "技术假设",
+		RiskLevel:  "中",
+		Validation: "需要技术原型和可行性研究",
+		Questions: []string{
+			"核心技术是否已经成熟？",
+			"是否有类似的技术实现案例？",
+			"技术瓶颈在哪里？",
+		},
+	},
+	{
+		Assumption: "市场规模足够大",
+		Type:       "市场假设",
+		RiskLevel:  "高",
+		Validation: "需要市场研究和数据分析",
+		Questions: []string{
+			"目标市场有多大？",
+			"增长趋势如何？",
+			"市场渗透率能达到多少？",
+		},
+	},
+	{
+		Assumption: "团队能够执行",
+		Type:       "执行假设",
+		RiskLevel:  "中",
+		Validation: "评估团队能力和资源",
+		Questions: []string{
+			"团队是否有相关经验？",
+			"是否有足够的资源？",
+			"执行的最大挑战是什么？",
+		},
+	},
+}
+
 // Execute checks assumptions
 func (t *AssumptionCheckerTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	statement, ok := input["statement"].(string)
@@ -30,51 +127,14 @@ func (t *AssumptionCheckerTool) Execute(ctx context.Context, input map[string]in
 		return nil, fmt.Errorf("statement must be a string")
 	}
 	
-	assumptions := []map[string]interface{}{
-		{
-			"assumption": "用户愿意为此付费",
-			"type":       "市场假设",
-			"risk_level": "高",
-			"validation": "需要通过用户访谈和支付意愿测试验证",
-			"questions": []string{
-				"用户现在如何解决这个问题？",
-				"他们为现有解决方案支付多少钱？",
-				"什么会让他们转换到新方案？",
-			},
-		},
-		{
-			"assumption": "技术可以实现预期功能",
-			"type":       "技术假设",
-			"risk_level": "中",
-			"validation": "需要技术原型和可行性研究",
-			"questions": []string{
-				"核心技术是否已经成熟？",
-				"是否有类似的技术实现案例？",
-				"技术瓶颈在哪里？",
-			},
-		},
-		{
-			"assumption": "市场规模足够大",
-			"type":       "市场假设",
-			"risk_level": "高",
-			"validation": "需要市场研究和数据分析",
-			"questions": []string{
-				"目标市场有多大？",
-				"增长趋势如何？",
-				"市场渗透率能达到多少？",
-			},
-		},
-		{
-			"assumption": "团队能够执行",
-			"type":       "执行假设",
-			"risk_level": "中",
-			"validation": "评估团队能力和资源",
-			"questions": []string{
-				"团队是否有相关经验？",
-				"是否有足够的资源？",
-				"执行的最大挑战是什么？",
-			},
-		},
+	assumptions, err := parseCheckedAssumptions(input["assumptions"])
+	if err != nil {
+		return nil, err
+	}
+	source := "input"
+	if len(assumptions) == 0 {
+		source = "template"
+		assumptions = assumptionChecklist
 	}
 	
 	// Analyze the statement for specific assumptions
@@ -91,9 +151,47 @@ func (t *AssumptionCheckerTool) Execute(ctx context.Context, input map[string]in
 		"identified_assumptions": assumptions,
 		"critical_assumptions": criticalAssumptions,
 		"recommendation":      "优先验证高风险假设，使用最小成本的验证方法",
+		"source":              source,
 	}, nil
 }
 
+// parseCheckedAssumptions reads the assumptions input
+func parseCheckedAssumptions(value interface{}) ([]CheckedAssumption, error) {
+	items, ok := value.([]interface{})
+	if value == nil || (ok && len(items) == 0) {
+		return nil, nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("assumptions must be an array")
+	}
+	
+	assumptions := make([]CheckedAssumption, 0, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("assumptions[%d] must be an object", i)
+		}
+		text, _ := fields["assumption"].(string)
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("assumptions[%d].assumption is required", i)
+		}
+		
+		assumption := CheckedAssumption{Assumption: strings.TrimSpace(text)}
+		if label, ok := fields["confidence"].(string); ok && label != "" {
+			confidence, known := assumptionConfidenceLabels[strings.ToLower(strings.TrimSpace(label))]
+			if !known {
+				return nil, fmt.Errorf("assumptions[%d].confidence must be low, medium or high", i)
+			}
+			assumption.Confidence = confidence
+		}
+		assumption.Type, _ = fields["type"].(string)
+		assumption.Validation, _ = fields["validation_method"].(string)
+		assumption.SuccessMetric, _ = fields["success_metric"].(string)
+		assumptions = append(assumptions, assumption)
+	}
+	return assumptions, nil
+}
+
 // MarketValidatorTool validates market demand
 type MarketValidatorTool struct {
 	BaseTool
@@ -255,14 +353,79 @@ type RiskAssessorTool struct {
 func NewRiskAssessorTool() *RiskAssessorTool {
 	return &RiskAssessorTool{
 		BaseTool: BaseTool{
-			Name:        "risk_assessor",
-			Description: "Identify and assess potential risks",
-			Required:    []string{"venture"},
-			Optional:    []string{"industry", "stage"},
+			Name: "risk_assessor",
+			Description: "Identify and assess potential risks. Pass the risks you found as risks: [{\"risk\", \"category\", " +
+				"\"likelihood\": 1-5, \"impact\": 1-5, \"mitigation\", \"path_id\", \"assumption\"}] to score them and " +
+				"record them in the room's risk register; without risks a generic checklist is returned",
+			Required: []string{"venture"},
+			Optional: []string{"industry", "stage", "risks"},
+		},
+	}
+}
+
+// InputSchema describes the risks array so function-calling models can fill it
+func (t *RiskAssessorTool) InputSchema() map[string]interface{} {
+	scale := map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 5}
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"venture":  map[string]interface{}{"type": "string", "description": "The venture or idea being assessed"},
+			"industry": map[string]interface{}{"type": "string"},
+			"stage":    map[string]interface{}{"type": "string"},
+			"risks": map[string]interface{}{
+				"type":        "array",
+				"description": "Risks specific to the venture; likelihood and impact use a 1-5 scale",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"risk":       map[string]interface{}{"type": "string"},
+						"category":   map[string]interface{}{"type": "string"},
+						"likelihood": scale,
+						"impact":     scale,
+						"mitigation": map[string]interface{}{"type": "string"},
+						"path_id":    map[string]interface{}{"type": "string", "description": "ID of the approach path the risk threatens"},
+						"assumption": map[string]interface{}{"type": "string", "description": "The assumption the risk threatens"},
+					},
+					"required": []string{"risk", "likelihood", "impact"},
+				},
+			},
 		},
+		"required": []string{"venture"},
 	}
 }
 
+// AssessedRisk is a risk scored by risk_assessor; likelihood and impact scores use a 1-5 scale
+type AssessedRisk struct {
+	Category        string `json:"category"`
+	Risk            string `json:"risk"`
+	Probability     string `json:"probability"` // 低 / 中 / 高
+	Impact          string `json:"impact"`      // 低 / 中 / 高 / 极高
+	LikelihoodScore int    `json:"likelihood_score"`
+	ImpactScore     int    `json:"impact_score"`
+	Mitigation      string `json:"mitigation"`
+	PathID          string `json:"path_id,omitempty"`
+	Assumption      string `json:"assumption,omitempty"`
+}
+
+// riskScaleLabels map the labels of the checklist and of LLM input onto the 1-5 scale
+var riskScaleLabels = map[string]int{
+	"极低": 1, "very low": 1,
+	"低": 2, "low": 2,
+	"中": 3, "medium": 3,
+	"高": 4, "high": 4,
+	"极高": 5, "very high": 5, "critical": 5,
+}
+
+// riskChecklist is the generic answer when no risks are passed in
+var riskChecklist = []AssessedRisk{
+	{Category: "市场风险", Risk: "需求不如预期", Probability: "中", Impact: "高", Mitigation: "进行充分的市场验证，采用精益创业方法"},
+	{Category: "技术风险", Risk: "技术实现困难", Probability: "中", Impact: "高", Mitigation: "建立技术原型，引入技术顾问"},
+	{Category: "竞争风险", Risk: "巨头进入市场", Probability: "低", Impact: "极高", Mitigation: "快速建立护城河，专注细分市场"},
+	{Category: "财务风险", Risk: "资金链断裂", Probability: "中", Impact: "极高", Mitigation: "控制烧钱速度，多元化融资渠道"},
+	{Category: "团队风险", Risk: "核心成员流失", Probability: "低", Impact: "高", Mitigation: "股权激励，建立良好文化"},
+	{Category: "法律风险", Risk: "合规问题", Probability: "低", Impact: "中", Mitigation: "提前咨询法律顾问，建立合规体系"},
+}
+
 // Execute assesses risks
 func (t *RiskAssessorTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	venture, ok := input["venture"].(string)
@@ -270,59 +433,25 @@ func (t *RiskAssessorTool) Execute(ctx context.Context, input map[string]interfa
 		return nil, fmt.Errorf("venture must be a string")
 	}
 	
-	risks := []map[string]interface{}{
-		{
-			"category":    "市场风险",
-			"risk":        "需求不如预期",
-			"probability": "中",
-			"impact":      "高",
-			"mitigation":  "进行充分的市场验证，采用精益创业方法",
-		},
-		{
-			"category":    "技术风险",
-			"risk":        "技术实现困难",
-			"probability": "中",
-			"impact":      "高",
-			"mitigation":  "建立技术原型，引入技术顾问",
-		},
-		{
-			"category":    "竞争风险",
-			"risk":        "巨头进入市场",
-			"probability": "低",
-			"impact":      "极高",
-			"mitigation":  "快速建立护城河，专注细分市场",
-		},
-		{
-			"category":    "财务风险",
-			"risk":        "资金链断裂",
-			"probability": "中",
-			"impact":      "极高",
-			"mitigation":  "控制烧钱速度，多元化融资渠道",
-		},
-		{
-			"category":    "团队风险",
-			"risk":        "核心成员流失",
-			"probability": "低",
-			"impact":      "高",
-			"mitigation":  "股权激励，建立良好文化",
-		},
-		{
-			"category":    "法律风险",
-			"risk":        "合规问题",
-			"probability": "低",
-			"impact":      "中",
-			"mitigation":  "提前咨询法律顾问，建立合规体系",
-		},
+	risks, err := parseAssessedRisks(input["risks"])
+	if err != nil {
+		return nil, err
+	}
+	source := "input"
+	if len(risks) == 0 {
+		source = "template"
+		for _, risk := range riskChecklist {
+			risk.LikelihoodScore = riskScaleLabels[risk.Probability]
+			risk.ImpactScore = riskScaleLabels[risk.Impact]
+			risks = append(risks, risk)
+		}
 	}
 	
-	// Calculate risk score
+	// Calculate risk score: each 1-5 level maps onto 0.1-0.9
 	riskScore := 0.0
 	for _, risk := range risks {
-		prob := map[string]float64{"低": 0.3, "中": 0.5, "高": 0.7}
-		impact := map[string]float64{"低": 0.3, "中": 0.5, "高": 0.7, "极高": 0.9}
-		
-		p, _ := prob[risk["probability"].(string)]
-		i, _ := impact[risk["impact"].(string)]
+		p := 0.2*float64(risk.LikelihoodScore) - 0.1
+		i := 0.2*float64(risk.ImpactScore) - 0.1
 		riskScore += p * i
 	}
 	riskScore = riskScore / float64(len(risks))
@@ -332,6 +461,7 @@ func (t *RiskAssessorTool) Execute(ctx context.Context, input map[string]interfa
 		"risks":        risks,
 		"risk_score":   fmt.Sprintf("%.2f", riskScore),
 		"risk_level":   getRiskLevel(riskScore),
+		"source":       source,
 		"priority_actions": []string{
 			"优先处理高概率高影响的风险",
 			"建立风险监控机制",
@@ -340,6 +470,87 @@ func (t *RiskAssessorTool) Execute(ctx context.Context, input map[string]interfa
 	}, nil
 }
 
+// parseAssessedRisks reads the risks input; likelihood and impact may be 1-5 or a label such as 高
+func parseAssessedRisks(value interface{}) ([]AssessedRisk, error) {
+	items, ok := value.([]interface{})
+	if value == nil || (ok && len(items) == 0) {
+		return nil, nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("risks must be an array")
+	}
+	
+	risks := make([]AssessedRisk, 0, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("risks[%d] must be an object", i)
+		}
+		text, _ := fields["risk"].(string)
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("risks[%d].risk is required", i)
+		}
+		likelihood, err := riskScaleValue(fields["likelihood"])
+		if err != nil {
+			return nil, fmt.Errorf("risks[%d].likelihood: %w", i, err)
+		}
+		impact, err := riskScaleValue(fields["impact"])
+		if err != nil {
+			return nil, fmt.Errorf("risks[%d].impact: %w", i, err)
+		}
+		
+		risk := AssessedRisk{
+			Risk:            strings.TrimSpace(text),
+			LikelihoodScore: likelihood,
+			ImpactScore:     impact,
+			Probability:     riskLabel(likelihood, false),
+			Impact:          riskLabel(impact, true),
+		}
+		risk.Category, _ = fields["category"].(string)
+		risk.Mitigation, _ = fields["mitigation"].(string)
+		risk.PathID, _ = fields["path_id"].(string)
+		risk.Assumption, _ = fields["assumption"].(string)
+		risks = append(risks, risk)
+	}
+	return risks, nil
+}
+
+// riskScaleValue reads a 1-5 level given as a number or a label
+func riskScaleValue(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case float64:
+		if v >= 1 && v <= 5 {
+			return int(v + 0.5), nil
+		}
+	case int:
+		if v >= 1 && v <= 5 {
+			return v, nil
+		}
+	case string:
+		if n, ok := riskScaleLabels[strings.ToLower(strings.TrimSpace(v))]; ok {
+			return n, nil
+		}
+		var n int
+		if _, err := fmt.Sscanf(strings.TrimSpace(v), "%d", &n); err == nil && n >= 1 && n <= 5 {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("must be 1-5")
+}
+
+// riskLabel is the checklist label of a 1-5 level; only impact uses 极高
+func riskLabel(level int, impact bool) string {
+	switch {
+	case level <= 2:
+		return "低"
+	case level == 3:
+		return "中"
+	case level == 5 && impact:
+		return "极高"
+	}
+	return "高"
+}
+
 func getRiskLevel(score float64) string {
 	if score < 0.3 {
 		return "低风险"
@@ -0,0 +1,552 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrFetchBlocked is returned when a URL is rejected by the allow/deny lists or points at a private address
+var ErrFetchBlocked = errors.New("url is not allowed")
+
+// FetchConfig limits what the page fetcher downloads
+type FetchConfig struct {
+	MaxBytes     int64         // Maximum response body size read
+	MaxTextChars int           // Maximum extracted text length returned to agents
+	Timeout      time.Duration // Timeout for the whole request including redirects
+	AllowHosts   []string      // If set, only these hosts and their subdomains may be fetched
+	DenyHosts    []string      // These hosts and their subdomains are never fetched
+	AllowPrivate bool          // Allow loopback and private network addresses
+	CacheTTL     time.Duration // How long fetched pages are reused; 0 disables the cache
+	CacheSize    int           // Maximum number of cached pages
+}
+
+// LoadFetchConfig reads the fetcher limits from FETCH_* environment variables
+func LoadFetchConfig() (FetchConfig, error) {
+	config := FetchConfig{
+		MaxBytes:     2 << 20,
+		MaxTextChars: 8000,
+		Timeout:      10 * time.Second,
+		CacheTTL:     15 * time.Minute,
+		CacheSize:    200,
+		AllowHosts:   splitHosts(os.Getenv("FETCH_ALLOW_HOSTS")),
+		DenyHosts:    splitHosts(os.Getenv("FETCH_DENY_HOSTS")),
+		AllowPrivate: os.Getenv("FETCH_ALLOW_PRIVATE") == "true",
+	}
+
+	ints := []struct {
+		name   string
+		target func(int)
+	}{
+		{"FETCH_MAX_BYTES", func(v int) { config.MaxBytes = int64(v) }},
+		{"FETCH_MAX_TEXT_CHARS", func(v int) { config.MaxTextChars = v }},
+		{"FETCH_TIMEOUT_SECONDS", func(v int) { config.Timeout = time.Duration(v) * time.Second }},
+		{"FETCH_CACHE_TTL_SECONDS", func(v int) { config.CacheTTL = time.Duration(v) * time.Second }},
+	}
+	for _, setting := range ints {
+		value := os.Getenv(setting.name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return config, fmt.Errorf("invalid %s: %q", setting.name, value)
+		}
+		setting.target(n)
+	}
+
+	if config.MaxBytes <= 0 || config.Timeout <= 0 {
+		return config, fmt.Errorf("FETCH_MAX_BYTES and FETCH_TIMEOUT_SECONDS must be positive")
+	}
+	return config, nil
+}
+
+// splitHosts parses a comma-separated host list
+func splitHosts(value string) []string {
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			hosts = append(hosts, strings.TrimPrefix(host, "."))
+		}
+	}
+	return hosts
+}
+
+// Page is a fetched web page and the metadata extracted from it
+type Page struct {
+	URL           string    `json:"url"`
+	FinalURL      string    `json:"final_url"`
+	StatusCode    int       `json:"status_code"`
+	ContentType   string    `json:"content_type"`
+	Title         string    `json:"title"`
+	Author        string    `json:"author,omitempty"`
+	Published     string    `json:"published,omitempty"`
+	SiteName      string    `json:"site_name,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	Text          string    `json:"text"`
+	Truncated     bool      `json:"truncated"`
+	OutboundLinks int       `json:"outbound_links"`
+	FetchedAt     time.Time `json:"fetched_at"`
+	Cached        bool      `json:"cached"`
+}
+
+// PublishedTime parses the published date, returning false when it is missing or unrecognized
+func (p *Page) PublishedTime() (time.Time, bool) {
+	layouts := []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+		"2006/01/02",
+		time.RFC1123,
+		time.RFC1123Z,
+		"January 2, 2006",
+		"Jan 2, 2006",
+		"2 January 2006",
+	}
+	value := strings.TrimSpace(p.Published)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+type cachedPage struct {
+	page      Page
+	expiresAt time.Time
+}
+
+// PageFetcher downloads pages within the configured limits and caches the extracted results
+type PageFetcher struct {
+	config     FetchConfig
+	httpClient *http.Client
+	cache      map[string]cachedPage
+	mu         sync.Mutex
+}
+
+// NewPageFetcher creates a fetcher with the given limits
+func NewPageFetcher(config FetchConfig) *PageFetcher {
+	f := &PageFetcher{
+		config: config,
+		cache:  make(map[string]cachedPage),
+	}
+
+	dialer := &net.Dialer{
+		Timeout: config.Timeout,
+		// Checking the resolved address at dial time also covers redirects and DNS rebinding
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip != nil && !config.AllowPrivate && isPrivateIP(ip) {
+				return fmt.Errorf("%w: %s is a private address", ErrFetchBlocked, ip)
+			}
+			return nil
+		},
+	}
+
+	f.httpClient = &http.Client{
+		Timeout: config.Timeout,
+		// No proxy: a proxy would make the dial-time address check see the proxy instead of the target
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: config.Timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("stopped after 5 redirects")
+			}
+			return f.checkURL(req.URL)
+		},
+	}
+	return f
+}
+
+// isPrivateIP reports whether ip is loopback, private, link-local or unspecified
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// checkURL applies the scheme, allow/deny and private address rules to a URL. Hosts given as
+// literal IPs are rejected here; names are checked once resolved, when the connection is dialed
+func (f *PageFetcher) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: only http and https are supported", ErrFetchBlocked)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: missing host", ErrFetchBlocked)
+	}
+	if ip := net.ParseIP(host); ip != nil && !f.config.AllowPrivate && isPrivateIP(ip) {
+		return fmt.Errorf("%w: %s is a private address", ErrFetchBlocked, ip)
+	}
+	if hostMatches(host, f.config.DenyHosts) {
+		return fmt.Errorf("%w: %s is on the deny list", ErrFetchBlocked, host)
+	}
+	if len(f.config.AllowHosts) > 0 && !hostMatches(host, f.config.AllowHosts) {
+		return fmt.Errorf("%w: %s is not on the allow list", ErrFetchBlocked, host)
+	}
+	return nil
+}
+
+// hostMatches reports whether host equals or is a subdomain of any entry
+func hostMatches(host string, entries []string) bool {
+	for _, entry := range entries {
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch downloads a page, extracts its content and caches the result
+func (f *PageFetcher) Fetch(ctx context.Context, rawURL string) (*Page, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if err := f.checkURL(u); err != nil {
+		return nil, err
+	}
+	key := u.String()
+
+	if page, ok := f.cached(key); ok {
+		return page, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "FoundationSprintResearchBot/1.0")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch %s: status %d", key, resp.StatusCode)
+	}
+	if resp.ContentLength > f.config.MaxBytes {
+		return nil, fmt.Errorf("page is larger than %d bytes", f.config.MaxBytes)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if contentType == "" {
+		contentType = "text/html"
+	}
+
+	// Read one byte past the limit to tell a page that fits from one that was cut off
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	truncated := int64(len(body)) > f.config.MaxBytes
+	if truncated {
+		body = body[:f.config.MaxBytes]
+	}
+
+	page := &Page{
+		URL:         key,
+		FinalURL:    resp.Request.URL.String(),
+		StatusCode:  resp.StatusCode,
+		ContentType: contentType,
+		FetchedAt:   time.Now(),
+		Truncated:   truncated,
+	}
+
+	switch contentType {
+	case "text/html", "application/xhtml+xml":
+		extractHTML(page, string(body))
+	case "text/plain", "text/markdown":
+		page.Text = collapseSpaces(string(body))
+	default:
+		return nil, fmt.Errorf("unsupported content type: %s", contentType)
+	}
+
+	if f.config.MaxTextChars > 0 {
+		if runes := []rune(page.Text); len(runes) > f.config.MaxTextChars {
+			page.Text = string(runes[:f.config.MaxTextChars])
+			page.Truncated = true
+		}
+	}
+
+	f.store(key, page)
+	return page, nil
+}
+
+// cached returns a copy of an unexpired cached page
+func (f *PageFetcher) cached(key string) (*Page, bool) {
+	if f.config.CacheTTL <= 0 {
+		return nil, false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entry, ok := f.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(f.cache, key)
+		return nil, false
+	}
+	page := entry.page
+	page.Cached = true
+	return &page, true
+}
+
+// store caches a page, evicting the entry closest to expiry when the cache is full
+func (f *PageFetcher) store(key string, page *Page) {
+	if f.config.CacheTTL <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.config.CacheSize > 0 && len(f.cache) >= f.config.CacheSize {
+		oldest := ""
+		for k, entry := range f.cache {
+			if oldest == "" || entry.expiresAt.Before(f.cache[oldest].expiresAt) {
+				oldest = k
+			}
+		}
+		delete(f.cache, oldest)
+	}
+	f.cache[key] = cachedPage{page: *page, expiresAt: time.Now().Add(f.config.CacheTTL)}
+}
+
+// skippedElements never contain readable article text
+var skippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Button:   true,
+}
+
+// blockElements end a line of extracted text
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Section: true, atom.Article: true, atom.Blockquote: true, atom.Pre: true,
+	atom.Table: true, atom.Ul: true, atom.Ol: true, atom.Dd: true, atom.Dt: true,
+}
+
+// extractHTML fills the page's metadata and readable text from an HTML document
+func extractHTML(page *Page, document string) {
+	root, err := html.Parse(strings.NewReader(document))
+	if err != nil {
+		page.Text = collapseSpaces(document)
+		return
+	}
+
+	meta := make(map[string]string)
+	var title string
+	var content *html.Node
+	var body *html.Node
+	var timeDatetime string
+	pageHost := hostOf(page.FinalURL)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Title:
+				if title == "" && n.FirstChild != nil {
+					title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case atom.Meta:
+				key := strings.ToLower(attr(n, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(n, "name"))
+				}
+				if key == "" {
+					key = strings.ToLower(attr(n, "itemprop"))
+				}
+				if value := strings.TrimSpace(attr(n, "content")); key != "" && value != "" {
+					if _, exists := meta[key]; !exists {
+						meta[key] = value
+					}
+				}
+			case atom.Time:
+				if timeDatetime == "" {
+					timeDatetime = attr(n, "datetime")
+				}
+			case atom.A:
+				if href := attr(n, "href"); strings.HasPrefix(href, "http") {
+					if host := hostOf(href); host != "" && host != pageHost {
+						page.OutboundLinks++
+					}
+				}
+				if strings.Contains(attr(n, "rel"), "author") && meta["rel-author"] == "" {
+					meta["rel-author"] = collapseSpaces(nodeText(n))
+				}
+			case atom.Article, atom.Main:
+				if content == nil {
+					content = n
+				}
+			case atom.Body:
+				body = n
+			}
+			if attr(n, "itemprop") == "author" && meta["itemprop-author"] == "" {
+				meta["itemprop-author"] = collapseSpaces(nodeText(n))
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	page.Title = firstNonEmpty(meta["og:title"], meta["twitter:title"], title)
+	page.Author = firstNonEmpty(meta["author"], meta["article:author"], meta["dc.creator"],
+		meta["itemprop-author"], meta["rel-author"])
+	page.Published = firstNonEmpty(meta["article:published_time"], meta["datepublished"],
+		meta["date"], meta["pubdate"], meta["dc.date"], meta["publish-date"], timeDatetime,
+		meta["article:modified_time"])
+	page.SiteName = meta["og:site_name"]
+	page.Description = firstNonEmpty(meta["og:description"], meta["description"])
+
+	// Prefer the article or main element so navigation and boilerplate stay out of the text
+	if content == nil {
+		content = body
+	}
+	if content == nil {
+		content = root
+	}
+	var builder strings.Builder
+	writeText(&builder, content)
+
+	lines := strings.Split(builder.String(), "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = collapseSpaces(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	page.Text = strings.Join(kept, "\n")
+}
+
+// writeText appends the readable text under n, one block element per line
+func writeText(builder *strings.Builder, n *html.Node) {
+	if n.Type == html.ElementNode && skippedElements[n.DataAtom] {
+		return
+	}
+	if n.Type == html.TextNode {
+		builder.WriteString(n.Data)
+		builder.WriteString(" ")
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeText(builder, c)
+	}
+	if n.Type == html.ElementNode && blockElements[n.DataAtom] {
+		builder.WriteString("\n")
+	}
+}
+
+// nodeText returns all text under n
+func nodeText(n *html.Node) string {
+	var builder strings.Builder
+	writeText(&builder, n)
+	return builder.String()
+}
+
+// attr returns the value of an attribute of n
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// collapseSpaces trims s and replaces runs of whitespace with single spaces
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// FetchURLTool downloads a web page and returns its readable text and metadata
+type FetchURLTool struct {
+	BaseTool
+	fetcher *PageFetcher
+}
+
+// NewFetchURLTool creates a new page fetch tool
+func NewFetchURLTool(fetcher *PageFetcher) *FetchURLTool {
+	return &FetchURLTool{
+		BaseTool: BaseTool{
+			Name:        "fetch_url",
+			Description: "Download a web page and extract its readable text, title, author and publish date",
+			Required:    []string{"url"},
+			Optional:    []string{"max_chars"},
+		},
+		fetcher: fetcher,
+	}
+}
+
+// Execute fetches the page
+func (t *FetchURLTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	rawURL, ok := input["url"].(string)
+	if !ok {
+		return nil, fmt.Errorf("url must be a string")
+	}
+
+	maxChars, err := intInput(input, "max_chars", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := t.fetcher.Fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if runes := []rune(page.Text); maxChars > 0 && len(runes) > maxChars {
+		page.Text = string(runes[:maxChars])
+		page.Truncated = true
+	}
+	return page, nil
+}
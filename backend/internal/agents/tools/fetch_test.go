@@ -0,0 +1,415 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testFetchConfig returns limits suited to an httptest server on the loopback address
+func testFetchConfig() FetchConfig {
+	return FetchConfig{
+		MaxBytes:     1 << 20,
+		MaxTextChars: 8000,
+		Timeout:      5 * time.Second,
+		AllowPrivate: true,
+		CacheTTL:     time.Minute,
+		CacheSize:    10,
+	}
+}
+
+// pageServer serves handler and counts the requests it receives
+func pageServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *int32) {
+	t.Helper()
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func servePage(contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(body))
+	}
+}
+
+func TestFetchRejectsPrivateAddressesUpFront(t *testing.T) {
+	config := testFetchConfig()
+	config.AllowPrivate = false
+	fetcher := NewPageFetcher(config)
+
+	for _, rawURL := range []string{
+		"http://127.0.0.1/",
+		"http://10.1.2.3:8080/admin",
+		"http://192.168.0.1/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/",
+		"http://[fe80::1]/",
+		"http://0.0.0.0/",
+		"file:///etc/passwd",
+	} {
+		if _, err := fetcher.Fetch(context.Background(), rawURL); !errors.Is(err, ErrFetchBlocked) {
+			t.Errorf("Fetch(%s) err = %v, want ErrFetchBlocked", rawURL, err)
+		}
+	}
+}
+
+func TestFetchRejectsPrivateAddressesWhenDialing(t *testing.T) {
+	server, hits := pageServer(t, servePage("text/plain", "secret"))
+	config := testFetchConfig()
+	config.AllowPrivate = false
+	fetcher := NewPageFetcher(config)
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	_, err := fetcher.Fetch(context.Background(), "http://localhost:"+port+"/")
+	if !errors.Is(err, ErrFetchBlocked) {
+		t.Errorf("err = %v, want ErrFetchBlocked", err)
+	}
+	if *hits != 0 {
+		t.Errorf("server received %d requests, want 0", *hits)
+	}
+}
+
+func TestFetchIgnoresProxyEnvironment(t *testing.T) {
+	transport := NewPageFetcher(testFetchConfig()).httpClient.Transport.(*http.Transport)
+	if transport.Proxy != nil {
+		t.Error("transport uses a proxy, which would bypass the private address check")
+	}
+}
+
+func TestFetchAppliesAllowAndDenyLists(t *testing.T) {
+	server, hits := pageServer(t, servePage("text/plain", "ok"))
+
+	tests := []struct {
+		name    string
+		allow   []string
+		deny    []string
+		blocked bool
+	}{
+		{"no lists", nil, nil, false},
+		{"allowed host", []string{"127.0.0.1"}, nil, false},
+		{"host not on allow list", []string{"example.com"}, nil, true},
+		{"denied host", nil, []string{"127.0.0.1"}, true},
+		{"deny wins over allow", []string{"127.0.0.1"}, []string{"127.0.0.1"}, true},
+	}
+	for _, tt := range tests {
+		config := testFetchConfig()
+		config.AllowHosts, config.DenyHosts = tt.allow, tt.deny
+		_, err := NewPageFetcher(config).Fetch(context.Background(), server.URL)
+		if blocked := errors.Is(err, ErrFetchBlocked); blocked != tt.blocked {
+			t.Errorf("%s: err = %v, want blocked %v", tt.name, err, tt.blocked)
+		}
+	}
+	if *hits != 2 {
+		t.Errorf("server received %d requests, want 2", *hits)
+	}
+
+	if !hostMatches("docs.example.com", []string{"example.com"}) || hostMatches("badexample.com", []string{"example.com"}) {
+		t.Error("hostMatches must match the host and its subdomains only")
+	}
+}
+
+func TestFetchRechecksRedirects(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		config   func(*FetchConfig)
+	}{
+		{"denied host", "http://denied.example/", func(c *FetchConfig) { c.DenyHosts = []string{"denied.example"} }},
+		{"host not on allow list", "http://other.example/", func(c *FetchConfig) { c.AllowHosts = []string{"public.example"} }},
+		{"private address", "http://127.0.0.1/", func(c *FetchConfig) { c.AllowPrivate = false }},
+		{"metadata address", "http://169.254.169.254/", func(c *FetchConfig) { c.AllowPrivate = false }},
+		{"unsupported scheme", "ftp://public.example/file", func(c *FetchConfig) {}},
+	}
+	for _, tt := range tests {
+		server, hits := pageServer(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, tt.location, http.StatusFound)
+		})
+		config := testFetchConfig()
+		tt.config(&config)
+		fetcher := NewPageFetcher(config)
+		// Route every connection to the test server so the first hop can use a public name
+		fetcher.httpClient.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+			},
+		}
+
+		_, err := fetcher.Fetch(context.Background(), "http://public.example/start")
+		if !errors.Is(err, ErrFetchBlocked) {
+			t.Errorf("%s: err = %v, want ErrFetchBlocked", tt.name, err)
+		}
+		if *hits != 1 {
+			t.Errorf("%s: server received %d requests, want 1", tt.name, *hits)
+		}
+	}
+}
+
+func TestFetchFollowsAllowedRedirects(t *testing.T) {
+	server, _ := pageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		servePage("text/plain", "moved here")(w, r)
+	})
+
+	page, err := NewPageFetcher(testFetchConfig()).Fetch(context.Background(), server.URL+"/old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.URL != server.URL+"/old" || page.FinalURL != server.URL+"/new" || page.Text != "moved here" {
+		t.Errorf("page = %+v, want redirected page", page)
+	}
+}
+
+func TestFetchLimitsSize(t *testing.T) {
+	body := strings.Repeat("a", 500)
+	server, _ := pageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/declared" {
+			w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+			w.Write([]byte(body))
+			return
+		}
+		// Flushing first sends the body chunked, without a Content-Length
+		w.(http.Flusher).Flush()
+		w.Write([]byte(body))
+	})
+	config := testFetchConfig()
+	config.MaxBytes = 100
+	fetcher := NewPageFetcher(config)
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/declared"); err == nil || !strings.Contains(err.Error(), "larger than 100 bytes") {
+		t.Errorf("declared oversized page: err = %v, want size error", err)
+	}
+
+	page, err := fetcher.Fetch(context.Background(), server.URL+"/chunked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Text) != 100 || !page.Truncated {
+		t.Errorf("chunked page: text length %d truncated %v, want 100 and true", len(page.Text), page.Truncated)
+	}
+
+	config = testFetchConfig()
+	config.MaxTextChars = 10
+	page, err = NewPageFetcher(config).Fetch(context.Background(), server.URL+"/chunked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Text != strings.Repeat("a", 10) || !page.Truncated {
+		t.Errorf("text limit: text %q truncated %v, want 10 characters and true", page.Text, page.Truncated)
+	}
+}
+
+func TestFetchLimitsTime(t *testing.T) {
+	release := make(chan struct{})
+	server, _ := pageServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+
+	config := testFetchConfig()
+	config.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	if _, err := NewPageFetcher(config).Fetch(context.Background(), server.URL); err == nil {
+		t.Fatal("slow page: want timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetch took %v, want it to stop near the 50ms timeout", elapsed)
+	}
+}
+
+func TestFetchRejectsUnsupportedContent(t *testing.T) {
+	server, _ := pageServer(t, servePage("application/pdf", "%PDF-1.7"))
+	if _, err := NewPageFetcher(testFetchConfig()).Fetch(context.Background(), server.URL); err == nil || !strings.Contains(err.Error(), "unsupported content type") {
+		t.Errorf("err = %v, want unsupported content type", err)
+	}
+}
+
+const articleHTML = `<!DOCTYPE html>
+<html><head>
+<title>Fallback title</title>
+<meta property="og:title" content="Sprint Results">
+<meta property="og:site_name" content="Example News">
+<meta name="description" content="What the team learned">
+<meta name="author" content="Ada Lovelace">
+<meta property="article:published_time" content="2025-03-01T08:00:00Z">
+<script>var tracking = "hidden";</script>
+</head><body>
+<nav>Home | About</nav>
+<article>
+<h1>Sprint   Results</h1>
+<p>The team tested <b>three</b> approaches.</p>
+<p>See <a href="https://research.example.org/a">the study</a>, <a href="https://data.example.net/b">data</a> and <a href="/local">our notes</a>.</p>
+<style>p { color: red; }</style>
+</article>
+<footer>Copyright</footer>
+</body></html>`
+
+func TestFetchExtractsHTML(t *testing.T) {
+	server, _ := pageServer(t, servePage("text/html; charset=utf-8", articleHTML))
+
+	page, err := NewPageFetcher(testFetchConfig()).Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []struct{ field, got, want string }{
+		{"title", page.Title, "Sprint Results"},
+		{"author", page.Author, "Ada Lovelace"},
+		{"published", page.Published, "2025-03-01T08:00:00Z"},
+		{"site name", page.SiteName, "Example News"},
+		{"description", page.Description, "What the team learned"},
+		{"content type", page.ContentType, "text/html"},
+		{"text", page.Text, "Sprint Results\nThe team tested three approaches.\nSee the study , data and our notes ."},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %q, want %q", c.field, c.got, c.want)
+		}
+	}
+	if page.OutboundLinks != 2 {
+		t.Errorf("outbound links = %d, want 2", page.OutboundLinks)
+	}
+	if published, ok := page.PublishedTime(); !ok || !published.Equal(time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("PublishedTime = %v %v, want 2025-03-01 08:00 UTC", published, ok)
+	}
+}
+
+func TestFetchCachesPages(t *testing.T) {
+	server, hits := pageServer(t, servePage("text/plain", "cached body"))
+	fetcher := NewPageFetcher(testFetchConfig())
+
+	first, err := fetcher.Fetch(context.Background(), server.URL+"/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := fetcher.Fetch(context.Background(), server.URL+"/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Cached || !second.Cached || second.Text != "cached body" || *hits != 1 {
+		t.Errorf("cached %v/%v, hits %d: want the second fetch served from the cache", first.Cached, second.Cached, *hits)
+	}
+
+	// Callers may modify the returned page without affecting the cache
+	second.Text = "changed"
+	third, _ := fetcher.Fetch(context.Background(), server.URL+"/a")
+	if third.Text != "cached body" {
+		t.Errorf("cached text = %q, want it unaffected by callers", third.Text)
+	}
+
+	config := testFetchConfig()
+	config.CacheTTL = 0
+	uncached := NewPageFetcher(config)
+	uncached.Fetch(context.Background(), server.URL+"/b")
+	if page, _ := uncached.Fetch(context.Background(), server.URL+"/b"); page.Cached || *hits != 3 {
+		t.Errorf("disabled cache: cached %v, hits %d, want a fresh fetch", page.Cached, *hits)
+	}
+
+	config = testFetchConfig()
+	config.CacheSize = 1
+	small := NewPageFetcher(config)
+	small.Fetch(context.Background(), server.URL+"/c")
+	small.Fetch(context.Background(), server.URL+"/d")
+	if page, _ := small.Fetch(context.Background(), server.URL+"/c"); page.Cached {
+		t.Error("full cache: want the oldest page evicted")
+	}
+}
+
+func TestSourceValidatorScoresFetchedPage(t *testing.T) {
+	published := time.Now().AddDate(0, 0, -10).UTC().Format(time.RFC3339)
+	links := strings.Repeat(`<a href="https://cited.example.org/">source</a>`, 5)
+	server, _ := pageServer(t, servePage("text/html", `<html><head>
+<meta name="author" content="Grace Hopper">
+<meta property="og:site_name" content="Example Journal">
+<meta property="article:published_time" content="`+published+`">
+</head><body><article><p>Teams that run a foundation sprint ship faster.</p>`+links+`</article></body></html>`))
+
+	result, err := NewSourceValidatorTool(NewPageFetcher(testFetchConfig())).Execute(context.Background(), map[string]interface{}{
+		"source": server.URL,
+		"claim":  "foundation sprint ship faster",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	validation := result.(map[string]interface{})["validation"].(map[string]interface{})
+	scores := validation["credibility_assessment"].(map[string]interface{})
+
+	// author and site name without an institutional domain, recent, 5 citations, plain HTTP
+	want := map[string]float64{
+		"authority_score": 0.7,
+		"currency_score":  1.0,
+		"citation_score":  0.8,
+		"transport_score": 0.3,
+		"overall_score":   0.7,
+	}
+	for key, value := range want {
+		if scores[key] != value {
+			t.Errorf("%s = %v, want %v", key, scores[key], value)
+		}
+	}
+	if validation["reliability_rating"] != "中" {
+		t.Errorf("reliability_rating = %v, want 中", validation["reliability_rating"])
+	}
+	if claim := validation["claim_verification"].(map[string]interface{}); claim["term_coverage"] != 1.0 {
+		t.Errorf("claim term_coverage = %v, want 1", claim["term_coverage"])
+	}
+
+	if _, err := NewSourceValidatorTool(NewPageFetcher(testFetchConfig())).Execute(context.Background(), map[string]interface{}{"source": "not a url"}); err == nil {
+		t.Error("non-URL source: want error")
+	}
+}
+
+func TestCredibilitySignals(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	currency := []struct {
+		published string
+		score     float64
+	}{
+		{"2025-12-01", 1.0},
+		{"2025-06-01", 0.8},
+		{"2024-06-01", 0.6},
+		{"2022-01-01", 0.4},
+		{"2015-01-01", 0.2},
+		{"", 0.3},
+	}
+	for _, c := range currency {
+		if got := currencySignal(&Page{Published: c.published}, now).Score; got != c.score {
+			t.Errorf("currency of %q = %v, want %v", c.published, got, c.score)
+		}
+	}
+
+	institutional := authoritySignal(&Page{FinalURL: "https://www.nih.gov/report", Author: "NIH", SiteName: "NIH"})
+	if institutional.Score != 1.0 || institutional.Flag != "green" {
+		t.Errorf("institutional authority = %+v, want score 1 and green", institutional)
+	}
+	if anonymous := authoritySignal(&Page{FinalURL: "https://blog.example.com/post"}); anonymous.Score != 0.3 || anonymous.Flag != "red" {
+		t.Errorf("anonymous authority = %+v, want score 0.3 and red", anonymous)
+	}
+
+	for links, score := range map[int]float64{0: 0.2, 1: 0.5, 5: 0.8, 10: 1.0} {
+		if got := citationSignal(&Page{OutboundLinks: links}).Score; got != score {
+			t.Errorf("citations with %d links = %v, want %v", links, got, score)
+		}
+	}
+	if got := transportSignal(&Page{FinalURL: "https://example.com"}).Score; got != 1.0 {
+		t.Errorf("https transport = %v, want 1", got)
+	}
+}
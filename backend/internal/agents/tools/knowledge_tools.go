@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/models"
+)
+
+// maxKnowledgeResults caps the limit input of knowledge_search
+const maxKnowledgeResults = 10
+
+// KnowledgeSearchTool searches past sprints and uploaded team documents
+type KnowledgeSearchTool struct {
+	BaseTool
+	store *knowledge.Store
+}
+
+// NewKnowledgeSearchTool creates a new knowledge search tool
+func NewKnowledgeSearchTool(store *knowledge.Store) *KnowledgeSearchTool {
+	return &KnowledgeSearchTool{
+		BaseTool: BaseTool{
+			Name: "knowledge_search",
+			Description: "Search completed Foundation Sprints, the team's uploaded documents (interview notes, market reports) " +
+				"and room attachments. source_type is 'room', 'document' or 'attachment'; results include the title and uri to cite",
+			Required: []string{"query"},
+			Optional: []string{"limit", "source_type"},
+		},
+		store: store,
+	}
+}
+
+// Execute runs the search
+func (t *KnowledgeSearchTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	query, ok := input["query"].(string)
+	if !ok {
+		return nil, fmt.Errorf("query must be a string")
+	}
+
+	limit, err := intInput(input, "limit", 5)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxKnowledgeResults {
+		limit = maxKnowledgeResults
+	}
+
+	sourceType, _ := input["source_type"].(string)
+	switch sourceType {
+	case "", models.KnowledgeSourceRoom, models.KnowledgeSourceDocument, models.KnowledgeSourceAttachment:
+	default:
+		return nil, fmt.Errorf("source_type must be '%s', '%s' or '%s'",
+			models.KnowledgeSourceRoom, models.KnowledgeSourceDocument, models.KnowledgeSourceAttachment)
+	}
+
+	return t.store.Search(ctx, query, knowledge.SearchOptions{
+		Limit:      limit,
+		SourceType: sourceType,
+	})
+}
+
+// RegisterKnowledgeTools registers the knowledge base tools
+func RegisterKnowledgeTools(store *knowledge.Store) error {
+	tool := NewKnowledgeSearchTool(store)
+	if err := Register(tool); err != nil {
+		return fmt.Errorf("failed to register tool %s: %w", tool.GetName(), err)
+	}
+	return nil
+}
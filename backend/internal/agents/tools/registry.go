@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"sync"
 )
 
 // Tool represents an MCP tool that agents can use
@@ -13,6 +14,11 @@ type Tool interface {
 	ValidateInput(input map[string]interface{}) error
 }
 
+// SchemaProvider is implemented by tools that describe their input with a JSON Schema object
+type SchemaProvider interface {
+	InputSchema() map[string]interface{}
+}
+
 // BaseTool provides common functionality for tools
 type BaseTool struct {
 	Name        string
@@ -31,6 +37,11 @@ func (t *BaseTool) GetDescription() string {
 	return t.Description
 }
 
+// InputFields returns the required and optional input fields
+func (t *BaseTool) InputFields() (required, optional []string) {
+	return t.Required, t.Optional
+}
+
 // ValidateInput validates that required fields are present
 func (t *BaseTool) ValidateInput(input map[string]interface{}) error {
 	for _, field := range t.Required {
@@ -41,15 +52,55 @@ func (t *BaseTool) ValidateInput(input map[string]interface{}) error {
 	return nil
 }
 
-// Registry manages available tools
+// Registry manages available tools and which agents may use them
 type Registry struct {
-	tools map[string]Tool
+	tools      map[string]Tool
+	agentTools map[string][]string
+	mu         sync.RWMutex
+}
+
+// defaultAgentTools lists the tools of the built-in agents
+var defaultAgentTools = map[string][]string{
+	// Tools for expanding thinking
+	"ThinkAgent": {
+		"brainstorm",
+		"perspective_analysis",
+		"blind_spot_detection",
+		"analogy_finder",
+		"question_generator",
+		"knowledge_search",
+	},
+	// Tools for critical analysis
+	"CritiqueAgent": {
+		"assumption_checker",
+		"market_validator",
+		"feasibility_analyzer",
+		"risk_assessor",
+		"competitor_analyzer",
+		"knowledge_search",
+	},
+	// Tools for research and data collection
+	"ResearchAgent": {
+		"web_search",
+		"fetch_url",
+		"market_research",
+		"trend_analyzer",
+		"data_collector",
+		"source_validator",
+		"knowledge_search",
+	},
 }
 
 // NewRegistry creates a new tool registry
 func NewRegistry() *Registry {
+	agentTools := make(map[string][]string)
+	for agent, names := range defaultAgentTools {
+		agentTools[agent] = append([]string{}, names...)
+	}
+	
 	return &Registry{
-		tools: make(map[string]Tool),
+		tools:      make(map[string]Tool),
+		agentTools: agentTools,
 	}
 }
 
@@ -64,6 +115,9 @@ func (r *Registry) Register(tool Tool) error {
 		return fmt.Errorf("tool name cannot be empty")
 	}
 	
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
 	if _, exists := r.tools[name]; exists {
 		return fmt.Errorf("tool '%s' is already registered", name)
 	}
@@ -74,12 +128,18 @@ func (r *Registry) Register(tool Tool) error {
 
 // Get retrieves a tool by name
 func (r *Registry) Get(name string) (Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
 	tool, exists := r.tools[name]
 	return tool, exists
 }
 
 // List returns all registered tools
 func (r *Registry) List() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	
 	tools := make([]Tool, 0, len(r.tools))
 	for _, tool := range r.tools {
 		tools = append(tools, tool)
@@ -87,46 +147,45 @@ func (r *Registry) List() []Tool {
 	return tools
 }
 
-// GetToolsForAgent returns tools appropriate for a specific agent
-func (r *Registry) GetToolsForAgent(agentName string) []Tool {
-	var tools []Tool
+// SetAgentTools sets the tools an agent may use
+func (r *Registry) SetAgentTools(agentName string, names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.agentTools[agentName] = append([]string{}, names...)
+}
+
+// AddAgentTools appends tools to an agent's list, skipping names it already has
+func (r *Registry) AddAgentTools(agentName string, names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	
-	switch agentName {
-	case "ThinkAgent":
-		// Tools for expanding thinking
-		tools = append(tools, r.getToolsByNames([]string{
-			"brainstorm",
-			"perspective_analysis",
-			"blind_spot_detection",
-			"analogy_finder",
-			"question_generator",
-		})...)
-		
-	case "CritiqueAgent":
-		// Tools for critical analysis
-		tools = append(tools, r.getToolsByNames([]string{
-			"assumption_checker",
-			"market_validator",
-			"feasibility_analyzer",
-			"risk_assessor",
-			"competitor_analyzer",
-		})...)
-		
-	case "ResearchAgent":
-		// Tools for research and data collection
-		tools = append(tools, r.getToolsByNames([]string{
-			"web_search",
-			"market_research",
-			"trend_analyzer",
-			"data_collector",
-			"source_validator",
-		})...)
+	existing := make(map[string]bool)
+	for _, name := range r.agentTools[agentName] {
+		existing[name] = true
 	}
-	
-	return tools
+	for _, name := range names {
+		if !existing[name] {
+			r.agentTools[agentName] = append(r.agentTools[agentName], name)
+			existing[name] = true
+		}
+	}
+}
+
+// RemoveAgentTools removes an agent's tool list
+func (r *Registry) RemoveAgentTools(agentName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.agentTools, agentName)
+}
+
+// GetToolsForAgent returns tools appropriate for a specific agent
+func (r *Registry) GetToolsForAgent(agentName string) []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.getToolsByNames(r.agentTools[agentName])
 }
 
-// getToolsByNames retrieves multiple tools by their names
+// getToolsByNames retrieves multiple tools by their names; callers must hold the lock
 func (r *Registry) getToolsByNames(names []string) []Tool {
 	var tools []Tool
 	for _, name := range names {
@@ -158,4 +217,29 @@ func List() []Tool {
 // GetToolsForAgent returns tools for a specific agent from the default registry
 func GetToolsForAgent(agentName string) []Tool {
 	return DefaultRegistry.GetToolsForAgent(agentName)
-}
\ No newline at end of file
+}
+
+// SetAgentTools sets the tools an agent may use in the default registry
+func SetAgentTools(agentName string, names []string) {
+	DefaultRegistry.SetAgentTools(agentName, names)
+}
+
+// RemoveAgentTools removes an agent's tool list from the default registry
+func RemoveAgentTools(agentName string) {
+	DefaultRegistry.RemoveAgentTools(agentName)
+}
+// SchemaRequired returns the required property names of a JSON Schema object
+func SchemaRequired(schema map[string]interface{}) []string {
+	required := []string{}
+	switch list := schema["required"].(type) {
+	case []interface{}:
+		for _, item := range list {
+			if name, ok := item.(string); ok {
+				required = append(required, name)
+			}
+		}
+	case []string:
+		required = append(required, list...)
+	}
+	return required
+}
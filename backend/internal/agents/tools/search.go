@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Search provider names accepted in SEARCH_PROVIDER
+const (
+	SearchProviderSearXNG = "searxng"
+	SearchProviderBrave   = "brave"
+)
+
+// Normalized time ranges accepted by every provider
+const (
+	TimeRangeDay   = "day"
+	TimeRangeWeek  = "week"
+	TimeRangeMonth = "month"
+	TimeRangeYear  = "year"
+)
+
+// maxSearchResults caps num_results so a single tool call stays small enough for a prompt
+const maxSearchResults = 20
+
+// SearchRequest is a provider-independent search query
+type SearchRequest struct {
+	Query      string
+	NumResults int
+	TimeRange  string // "", day, week, month or year
+}
+
+// SearchResult is a single normalized search hit
+type SearchResult struct {
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Snippet   string `json:"snippet"`
+	Published string `json:"published,omitempty"`
+	Source    string `json:"source,omitempty"`
+}
+
+// SearchResponse is the output of the web_search tool
+type SearchResponse struct {
+	Query        string         `json:"query"`
+	Provider     string         `json:"provider"`
+	TimeRange    string         `json:"time_range,omitempty"`
+	Results      []SearchResult `json:"results"`
+	TotalResults int            `json:"total_results"`
+	SearchTime   string         `json:"search_time"`
+}
+
+// SearchProvider is a web search backend
+type SearchProvider interface {
+	Name() string
+	Search(ctx context.Context, req SearchRequest) ([]SearchResult, error)
+}
+
+// NewSearchProviderFromEnv creates the provider configured by SEARCH_PROVIDER, SEARCH_API_URL and SEARCH_API_KEY.
+// It returns nil without an error when no provider is configured.
+func NewSearchProviderFromEnv() (SearchProvider, error) {
+	name := strings.ToLower(strings.TrimSpace(os.Getenv("SEARCH_PROVIDER")))
+	if name == "" || name == "none" {
+		return nil, nil
+	}
+
+	timeout := 10 * time.Second
+	if value := os.Getenv("SEARCH_TIMEOUT_SECONDS"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid SEARCH_TIMEOUT_SECONDS: %q", value)
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	return NewSearchProvider(name, os.Getenv("SEARCH_API_URL"), os.Getenv("SEARCH_API_KEY"), &http.Client{Timeout: timeout})
+}
+
+// NewSearchProvider creates a provider by name. baseURL overrides the provider's public endpoint.
+func NewSearchProvider(name, baseURL, apiKey string, httpClient *http.Client) (SearchProvider, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	switch name {
+	case SearchProviderSearXNG:
+		if baseURL == "" {
+			return nil, fmt.Errorf("SEARCH_API_URL is required for %s", name)
+		}
+		return &searxngProvider{baseURL: baseURL, apiKey: apiKey, httpClient: httpClient}, nil
+
+	case SearchProviderBrave:
+		if apiKey == "" {
+			return nil, fmt.Errorf("SEARCH_API_KEY is required for %s", name)
+		}
+		if baseURL == "" {
+			baseURL = "https://api.search.brave.com/res/v1"
+		}
+		return &braveProvider{baseURL: baseURL, apiKey: apiKey, httpClient: httpClient}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported search provider: %s", name)
+}
+
+// NormalizeTimeRange maps the spellings LLMs use for a time range onto day, week, month or year
+func NormalizeTimeRange(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "any", "all", "anytime":
+		return "", nil
+	case "day", "d", "24h", "past_day", "past day", "today":
+		return TimeRangeDay, nil
+	case "week", "w", "7d", "past_week", "past week":
+		return TimeRangeWeek, nil
+	case "month", "m", "30d", "past_month", "past month":
+		return TimeRangeMonth, nil
+	case "year", "y", "12m", "365d", "past_year", "past year":
+		return TimeRangeYear, nil
+	}
+	return "", fmt.Errorf("time_range must be one of day, week, month or year, got %q", value)
+}
+
+// getSearchJSON sends a GET request and decodes the JSON response into out
+func getSearchJSON(ctx context.Context, httpClient *http.Client, endpoint string, headers map[string]string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("search API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode search response: %w", err)
+	}
+	return nil
+}
+
+// hostOf returns the host of a URL, used as the result source when the API has none
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(parsed.Hostname(), "www.")
+}
+
+// limitResults keeps the first n results that have a URL
+func limitResults(results []SearchResult, n int) []SearchResult {
+	limited := make([]SearchResult, 0, n)
+	for _, result := range results {
+		if result.URL == "" {
+			continue
+		}
+		if result.Source == "" {
+			result.Source = hostOf(result.URL)
+		}
+		limited = append(limited, result)
+		if len(limited) == n {
+			break
+		}
+	}
+	return limited
+}
+
+// searxngProvider queries a SearXNG instance's JSON API
+type searxngProvider struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func (p *searxngProvider) Name() string {
+	return SearchProviderSearXNG
+}
+
+func (p *searxngProvider) Search(ctx context.Context, req SearchRequest) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("q", req.Query)
+	params.Set("format", "json")
+	if req.TimeRange != "" {
+		params.Set("time_range", req.TimeRange)
+	}
+
+	// Instances behind an authenticating proxy take the key as a bearer token
+	headers := map[string]string{}
+	if p.apiKey != "" {
+		headers["Authorization"] = "Bearer " + p.apiKey
+	}
+
+	var response struct {
+		Results []struct {
+			Title         string `json:"title"`
+			URL           string `json:"url"`
+			Content       string `json:"content"`
+			PublishedDate string `json:"publishedDate"`
+		} `json:"results"`
+	}
+	if err := getSearchJSON(ctx, p.httpClient, p.baseURL+"/search?"+params.Encode(), headers, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(response.Results))
+	for _, r := range response.Results {
+		results = append(results, SearchResult{
+			Title:     r.Title,
+			URL:       r.URL,
+			Snippet:   r.Content,
+			Published: r.PublishedDate,
+		})
+	}
+	return limitResults(results, req.NumResults), nil
+}
+
+// braveProvider queries the Brave Search web API
+type braveProvider struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// braveFreshness maps normalized time ranges to Brave's freshness values
+var braveFreshness = map[string]string{
+	TimeRangeDay:   "pd",
+	TimeRangeWeek:  "pw",
+	TimeRangeMonth: "pm",
+	TimeRangeYear:  "py",
+}
+
+func (p *braveProvider) Name() string {
+	return SearchProviderBrave
+}
+
+func (p *braveProvider) Search(ctx context.Context, req SearchRequest) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("q", req.Query)
+	params.Set("count", strconv.Itoa(req.NumResults))
+	if freshness, ok := braveFreshness[req.TimeRange]; ok {
+		params.Set("freshness", freshness)
+	}
+
+	var response struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+				PageAge     string `json:"page_age"`
+				Age         string `json:"age"`
+				Profile     struct {
+					Name string `json:"name"`
+				} `json:"profile"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	headers := map[string]string{"X-Subscription-Token": p.apiKey}
+	if err := getSearchJSON(ctx, p.httpClient, p.baseURL+"/web/search?"+params.Encode(), headers, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(response.Web.Results))
+	for _, r := range response.Web.Results {
+		published := r.PageAge
+		if published == "" {
+			published = r.Age
+		}
+		results = append(results, SearchResult{
+			Title:     r.Title,
+			URL:       r.URL,
+			Snippet:   r.Description,
+			Published: published,
+			Source:    r.Profile.Name,
+		})
+	}
+	return limitResults(results, req.NumResults), nil
+}
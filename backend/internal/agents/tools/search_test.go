@@ -0,0 +1,213 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// searchServer serves body with status and records the last request
+func searchServer(t *testing.T, status int, body string) (*httptest.Server, *http.Request) {
+	t.Helper()
+	var last http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = *r.Clone(context.Background())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &last
+}
+
+func newTestProvider(t *testing.T, name, baseURL string) SearchProvider {
+	t.Helper()
+	provider, err := NewSearchProvider(name, baseURL, "test-key", nil)
+	if err != nil {
+		t.Fatalf("NewSearchProvider(%s): %v", name, err)
+	}
+	return provider
+}
+
+func TestSearXNGProviderMapsRequest(t *testing.T) {
+	tests := []struct {
+		timeRange string
+		want      url.Values
+	}{
+		{"", url.Values{"q": {"foundation sprint"}, "format": {"json"}}},
+		{TimeRangeWeek, url.Values{"q": {"foundation sprint"}, "format": {"json"}, "time_range": {"week"}}},
+		{TimeRangeYear, url.Values{"q": {"foundation sprint"}, "format": {"json"}, "time_range": {"year"}}},
+	}
+	for _, tt := range tests {
+		server, last := searchServer(t, http.StatusOK, `{"results":[]}`)
+		provider := newTestProvider(t, SearchProviderSearXNG, server.URL)
+
+		if _, err := provider.Search(context.Background(), SearchRequest{Query: "foundation sprint", NumResults: 5, TimeRange: tt.timeRange}); err != nil {
+			t.Fatalf("time range %q: %v", tt.timeRange, err)
+		}
+		if last.URL.Path != "/search" {
+			t.Errorf("time range %q: path = %s, want /search", tt.timeRange, last.URL.Path)
+		}
+		if got := last.URL.Query(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("time range %q: query = %v, want %v", tt.timeRange, got, tt.want)
+		}
+		if got := last.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q, want bearer key", got)
+		}
+	}
+}
+
+func TestSearXNGProviderNormalizesResults(t *testing.T) {
+	server, _ := searchServer(t, http.StatusOK, `{"results":[
+		{"title":"One","url":"https://www.example.com/one","content":"first","publishedDate":"2025-01-02"},
+		{"title":"No URL","url":"","content":"dropped"},
+		{"title":"Two","url":"https://news.example.org/two","content":"second"},
+		{"title":"Three","url":"https://example.net/three","content":"third"}
+	]}`)
+	provider := newTestProvider(t, SearchProviderSearXNG, server.URL)
+
+	results, err := provider.Search(context.Background(), SearchRequest{Query: "q", NumResults: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SearchResult{
+		{Title: "One", URL: "https://www.example.com/one", Snippet: "first", Published: "2025-01-02", Source: "example.com"},
+		{Title: "Two", URL: "https://news.example.org/two", Snippet: "second", Source: "news.example.org"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %+v, want %+v", results, want)
+	}
+}
+
+func TestBraveProviderMapsRequest(t *testing.T) {
+	tests := []struct {
+		timeRange string
+		freshness string
+	}{
+		{"", ""},
+		{TimeRangeDay, "pd"},
+		{TimeRangeWeek, "pw"},
+		{TimeRangeMonth, "pm"},
+		{TimeRangeYear, "py"},
+	}
+	for _, tt := range tests {
+		server, last := searchServer(t, http.StatusOK, `{"web":{"results":[]}}`)
+		provider := newTestProvider(t, SearchProviderBrave, server.URL)
+
+		if _, err := provider.Search(context.Background(), SearchRequest{Query: "design sprint", NumResults: 7, TimeRange: tt.timeRange}); err != nil {
+			t.Fatalf("time range %q: %v", tt.timeRange, err)
+		}
+		if last.URL.Path != "/web/search" {
+			t.Errorf("time range %q: path = %s, want /web/search", tt.timeRange, last.URL.Path)
+		}
+		want := url.Values{"q": {"design sprint"}, "count": {"7"}}
+		if tt.freshness != "" {
+			want.Set("freshness", tt.freshness)
+		}
+		if got := last.URL.Query(); !reflect.DeepEqual(got, want) {
+			t.Errorf("time range %q: query = %v, want %v", tt.timeRange, got, want)
+		}
+		if got := last.Header.Get("X-Subscription-Token"); got != "test-key" {
+			t.Errorf("X-Subscription-Token = %q, want test-key", got)
+		}
+	}
+}
+
+func TestBraveProviderNormalizesResults(t *testing.T) {
+	server, _ := searchServer(t, http.StatusOK, `{"web":{"results":[
+		{"title":"One","url":"https://www.example.com/one","description":"first","page_age":"2025-01-02T00:00:00","age":"2 days ago","profile":{"name":"Example"}},
+		{"title":"Two","url":"https://example.org/two","description":"second","age":"1 week ago"},
+		{"title":"Three","url":"https://example.net/three","description":"third"}
+	]}}`)
+	provider := newTestProvider(t, SearchProviderBrave, server.URL)
+
+	results, err := provider.Search(context.Background(), SearchRequest{Query: "q", NumResults: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SearchResult{
+		{Title: "One", URL: "https://www.example.com/one", Snippet: "first", Published: "2025-01-02T00:00:00", Source: "Example"},
+		{Title: "Two", URL: "https://example.org/two", Snippet: "second", Published: "1 week ago", Source: "example.org"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %+v, want %+v", results, want)
+	}
+}
+
+func TestSearchProvidersReportAPIErrors(t *testing.T) {
+	for _, name := range []string{SearchProviderSearXNG, SearchProviderBrave} {
+		t.Run(name+"/status", func(t *testing.T) {
+			server, _ := searchServer(t, http.StatusTooManyRequests, `rate limited`)
+			_, err := newTestProvider(t, name, server.URL).Search(context.Background(), SearchRequest{Query: "q", NumResults: 5})
+			if err == nil || !strings.Contains(err.Error(), "status 429") || !strings.Contains(err.Error(), "rate limited") {
+				t.Errorf("err = %v, want status 429 with body", err)
+			}
+		})
+		t.Run(name+"/malformed", func(t *testing.T) {
+			server, _ := searchServer(t, http.StatusOK, `{"results": [`)
+			_, err := newTestProvider(t, name, server.URL).Search(context.Background(), SearchRequest{Query: "q", NumResults: 5})
+			if err == nil || !strings.Contains(err.Error(), "failed to decode search response") {
+				t.Errorf("err = %v, want decode error", err)
+			}
+		})
+	}
+}
+
+func TestNewSearchProviderValidatesConfig(t *testing.T) {
+	if _, err := NewSearchProvider(SearchProviderSearXNG, "", "", nil); err == nil {
+		t.Error("searxng without SEARCH_API_URL: want error")
+	}
+	if _, err := NewSearchProvider(SearchProviderBrave, "", "", nil); err == nil {
+		t.Error("brave without SEARCH_API_KEY: want error")
+	}
+	if _, err := NewSearchProvider("bing", "", "key", nil); err == nil {
+		t.Error("bing: want unsupported provider error")
+	}
+}
+
+// fakeSearchProvider records the request passed by the web_search tool
+type fakeSearchProvider struct {
+	req SearchRequest
+}
+
+func (p *fakeSearchProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeSearchProvider) Search(ctx context.Context, req SearchRequest) ([]SearchResult, error) {
+	p.req = req
+	return nil, nil
+}
+
+func TestWebSearchToolMapsInput(t *testing.T) {
+	tests := []struct {
+		input map[string]interface{}
+		want  SearchRequest
+	}{
+		{map[string]interface{}{"query": "q"}, SearchRequest{Query: "q", NumResults: 5}},
+		{map[string]interface{}{"query": "q", "num_results": float64(50)}, SearchRequest{Query: "q", NumResults: maxSearchResults}},
+		{map[string]interface{}{"query": "q", "num_results": "0"}, SearchRequest{Query: "q", NumResults: 1}},
+		{map[string]interface{}{"query": "q", "time_range": "past week"}, SearchRequest{Query: "q", NumResults: 5, TimeRange: TimeRangeWeek}},
+		{map[string]interface{}{"query": "q", "time_range": "any"}, SearchRequest{Query: "q", NumResults: 5}},
+	}
+	for _, tt := range tests {
+		provider := &fakeSearchProvider{}
+		if _, err := NewWebSearchTool(provider).Execute(context.Background(), tt.input); err != nil {
+			t.Fatalf("input %v: %v", tt.input, err)
+		}
+		if provider.req != tt.want {
+			t.Errorf("input %v: request = %+v, want %+v", tt.input, provider.req, tt.want)
+		}
+	}
+
+	if _, err := NewWebSearchTool(&fakeSearchProvider{}).Execute(context.Background(), map[string]interface{}{"query": "q", "time_range": "decade"}); err == nil {
+		t.Error("unknown time_range: want error")
+	}
+	if _, err := NewWebSearchTool(nil).Execute(context.Background(), map[string]interface{}{"query": "q"}); err == nil {
+		t.Error("no provider: want error")
+	}
+}
@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxRoomContextRunes caps the room state included in a think tool sub-prompt
+const maxRoomContextRunes = 4000
+
+// thinkSystemPrompt frames every think tool sub-prompt
+const thinkSystemPrompt = `You are a focused helper inside a Foundation Sprint facilitation agent.
+Answer with a single JSON object that matches the requested schema exactly: no prose, no code fences, no extra keys.
+Ground your answer in the sprint room state when it is given, and write in the language of the input.`
+
+// ErrNoThinkModel is returned when no language model is configured for the think tools
+var ErrNoThinkModel = errors.New("no language model configured")
+
+// Completer runs a single prompt through a language model and returns the answer text
+type Completer interface {
+	Complete(ctx context.Context, systemPrompt, prompt string) (string, error)
+}
+
+// ThinkModel gives the think tools access to a language model. Until a completer is set
+// the tools answer from their static templates.
+type ThinkModel struct {
+	completer Completer
+	mu        sync.RWMutex
+}
+
+// NewThinkModel creates a think model without a completer
+func NewThinkModel() *ThinkModel {
+	return &ThinkModel{}
+}
+
+// SetCompleter sets the language model used by the think tools; nil restores the templates
+func (m *ThinkModel) SetCompleter(completer Completer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.completer = completer
+}
+
+// Enabled reports whether a language model is configured
+func (m *ThinkModel) Enabled() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.completer != nil
+}
+
+// thinkResult is a decoded sub-prompt answer that can check itself against its schema
+type thinkResult interface {
+	validate() error
+}
+
+// generate runs a sub-prompt and decodes the JSON answer into out
+func (m *ThinkModel) generate(ctx context.Context, task, schema string, out thinkResult) error {
+	if m == nil {
+		return ErrNoThinkModel
+	}
+	m.mu.RLock()
+	completer := m.completer
+	m.mu.RUnlock()
+	if completer == nil {
+		return ErrNoThinkModel
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(task)
+	if roomContext := RoomContextFromContext(ctx); roomContext != "" {
+		prompt.WriteString("\n\nSprint room state:\n")
+		prompt.WriteString(truncateRunes(roomContext, maxRoomContextRunes))
+	}
+	prompt.WriteString("\n\nRespond with JSON matching this schema:\n")
+	prompt.WriteString(schema)
+
+	answer, err := completer.Complete(ctx, thinkSystemPrompt, prompt.String())
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(jsonObject(answer)), out); err != nil {
+		return fmt.Errorf("invalid JSON answer: %w", err)
+	}
+	return out.validate()
+}
+
+// jsonObject returns the outermost JSON object in a model answer, dropping code fences and surrounding prose
+func jsonObject(answer string) string {
+	start := strings.Index(answer, "{")
+	end := strings.LastIndex(answer, "}")
+	if start < 0 || end < start {
+		return answer
+	}
+	return answer[start : end+1]
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
+}
+
+type roomContextKey struct{}
+
+// WithRoomContext returns a context carrying the rendered room state for the tools run with it
+func WithRoomContext(ctx context.Context, roomContext string) context.Context {
+	if roomContext == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, roomContextKey{}, roomContext)
+}
+
+// RoomContextFromContext returns the room state stored in the context
+func RoomContextFromContext(ctx context.Context) string {
+	roomContext, _ := ctx.Value(roomContextKey{}).(string)
+	return roomContext
+}
+
+// withSource tags a tool result with where it came from; fallback explains why the template was used
+func withSource(result map[string]interface{}, source string, fallback error) map[string]interface{} {
+	result["source"] = source
+	if fallback != nil && !errors.Is(fallback, ErrNoThinkModel) {
+		result["fallback_reason"] = fallback.Error()
+	}
+	return result
+}
+
+// stringList reads an input that LLMs send either as a JSON array or a comma-separated string
+func stringList(input map[string]interface{}, field string) []string {
+	var values []string
+	switch value := input[field].(type) {
+	case []string:
+		values = value
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	case string:
+		values = strings.Split(value, ",")
+	}
+
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// requireText checks that the fields of a schema item, given as name/value pairs, are not blank
+func requireText(kind string, index int, fields ...string) error {
+	for i := 0; i+1 < len(fields); i += 2 {
+		if strings.TrimSpace(fields[i+1]) == "" {
+			return fmt.Errorf("%s %d is missing %s", kind, index+1, fields[i])
+		}
+	}
+	return nil
+}
@@ -6,13 +6,17 @@ import (
 	"strings"
 )
 
+// maxThinkItems caps the items kept from a sub-prompt answer
+const maxThinkItems = 8
+
 // BrainstormTool generates ideas and perspectives
 type BrainstormTool struct {
 	BaseTool
+	model *ThinkModel
 }
 
-// NewBrainstormTool creates a new brainstorm tool
-func NewBrainstormTool() *BrainstormTool {
+// NewBrainstormTool creates a new brainstorm tool; without a model it answers from templates
+func NewBrainstormTool(model *ThinkModel) *BrainstormTool {
 	return &BrainstormTool{
 		BaseTool: BaseTool{
 			Name:        "brainstorm",
@@ -20,7 +24,36 @@ func NewBrainstormTool() *BrainstormTool {
 			Required:    []string{"topic"},
 			Optional:    []string{"constraints", "domain"},
 		},
+		model: model,
+	}
+}
+
+// brainstormSchema is the answer schema of the brainstorm sub-prompt
+const brainstormSchema = `{"ideas": [{"idea": "one concrete idea", "rationale": "why it could work for this team and customer"}]}
+Give 4 to 8 ideas.`
+
+type brainstormIdea struct {
+	Idea      string `json:"idea"`
+	Rationale string `json:"rationale"`
+}
+
+type brainstormResult struct {
+	Ideas []brainstormIdea `json:"ideas"`
+}
+
+func (r *brainstormResult) validate() error {
+	if len(r.Ideas) == 0 {
+		return fmt.Errorf("answer has no ideas")
+	}
+	for i, idea := range r.Ideas {
+		if err := requireText("idea", i, "idea", idea.Idea, "rationale", idea.Rationale); err != nil {
+			return err
+		}
 	}
+	if len(r.Ideas) > maxThinkItems {
+		r.Ideas = r.Ideas[:maxThinkItems]
+	}
+	return nil
 }
 
 // Execute runs the brainstorm tool
@@ -29,8 +62,32 @@ func (t *BrainstormTool) Execute(ctx context.Context, input map[string]interface
 	if !ok {
 		return nil, fmt.Errorf("topic must be a string")
 	}
+	constraints, _ := input["constraints"].(string)
+	domain, _ := input["domain"].(string)
+	
+	task := fmt.Sprintf("Brainstorm creative, concrete ideas for: %s", topic)
+	if constraints != "" {
+		task += fmt.Sprintf("\nConstraints: %s", constraints)
+	}
+	if domain != "" {
+		task += fmt.Sprintf("\nDomain: %s", domain)
+	}
+	
+	var result brainstormResult
+	err := t.model.generate(ctx, task, brainstormSchema, &result)
+	if err != nil {
+		return withSource(brainstormTemplate(topic, domain), "template", err), nil
+	}
 	
-	// In production, this would call an LLM or use more sophisticated logic
+	return withSource(map[string]interface{}{
+		"ideas":       result.Ideas,
+		"topic":       topic,
+		"suggestions": "Consider each idea and explore the most promising ones further",
+	}, "llm", nil), nil
+}
+
+// brainstormTemplate is the offline answer of the brainstorm tool
+func brainstormTemplate(topic, domain string) map[string]interface{} {
 	ideas := []string{
 		fmt.Sprintf("What if we approach %s from a completely opposite angle?", topic),
 		fmt.Sprintf("Consider %s from the perspective of different stakeholders", topic),
@@ -40,7 +97,7 @@ func (t *BrainstormTool) Execute(ctx context.Context, input map[string]interface
 	}
 	
 	// Add domain-specific ideas if provided
-	if domain, ok := input["domain"].(string); ok {
+	if domain != "" {
 		ideas = append(ideas, fmt.Sprintf("Apply %s best practices to %s", domain, topic))
 	}
 	
@@ -48,16 +105,17 @@ func (t *BrainstormTool) Execute(ctx context.Context, input map[string]interface
 		"ideas":       ideas,
 		"topic":       topic,
 		"suggestions": "Consider each idea and explore the most promising ones further",
-	}, nil
+	}
 }
 
 // PerspectiveAnalysisTool analyzes different perspectives
 type PerspectiveAnalysisTool struct {
 	BaseTool
+	model *ThinkModel
 }
 
-// NewPerspectiveAnalysisTool creates a new perspective analysis tool
-func NewPerspectiveAnalysisTool() *PerspectiveAnalysisTool {
+// NewPerspectiveAnalysisTool creates a new perspective analysis tool; without a model it answers from templates
+func NewPerspectiveAnalysisTool(model *ThinkModel) *PerspectiveAnalysisTool {
 	return &PerspectiveAnalysisTool{
 		BaseTool: BaseTool{
 			Name:        "perspective_analysis",
@@ -65,22 +123,76 @@ func NewPerspectiveAnalysisTool() *PerspectiveAnalysisTool {
 			Required:    []string{"problem"},
 			Optional:    []string{"stakeholders"},
 		},
+		model: model,
 	}
 }
 
+// perspectiveSchema is the answer schema of the perspective analysis sub-prompt
+const perspectiveSchema = `{"stakeholders": [{"name": "stakeholder", "needs": ["what they need"], "concerns": ["what worries them"], "risk": "how this could go wrong for them"}]}
+Cover 3 to 6 stakeholders.`
+
+type stakeholderPerspective struct {
+	Name     string   `json:"name"`
+	Needs    []string `json:"needs"`
+	Concerns []string `json:"concerns"`
+	Risk     string   `json:"risk"`
+}
+
+type perspectiveResult struct {
+	Stakeholders []stakeholderPerspective `json:"stakeholders"`
+}
+
+func (r *perspectiveResult) validate() error {
+	if len(r.Stakeholders) == 0 {
+		return fmt.Errorf("answer has no stakeholders")
+	}
+	for i, stakeholder := range r.Stakeholders {
+		if err := requireText("stakeholder", i, "name", stakeholder.Name); err != nil {
+			return err
+		}
+		if len(stakeholder.Needs) == 0 {
+			return fmt.Errorf("stakeholder %d is missing needs", i+1)
+		}
+	}
+	if len(r.Stakeholders) > maxThinkItems {
+		r.Stakeholders = r.Stakeholders[:maxThinkItems]
+	}
+	return nil
+}
+
 // Execute runs the perspective analysis
 func (t *PerspectiveAnalysisTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	problem, ok := input["problem"].(string)
 	if !ok {
 		return nil, fmt.Errorf("problem must be a string")
 	}
+	stakeholders := stringList(input, "stakeholders")
 	
-	// Default stakeholders
-	stakeholders := []string{"用户", "企业", "投资者", "员工", "社会"}
+	task := fmt.Sprintf("Analyze this problem from the perspective of each stakeholder: %s", problem)
+	if len(stakeholders) > 0 {
+		task += fmt.Sprintf("\nStakeholders to cover: %s", strings.Join(stakeholders, ", "))
+	} else {
+		task += "\nPick the stakeholders who matter most for the sprint's target customer."
+	}
 	
-	// Use custom stakeholders if provided
-	if customStakeholders, ok := input["stakeholders"].([]string); ok {
-		stakeholders = customStakeholders
+	var result perspectiveResult
+	err := t.model.generate(ctx, task, perspectiveSchema, &result)
+	if err != nil {
+		return withSource(perspectiveTemplate(problem, stakeholders), "template", err), nil
+	}
+	
+	return withSource(map[string]interface{}{
+		"problem":      problem,
+		"stakeholders": result.Stakeholders,
+		"insight":      "不同利益相关者有不同的关注点，需要平衡各方利益",
+	}, "llm", nil), nil
+}
+
+// perspectiveTemplate is the offline answer of the perspective analysis tool
+func perspectiveTemplate(problem string, stakeholders []string) map[string]interface{} {
+	// Default stakeholders
+	if len(stakeholders) == 0 {
+		stakeholders = []string{"用户", "企业", "投资者", "员工", "社会"}
 	}
 	
 	perspectives := make(map[string]interface{})
@@ -97,16 +209,17 @@ func (t *PerspectiveAnalysisTool) Execute(ctx context.Context, input map[string]
 		"problem":      problem,
 		"perspectives": perspectives,
 		"insight":      "不同利益相关者有不同的关注点，需要平衡各方利益",
-	}, nil
+	}
 }
 
 // BlindSpotDetectionTool identifies potential blind spots
 type BlindSpotDetectionTool struct {
 	BaseTool
+	model *ThinkModel
 }
 
-// NewBlindSpotDetectionTool creates a new blind spot detection tool
-func NewBlindSpotDetectionTool() *BlindSpotDetectionTool {
+// NewBlindSpotDetectionTool creates a new blind spot detection tool; without a model it answers from templates
+func NewBlindSpotDetectionTool(model *ThinkModel) *BlindSpotDetectionTool {
 	return &BlindSpotDetectionTool{
 		BaseTool: BaseTool{
 			Name:        "blind_spot_detection",
@@ -114,16 +227,67 @@ func NewBlindSpotDetectionTool() *BlindSpotDetectionTool {
 			Required:    []string{"concept"},
 			Optional:    []string{"context"},
 		},
+		model: model,
 	}
 }
 
+// blindSpotSchema is the answer schema of the blind spot sub-prompt
+const blindSpotSchema = `{"blind_spots": [{"area": "overlooked area", "question": "the question the team has not asked", "risk": "what happens if it stays unanswered"}]}
+Give 3 to 6 blind spots specific to this concept.`
+
+type blindSpot struct {
+	Area     string `json:"area"`
+	Question string `json:"question"`
+	Risk     string `json:"risk"`
+}
+
+type blindSpotResult struct {
+	BlindSpots []blindSpot `json:"blind_spots"`
+}
+
+func (r *blindSpotResult) validate() error {
+	if len(r.BlindSpots) == 0 {
+		return fmt.Errorf("answer has no blind spots")
+	}
+	for i, spot := range r.BlindSpots {
+		if err := requireText("blind spot", i, "area", spot.Area, "question", spot.Question, "risk", spot.Risk); err != nil {
+			return err
+		}
+	}
+	if len(r.BlindSpots) > maxThinkItems {
+		r.BlindSpots = r.BlindSpots[:maxThinkItems]
+	}
+	return nil
+}
+
 // Execute detects blind spots
 func (t *BlindSpotDetectionTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	concept, ok := input["concept"].(string)
 	if !ok {
 		return nil, fmt.Errorf("concept must be a string")
 	}
+	conceptContext, _ := input["context"].(string)
+	
+	task := fmt.Sprintf("Identify the blind spots a team is likely to overlook in: %s", concept)
+	if conceptContext != "" {
+		task += fmt.Sprintf("\nContext: %s", conceptContext)
+	}
+	
+	var result blindSpotResult
+	err := t.model.generate(ctx, task, blindSpotSchema, &result)
+	if err != nil {
+		return withSource(blindSpotTemplate(concept, conceptContext), "template", err), nil
+	}
 	
+	return withSource(map[string]interface{}{
+		"concept":        concept,
+		"blind_spots":    result.BlindSpots,
+		"recommendation": "仔细检查每个盲点，并制定相应的缓解措施",
+	}, "llm", nil), nil
+}
+
+// blindSpotTemplate is the offline answer of the blind spot detection tool
+func blindSpotTemplate(concept, conceptContext string) map[string]interface{} {
 	blindSpots := []map[string]string{
 		{
 			"area":     "技术可行性",
@@ -153,30 +317,29 @@ func (t *BlindSpotDetectionTool) Execute(ctx context.Context, input map[string]i
 	}
 	
 	// Add context-specific blind spots
-	if context, ok := input["context"].(string); ok {
-		if strings.Contains(strings.ToLower(context), "ai") {
-			blindSpots = append(blindSpots, map[string]string{
-				"area":     "AI伦理",
-				"question": "是否考虑了AI系统的伦理和偏见问题？",
-				"risk":     "AI偏见可能导致用户信任危机",
-			})
-		}
+	if strings.Contains(strings.ToLower(conceptContext), "ai") {
+		blindSpots = append(blindSpots, map[string]string{
+			"area":     "AI伦理",
+			"question": "是否考虑了AI系统的伦理和偏见问题？",
+			"risk":     "AI偏见可能导致用户信任危机",
+		})
 	}
 	
 	return map[string]interface{}{
-		"concept":     concept,
-		"blind_spots": blindSpots,
+		"concept":        concept,
+		"blind_spots":    blindSpots,
 		"recommendation": "仔细检查每个盲点，并制定相应的缓解措施",
-	}, nil
+	}
 }
 
 // AnalogyFinderTool finds analogies from other domains
 type AnalogyFinderTool struct {
 	BaseTool
+	model *ThinkModel
 }
 
-// NewAnalogyFinderTool creates a new analogy finder tool
-func NewAnalogyFinderTool() *AnalogyFinderTool {
+// NewAnalogyFinderTool creates a new analogy finder tool; without a model it answers from templates
+func NewAnalogyFinderTool(model *ThinkModel) *AnalogyFinderTool {
 	return &AnalogyFinderTool{
 		BaseTool: BaseTool{
 			Name:        "analogy_finder",
@@ -184,16 +347,69 @@ func NewAnalogyFinderTool() *AnalogyFinderTool {
 			Required:    []string{"concept"},
 			Optional:    []string{"target_domain"},
 		},
+		model: model,
 	}
 }
 
+// analogySchema is the answer schema of the analogy sub-prompt
+const analogySchema = `{"analogies": [{"source_industry": "industry the analogy comes from", "analogy": "the company, product or practice", "application": "how it maps onto this concept", "insight": "the lesson to take"}]}
+Give 3 to 5 analogies from industries other than the concept's own.`
+
+type analogy struct {
+	SourceIndustry string `json:"source_industry"`
+	Analogy        string `json:"analogy"`
+	Application    string `json:"application"`
+	Insight        string `json:"insight"`
+}
+
+type analogyResult struct {
+	Analogies []analogy `json:"analogies"`
+}
+
+func (r *analogyResult) validate() error {
+	if len(r.Analogies) == 0 {
+		return fmt.Errorf("answer has no analogies")
+	}
+	for i, item := range r.Analogies {
+		if err := requireText("analogy", i, "source_industry", item.SourceIndustry, "analogy", item.Analogy,
+			"application", item.Application); err != nil {
+			return err
+		}
+	}
+	if len(r.Analogies) > maxThinkItems {
+		r.Analogies = r.Analogies[:maxThinkItems]
+	}
+	return nil
+}
+
 // Execute finds analogies
 func (t *AnalogyFinderTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	concept, ok := input["concept"].(string)
 	if !ok {
 		return nil, fmt.Errorf("concept must be a string")
 	}
+	targetDomain, _ := input["target_domain"].(string)
+	
+	task := fmt.Sprintf("Find analogies from other industries that shed light on: %s", concept)
+	if targetDomain != "" {
+		task += fmt.Sprintf("\nThe concept will be applied in: %s", targetDomain)
+	}
+	
+	var result analogyResult
+	err := t.model.generate(ctx, task, analogySchema, &result)
+	if err != nil {
+		return withSource(analogyTemplate(concept), "template", err), nil
+	}
 	
+	return withSource(map[string]interface{}{
+		"concept":   concept,
+		"analogies": result.Analogies,
+		"value":     "跨领域的类比可以激发创新思维",
+	}, "llm", nil), nil
+}
+
+// analogyTemplate is the offline answer of the analogy finder tool
+func analogyTemplate(concept string) map[string]interface{} {
 	analogies := []map[string]string{
 		{
 			"domain":      "自然界",
@@ -225,16 +441,17 @@ func (t *AnalogyFinderTool) Execute(ctx context.Context, input map[string]interf
 		"concept":   concept,
 		"analogies": analogies,
 		"value":     "跨领域的类比可以激发创新思维",
-	}, nil
+	}
 }
 
 // QuestionGeneratorTool generates probing questions
 type QuestionGeneratorTool struct {
 	BaseTool
+	model *ThinkModel
 }
 
-// NewQuestionGeneratorTool creates a new question generator tool
-func NewQuestionGeneratorTool() *QuestionGeneratorTool {
+// NewQuestionGeneratorTool creates a new question generator tool; without a model it answers from templates
+func NewQuestionGeneratorTool(model *ThinkModel) *QuestionGeneratorTool {
 	return &QuestionGeneratorTool{
 		BaseTool: BaseTool{
 			Name:        "question_generator",
@@ -242,9 +459,35 @@ func NewQuestionGeneratorTool() *QuestionGeneratorTool {
 			Required:    []string{"topic"},
 			Optional:    []string{"depth_level"},
 		},
+		model: model,
 	}
 }
 
+// questionCategories are the question groups every answer must fill
+var questionCategories = []string{"foundational", "exploratory", "critical", "creative"}
+
+// questionSchema is the answer schema of the question generator sub-prompt
+const questionSchema = `{"questions": {"foundational": ["..."], "exploratory": ["..."], "critical": ["..."], "creative": ["..."]}}
+Give 2 to 4 questions in every group.`
+
+type questionResult struct {
+	Questions map[string][]string `json:"questions"`
+}
+
+func (r *questionResult) validate() error {
+	for _, category := range questionCategories {
+		if len(r.Questions[category]) == 0 {
+			return fmt.Errorf("answer has no %s questions", category)
+		}
+	}
+	for category, questions := range r.Questions {
+		if len(questions) > maxThinkItems {
+			r.Questions[category] = questions[:maxThinkItems]
+		}
+	}
+	return nil
+}
+
 // Execute generates questions
 func (t *QuestionGeneratorTool) Execute(ctx context.Context, input map[string]interface{}) (interface{}, error) {
 	topic, ok := input["topic"].(string)
@@ -252,6 +495,26 @@ func (t *QuestionGeneratorTool) Execute(ctx context.Context, input map[string]in
 		return nil, fmt.Errorf("topic must be a string")
 	}
 	
+	task := fmt.Sprintf("Write probing questions that deepen the team's understanding of: %s", topic)
+	if depth, ok := input["depth_level"]; ok && depth != nil {
+		task += fmt.Sprintf("\nDepth level: %v", depth)
+	}
+	
+	var result questionResult
+	err := t.model.generate(ctx, task, questionSchema, &result)
+	if err != nil {
+		return withSource(questionTemplate(topic), "template", err), nil
+	}
+	
+	return withSource(map[string]interface{}{
+		"topic":     topic,
+		"questions": result.Questions,
+		"usage":     "Use these questions to explore different aspects and uncover new insights",
+	}, "llm", nil), nil
+}
+
+// questionTemplate is the offline answer of the question generator tool
+func questionTemplate(topic string) map[string]interface{} {
 	questions := map[string][]string{
 		"foundational": {
 			fmt.Sprintf("What is the core problem that %s is trying to solve?", topic),
@@ -279,17 +542,18 @@ func (t *QuestionGeneratorTool) Execute(ctx context.Context, input map[string]in
 		"topic":     topic,
 		"questions": questions,
 		"usage":     "Use these questions to explore different aspects and uncover new insights",
-	}, nil
+	}
 }
 
-// RegisterThinkTools registers all thinking tools
-func RegisterThinkTools() error {
+// RegisterThinkTools registers all thinking tools. The tools run their sub-prompts through
+// the model once it has a completer and fall back to templates otherwise.
+func RegisterThinkTools(model *ThinkModel) error {
 	tools := []Tool{
-		NewBrainstormTool(),
-		NewPerspectiveAnalysisTool(),
-		NewBlindSpotDetectionTool(),
-		NewAnalogyFinderTool(),
-		NewQuestionGeneratorTool(),
+		NewBrainstormTool(model),
+		NewPerspectiveAnalysisTool(model),
+		NewBlindSpotDetectionTool(model),
+		NewAnalogyFinderTool(model),
+		NewQuestionGeneratorTool(model),
 	}
 	
 	for _, tool := range tools {
@@ -299,4 +563,4 @@ func RegisterThinkTools() error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
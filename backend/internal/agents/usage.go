@@ -0,0 +1,337 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"foundation-sprint/internal/models"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UsageScope attributes LLM calls to a room, user, agent and session
+type UsageScope struct {
+	RoomID    string
+	UserID    string
+	AgentName string
+	SessionID string
+	CallType  string
+}
+
+type usageScopeKey struct{}
+
+// WithUsageScope returns a context carrying the scope; non-empty fields override the parent scope
+func WithUsageScope(ctx context.Context, scope UsageScope) context.Context {
+	merged := UsageScopeFromContext(ctx)
+	if scope.RoomID != "" {
+		merged.RoomID = scope.RoomID
+	}
+	if scope.UserID != "" {
+		merged.UserID = scope.UserID
+	}
+	if scope.AgentName != "" {
+		merged.AgentName = scope.AgentName
+	}
+	if scope.SessionID != "" {
+		merged.SessionID = scope.SessionID
+	}
+	if scope.CallType != "" {
+		merged.CallType = scope.CallType
+	}
+	return context.WithValue(ctx, usageScopeKey{}, merged)
+}
+
+// UsageScopeFromContext returns the usage scope stored in the context
+func UsageScopeFromContext(ctx context.Context) UsageScope {
+	if scope, ok := ctx.Value(usageScopeKey{}).(UsageScope); ok {
+		return scope
+	}
+	return UsageScope{}
+}
+
+// withCallType tags the LLM calls made with the context
+func withCallType(ctx context.Context, callType string) context.Context {
+	return WithUsageScope(ctx, UsageScope{CallType: callType})
+}
+
+// UsageStore persists usage records and budgets
+type UsageStore interface {
+	Create(ctx context.Context, usage *models.LLMUsage) error
+	TotalCost(ctx context.Context, roomID string, since time.Time) (float64, error)
+	GetBudget(ctx context.Context, scope, scopeID string) (float64, bool, error)
+}
+
+// WorkspaceBudgetID identifies the deployment-wide budget
+const WorkspaceBudgetID = "default"
+
+// ErrBudgetExceeded is returned when a room or workspace has spent its budget
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// BudgetExceededError describes which budget was exceeded
+type BudgetExceededError struct {
+	Scope    string
+	ScopeID  string
+	LimitUSD float64
+	SpentUSD float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s budget exceeded: spent $%.4f of $%.4f", e.Scope, e.SpentUSD, e.LimitUSD)
+}
+
+// Is makes errors.Is(err, ErrBudgetExceeded) match
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// BudgetConfig holds default budgets in USD; zero means unlimited.
+// Room budgets cover the room's lifetime, the workspace budget covers the current calendar month.
+type BudgetConfig struct {
+	RoomUSD      float64
+	WorkspaceUSD float64
+}
+
+// LoadBudgetConfig reads default budgets from LLM_BUDGET_ROOM_USD and LLM_BUDGET_WORKSPACE_USD
+func LoadBudgetConfig() BudgetConfig {
+	return BudgetConfig{
+		RoomUSD:      envFloat("LLM_BUDGET_ROOM_USD"),
+		WorkspaceUSD: envFloat("LLM_BUDGET_WORKSPACE_USD"),
+	}
+}
+
+func envFloat(key string) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// UsageTracker prices LLM calls, records them and enforces budgets
+type UsageTracker struct {
+	store    UsageStore
+	prices   PriceTable
+	budget   BudgetConfig
+	unpriced sync.Map // models already warned about for having no price
+}
+
+// NewUsageTracker creates a usage tracker; records are dropped until a store is set
+func NewUsageTracker(prices PriceTable, budget BudgetConfig) *UsageTracker {
+	return &UsageTracker{
+		prices: prices,
+		budget: budget,
+	}
+}
+
+// SetStore sets the store used for usage records and budgets
+func (t *UsageTracker) SetStore(store UsageStore) {
+	t.store = store
+}
+
+// Prices returns the price table in use
+func (t *UsageTracker) Prices() PriceTable {
+	return t.prices
+}
+
+// Record persists the usage of a single LLM call attributed with the context's scope
+func (t *UsageTracker) Record(ctx context.Context, model string, usage TokenUsage) {
+	if t == nil || t.store == nil {
+		return
+	}
+
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+
+	cost, priced := t.prices.Cost(model, usage)
+	if !priced {
+		t.warnUnpriced(model, cost)
+	}
+
+	scope := UsageScopeFromContext(ctx)
+	record := &models.LLMUsage{
+		RoomID:           scope.RoomID,
+		UserID:           scope.UserID,
+		AgentName:        scope.AgentName,
+		SessionID:        scope.SessionID,
+		Model:            model,
+		CallType:         scope.CallType,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CostUSD:          cost,
+		Unpriced:         !priced,
+		CreatedAt:        time.Now(),
+	}
+
+	// Recording must not fail the agent request, and should survive a cancelled request context
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := t.store.Create(storeCtx, record); err != nil {
+		log.Printf("Failed to record LLM usage: %v", err)
+	}
+}
+
+// warnUnpriced logs once per model that its calls are not priced, since they count little or nothing
+// toward budgets
+func (t *UsageTracker) warnUnpriced(model string, cost float64) {
+	if _, warned := t.unpriced.LoadOrStore(model, true); warned {
+		return
+	}
+	if _, ok := t.prices[FallbackPriceKey]; ok {
+		log.Printf("Warning: no price for LLM model %q; charging the %q fallback price. Add the model to LLM_PRICING_FILE", model, FallbackPriceKey)
+		return
+	}
+	log.Printf("Warning: no price for LLM model %q; its calls cost $0 and are not limited by budgets. Add the model or a %q fallback to LLM_PRICING_FILE", model, FallbackPriceKey)
+}
+
+// Limit returns the effective budget for a scope, preferring a stored override over the default
+func (t *UsageTracker) Limit(ctx context.Context, scope, scopeID string) (float64, error) {
+	defaultLimit := t.budget.RoomUSD
+	if scope == models.BudgetScopeWorkspace {
+		defaultLimit = t.budget.WorkspaceUSD
+	}
+
+	if t.store == nil {
+		return defaultLimit, nil
+	}
+
+	limit, ok, err := t.store.GetBudget(ctx, scope, scopeID)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return limit, nil
+	}
+	return defaultLimit, nil
+}
+
+// CheckBudget returns a BudgetExceededError if the room or workspace budget is spent
+func (t *UsageTracker) CheckBudget(ctx context.Context, roomID string) error {
+	if t == nil || t.store == nil {
+		return nil
+	}
+
+	if roomID != "" {
+		limit, err := t.Limit(ctx, models.BudgetScopeRoom, roomID)
+		if err != nil {
+			return fmt.Errorf("failed to load room budget: %w", err)
+		}
+		if limit > 0 {
+			spent, err := t.store.TotalCost(ctx, roomID, time.Time{})
+			if err != nil {
+				return fmt.Errorf("failed to load room spend: %w", err)
+			}
+			if spent >= limit {
+				return &BudgetExceededError{Scope: models.BudgetScopeRoom, ScopeID: roomID, LimitUSD: limit, SpentUSD: spent}
+			}
+		}
+	}
+
+	limit, err := t.Limit(ctx, models.BudgetScopeWorkspace, WorkspaceBudgetID)
+	if err != nil {
+		return fmt.Errorf("failed to load workspace budget: %w", err)
+	}
+	if limit > 0 {
+		now := time.Now().UTC()
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		spent, err := t.store.TotalCost(ctx, "", monthStart)
+		if err != nil {
+			return fmt.Errorf("failed to load workspace spend: %w", err)
+		}
+		if spent >= limit {
+			return &BudgetExceededError{Scope: models.BudgetScopeWorkspace, ScopeID: WorkspaceBudgetID, LimitUSD: limit, SpentUSD: spent}
+		}
+	}
+
+	return nil
+}
+
+// MeteredLLMClient wraps an LLMClient and records the usage of every call
+type MeteredLLMClient struct {
+	client  LLMClient
+	tracker *UsageTracker
+}
+
+// NewMeteredLLMClient creates a metered LLM client
+func NewMeteredLLMClient(client LLMClient, tracker *UsageTracker) *MeteredLLMClient {
+	return &MeteredLLMClient{
+		client:  client,
+		tracker: tracker,
+	}
+}
+
+// Complete implements the LLMClient interface
+func (m *MeteredLLMClient) Complete(ctx context.Context, prompt string, options ...LLMOption) (*LLMResponse, error) {
+	response, err := m.client.Complete(ctx, prompt, options...)
+	if err != nil {
+		return nil, err
+	}
+	m.tracker.Record(ctx, response.Model, response.Usage)
+	return response, nil
+}
+
+// CompleteWithTools implements the LLMClient interface
+func (m *MeteredLLMClient) CompleteWithTools(ctx context.Context, prompt string, tools []Tool, options ...LLMOption) (*LLMResponse, error) {
+	response, err := m.client.CompleteWithTools(ctx, prompt, tools, options...)
+	if err != nil {
+		return nil, err
+	}
+	m.tracker.Record(ctx, response.Model, response.Usage)
+	return response, nil
+}
+
+// Stream implements the LLMClient interface. Streamed responses carry no usage data, so the call is
+// checked against the scope's room budget up front and recorded with estimated token counts once
+// the stream ends
+func (m *MeteredLLMClient) Stream(ctx context.Context, prompt string, options ...LLMOption) (<-chan string, error) {
+	if err := m.tracker.CheckBudget(ctx, UsageScopeFromContext(ctx).RoomID); err != nil {
+		return nil, err
+	}
+
+	chunks, err := m.client.Stream(ctx, prompt, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &LLMConfig{}
+	for _, option := range options {
+		option(config)
+	}
+	model := config.Model
+	if model == "" {
+		if named, ok := m.client.(interface{ Model() string }); ok {
+			model = named.Model()
+		}
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		var completion strings.Builder
+		for chunk := range chunks {
+			completion.WriteString(chunk)
+			// Keep draining after the caller gives up so the upstream stream can finish
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+			}
+		}
+		m.tracker.Record(ctx, model, TokenUsage{
+			PromptTokens:     estimateTokens(config.SystemPrompt) + estimateTokens(prompt),
+			CompletionTokens: estimateTokens(completion.String()),
+		})
+	}()
+	return out, nil
+}
+
+// addUsage accumulates token usage
+func addUsage(total *TokenUsage, usage TokenUsage) {
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+}
@@ -0,0 +1,165 @@
+// Package attachments stores room evidence files and ingests their text into the knowledge base.
+package attachments
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Supported formats
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatPDF      = "pdf"
+)
+
+// ErrUnsupportedFormat is returned for files that cannot be ingested
+var ErrUnsupportedFormat = errors.New("unsupported file type: upload .txt, .md, .csv or .pdf")
+
+// DetectFormat picks the format from the file extension, falling back to the content type
+func DetectFormat(filename, contentType string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt", ".text":
+		return FormatText, nil
+	case ".md", ".markdown":
+		return FormatMarkdown, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".pdf":
+		return FormatPDF, nil
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch mediaType {
+	case "text/plain":
+		return FormatText, nil
+	case "text/markdown":
+		return FormatMarkdown, nil
+	case "text/csv":
+		return FormatCSV, nil
+	case "application/pdf":
+		return FormatPDF, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// ContentType returns the canonical content type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// ExtractText returns the text of a file, with blank lines between paragraphs so chunks
+// break at natural boundaries
+func ExtractText(format string, data []byte) (string, error) {
+	switch format {
+	case FormatText, FormatMarkdown:
+		data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("file is not UTF-8 text")
+		}
+		return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+	case FormatCSV:
+		return extractCSV(data)
+	case FormatPDF:
+		return extractPDF(data)
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// extractCSV renders each row as "column: value" pairs so a chunk keeps the headers of its rows
+func extractCSV(data []byte) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("file is not UTF-8 text")
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	var rows []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		fields := make([]string, 0, len(record))
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			column := fmt.Sprintf("column %d", i+1)
+			if i < len(header) && strings.TrimSpace(header[i]) != "" {
+				column = strings.TrimSpace(header[i])
+			}
+			fields = append(fields, column+": "+value)
+		}
+		if len(fields) > 0 {
+			rows = append(rows, strings.Join(fields, "\n"))
+		}
+	}
+
+	return strings.Join(rows, "\n\n"), nil
+}
+
+// extractPDF returns the plain text of every page. Scanned PDFs without a text layer yield no text.
+func extractPDF(data []byte) (text string, err error) {
+	// The PDF parser panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("invalid PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("invalid PDF: %w", err)
+	}
+
+	var pages []string
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		content, err := page.GetPlainText(nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to read PDF page %d: %w", i, err)
+		}
+		if content = strings.TrimSpace(content); content != "" {
+			pages = append(pages, content)
+		}
+	}
+
+	return strings.Join(pages, "\n\n"), nil
+}
@@ -0,0 +1,267 @@
+package attachments
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/models"
+	"foundation-sprint/internal/storage"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// defaultMaxBytes is the upload limit when ATTACHMENT_MAX_BYTES is not set
+const defaultMaxBytes = 20 << 20
+
+// processTimeout bounds the extraction and indexing of one attachment
+const processTimeout = 5 * time.Minute
+
+// Ingestion stages reported in progress events
+const (
+	StageStored     = "stored"
+	StageExtracting = "extracting"
+	StageIndexing   = "indexing"
+	StageReady      = "ready"
+	StageFailed     = "failed"
+)
+
+// ProgressEvent is broadcast to the room as "attachment_progress" while an attachment is ingested
+type ProgressEvent struct {
+	AttachmentID string `json:"attachment_id"`
+	RoomID       string `json:"room_id"`
+	CardID       string `json:"card_id,omitempty"`
+	Filename     string `json:"filename"`
+	Status       string `json:"status"`
+	Stage        string `json:"stage"`
+	Progress     int    `json:"progress"` // 0-100
+	ChunkCount   int    `json:"chunk_count,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Notifier sends room events to connected clients, e.g. handlers.BroadcastToRoom
+type Notifier func(roomID, msgType string, data interface{})
+
+// MaxUploadBytes returns the upload size limit from ATTACHMENT_MAX_BYTES
+func MaxUploadBytes() int64 {
+	if value, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_BYTES"), 10, 64); err == nil && value > 0 {
+		return value
+	}
+	return defaultMaxBytes
+}
+
+// Service stores attachments and ingests them into the knowledge base in the background
+type Service struct {
+	db        database.Database
+	blobs     storage.BlobStore
+	knowledge *knowledge.Store
+	notify    Notifier
+	maxBytes  int64
+}
+
+// NewService creates an attachment service
+func NewService(db database.Database, blobs storage.BlobStore, store *knowledge.Store, notify Notifier) *Service {
+	if notify == nil {
+		notify = func(string, string, interface{}) {}
+	}
+	return &Service{
+		db:        db,
+		blobs:     blobs,
+		knowledge: store,
+		notify:    notify,
+		maxBytes:  MaxUploadBytes(),
+	}
+}
+
+// UploadRequest describes an uploaded file
+type UploadRequest struct {
+	RoomID      string
+	CardID      string
+	UserID      string
+	Filename    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// Upload stores the file and starts ingesting it. The returned attachment is still processing;
+// progress is reported to the room until it is ready or failed.
+func (s *Service) Upload(ctx context.Context, req UploadRequest) (*models.Attachment, error) {
+	filename := sanitizeFilename(req.Filename)
+	format, err := DetectFormat(filename, req.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	if req.Size > s.maxBytes {
+		return nil, fmt.Errorf("file exceeds the %d byte limit", s.maxBytes)
+	}
+
+	if _, err := s.db.Rooms().Get(ctx, req.RoomID); err != nil {
+		return nil, fmt.Errorf("room not found")
+	}
+
+	id := uuid.New().String()
+	attachment := &models.Attachment{
+		ID:          id,
+		RoomID:      req.RoomID,
+		CardID:      req.CardID,
+		Filename:    filename,
+		ContentType: ContentType(format),
+		Size:        req.Size,
+		BlobKey:     fmt.Sprintf("rooms/%s/attachments/%s%s", req.RoomID, id, strings.ToLower(filepath.Ext(filename))),
+		Status:      models.AttachmentProcessing,
+		UploadedBy:  req.UserID,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.blobs.Put(ctx, attachment.BlobKey, req.Body, req.Size, attachment.ContentType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+	if err := s.db.Attachments().Create(ctx, attachment); err != nil {
+		s.blobs.Delete(context.Background(), attachment.BlobKey)
+		return nil, err
+	}
+
+	s.report(attachment, StageStored, 10, "")
+	go s.process(attachment)
+	return attachment, nil
+}
+
+// Reprocess ingests a stored attachment again, e.g. after a failure or a change of embedding model
+func (s *Service) Reprocess(ctx context.Context, id string) (*models.Attachment, error) {
+	attachment, err := s.db.Attachments().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Attachments().UpdateStatus(ctx, id, models.AttachmentProcessing, "", attachment.ChunkCount); err != nil {
+		return nil, err
+	}
+	attachment.Status = models.AttachmentProcessing
+	attachment.Error = ""
+
+	go s.process(attachment)
+	return attachment, nil
+}
+
+// Open returns the stored file
+func (s *Service) Open(ctx context.Context, attachment *models.Attachment) (io.ReadCloser, error) {
+	return s.blobs.Get(ctx, attachment.BlobKey)
+}
+
+// Delete removes the attachment, its file and its indexed chunks
+func (s *Service) Delete(ctx context.Context, id string) error {
+	attachment, err := s.db.Attachments().Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.knowledge.DeleteSource(ctx, models.KnowledgeSourceAttachment, id); err != nil {
+		return err
+	}
+	if err := s.blobs.Delete(ctx, attachment.BlobKey); err != nil {
+		return err
+	}
+	return s.db.Attachments().Delete(ctx, id)
+}
+
+// process extracts the text of an attachment and indexes it
+func (s *Service) process(attachment *models.Attachment) {
+	ctx, cancel := context.WithTimeout(context.Background(), processTimeout)
+	defer cancel()
+
+	chunkCount, err := s.ingest(ctx, attachment)
+	if err != nil {
+		log.Printf("Failed to ingest attachment %s (%s): %v", attachment.ID, attachment.Filename, err)
+		if updateErr := s.db.Attachments().UpdateStatus(ctx, attachment.ID, models.AttachmentFailed, err.Error(), 0); updateErr != nil {
+			log.Printf("Failed to record attachment %s failure: %v", attachment.ID, updateErr)
+		}
+		attachment.Status = models.AttachmentFailed
+		s.report(attachment, StageFailed, 100, err.Error())
+		return
+	}
+
+	if err := s.db.Attachments().UpdateStatus(ctx, attachment.ID, models.AttachmentReady, "", chunkCount); err != nil {
+		log.Printf("Failed to record attachment %s result: %v", attachment.ID, err)
+	}
+	attachment.Status = models.AttachmentReady
+	attachment.ChunkCount = chunkCount
+	s.report(attachment, StageReady, 100, "")
+}
+
+func (s *Service) ingest(ctx context.Context, attachment *models.Attachment) (int, error) {
+	s.report(attachment, StageExtracting, 20, "")
+
+	format, err := DetectFormat(attachment.Filename, attachment.ContentType)
+	if err != nil {
+		return 0, err
+	}
+
+	reader, err := s.blobs.Get(ctx, attachment.BlobKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, s.maxBytes+1))
+	reader.Close()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	text, err := ExtractText(format, data)
+	if err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(text) == "" {
+		return 0, fmt.Errorf("no text found in file; scanned PDFs need OCR before upload")
+	}
+
+	// Embedding takes the remaining progress from 40 to 95
+	s.report(attachment, StageIndexing, 40, "")
+	document, err := s.knowledge.IndexAttachment(ctx, attachment, text, func(done, total int) {
+		s.report(attachment, StageIndexing, 40+55*done/total, "")
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to index text: %w", err)
+	}
+	return document.ChunkCount, nil
+}
+
+func (s *Service) report(attachment *models.Attachment, stage string, progress int, errorMessage string) {
+	s.notify(attachment.RoomID, "attachment_progress", ProgressEvent{
+		AttachmentID: attachment.ID,
+		RoomID:       attachment.RoomID,
+		CardID:       attachment.CardID,
+		Filename:     attachment.Filename,
+		Status:       attachment.Status,
+		Stage:        stage,
+		Progress:     progress,
+		ChunkCount:   attachment.ChunkCount,
+		Error:        errorMessage,
+	})
+}
+
+// sanitizeFilename keeps the base name without control characters or path separators
+func sanitizeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '/' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." {
+		name = "attachment"
+	}
+	if runes := []rune(name); len(runes) > 200 {
+		ext := filepath.Ext(name)
+		name = string(runes[:200-len([]rune(ext))]) + ext
+	}
+	return name
+}
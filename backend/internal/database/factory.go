@@ -76,10 +76,54 @@ func (s *sqliteDB) Votes() VoteRepository {
 	return &sqliteVoteRepo{db: s.db}
 }
 
+func (s *sqliteDB) VoteSessions() VoteSessionRepository {
+	return &sqliteVoteSessionRepo{db: s.db}
+}
+
 func (s *sqliteDB) Sessions() SessionRepository {
 	return &sqliteSessionRepo{db: s.db}
 }
 
+func (s *sqliteDB) Proposals() ProposalRepository {
+	return &sqliteProposalRepo{db: s.db}
+}
+
+func (s *sqliteDB) Usage() UsageRepository {
+	return &sqliteUsageRepo{db: s.db}
+}
+
+func (s *sqliteDB) Knowledge() KnowledgeRepository {
+	return &sqliteKnowledgeRepo{db: s.db}
+}
+
+func (s *sqliteDB) Attachments() AttachmentRepository {
+	return &sqliteAttachmentRepo{db: s.db}
+}
+
+func (s *sqliteDB) Risks() RiskRepository {
+	return &sqliteRiskRepo{db: s.db}
+}
+
+func (s *sqliteDB) Hypotheses() HypothesisRepository {
+	return &sqliteHypothesisRepo{db: s.db}
+}
+
+func (s *sqliteDB) Interviews() InterviewRepository {
+	return &sqliteInterviewRepo{db: s.db}
+}
+
+func (s *sqliteDB) Scorecards() ScorecardRepository {
+	return &sqliteScorecardRepo{db: s.db}
+}
+
+func (s *sqliteDB) Audit() AuditRepository {
+	return &sqliteAuditRepo{db: s.db}
+}
+
+func (s *sqliteDB) Templates() TemplateRepository {
+	return &sqliteTemplateRepo{db: s.db}
+}
+
 func (s *sqliteDB) Migrate(ctx context.Context) error {
 	migrations := []string{
 		// Rooms table
@@ -92,7 +136,8 @@ func (s *sqliteDB) Migrate(ctx context.Context) error {
 			status TEXT DEFAULT 'foundation',
 			foundation_data TEXT,
 			differentiation_data TEXT,
-			approach_data TEXT
+			approach_data TEXT,
+			settings_data TEXT
 		)`,
 		
 		// Votes table
@@ -108,6 +153,23 @@ func (s *sqliteDB) Migrate(ctx context.Context) error {
 			UNIQUE(room_id, user_id, vote_type)
 		)`,
 		
+		// Vote sessions table
+		`CREATE TABLE IF NOT EXISTS vote_sessions (
+			id TEXT PRIMARY KEY,
+			room_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			description TEXT,
+			vote_type TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'active',
+			created_by TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			ended_at TIMESTAMP,
+			options_data TEXT,
+			settings_data TEXT,
+			decision_data TEXT,
+			FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+		)`,
+		
 		// Sessions table
 		`CREATE TABLE IF NOT EXISTS sessions (
 			session_id TEXT PRIMARY KEY,
@@ -121,15 +183,222 @@ func (s *sqliteDB) Migrate(ctx context.Context) error {
 			expires_at TIMESTAMP NOT NULL
 		)`,
 		
+		// LLM usage table
+		`CREATE TABLE IF NOT EXISTS llm_usage (
+			id TEXT PRIMARY KEY,
+			room_id TEXT,
+			user_id TEXT,
+			agent_name TEXT,
+			session_id TEXT,
+			model TEXT NOT NULL,
+			call_type TEXT,
+			prompt_tokens INTEGER NOT NULL DEFAULT 0,
+			completion_tokens INTEGER NOT NULL DEFAULT 0,
+			total_tokens INTEGER NOT NULL DEFAULT 0,
+			cost_usd REAL NOT NULL DEFAULT 0,
+			unpriced INTEGER NOT NULL DEFAULT 0,
+			usage_date TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		
+		// Usage budgets table
+		`CREATE TABLE IF NOT EXISTS usage_budgets (
+			scope TEXT NOT NULL,
+			scope_id TEXT NOT NULL,
+			limit_usd REAL NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (scope, scope_id)
+		)`,
+		
+		// Agent proposals table
+		`CREATE TABLE IF NOT EXISTS proposals (
+			id TEXT PRIMARY KEY,
+			room_id TEXT NOT NULL,
+			type TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			agent_name TEXT NOT NULL,
+			requested_by TEXT,
+			rationale TEXT,
+			payload TEXT,
+			decided_by TEXT,
+			decided_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+		)`,
+		
+		// Knowledge base documents table
+		`CREATE TABLE IF NOT EXISTS knowledge_documents (
+			id TEXT PRIMARY KEY,
+			source_type TEXT NOT NULL,
+			source_id TEXT,
+			room_id TEXT,
+			title TEXT NOT NULL,
+			content TEXT,
+			created_by TEXT,
+			chunk_count INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		
+		// Knowledge base chunks table, embeddings are little-endian float32 blobs
+		`CREATE TABLE IF NOT EXISTS knowledge_chunks (
+			id TEXT PRIMARY KEY,
+			document_id TEXT NOT NULL,
+			ordinal INTEGER NOT NULL,
+			text TEXT NOT NULL,
+			embedding BLOB,
+			embedding_model TEXT,
+			FOREIGN KEY (document_id) REFERENCES knowledge_documents(id) ON DELETE CASCADE
+		)`,
+		
+		// Room attachments table, file contents live in the blob store
+		`CREATE TABLE IF NOT EXISTS attachments (
+			id TEXT PRIMARY KEY,
+			room_id TEXT NOT NULL,
+			card_id TEXT,
+			filename TEXT NOT NULL,
+			content_type TEXT,
+			size INTEGER NOT NULL DEFAULT 0,
+			blob_key TEXT NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT,
+			chunk_count INTEGER NOT NULL DEFAULT 0,
+			uploaded_by TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			processed_at TIMESTAMP,
+			FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+		)`,
+		
+		// Room risk register
+		`CREATE TABLE IF NOT EXISTS risks (
+			id TEXT PRIMARY KEY,
+			room_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			description TEXT,
+			category TEXT,
+			likelihood INTEGER NOT NULL,
+			impact INTEGER NOT NULL,
+			score INTEGER NOT NULL,
+			level TEXT NOT NULL,
+			owner TEXT,
+			mitigation TEXT,
+			status TEXT NOT NULL,
+			path_id TEXT,
+			assumption TEXT,
+			source TEXT NOT NULL,
+			agent_name TEXT,
+			created_by TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+		)`,
+		
+		// Founding hypotheses
+		`CREATE TABLE IF NOT EXISTS hypotheses (
+			id TEXT PRIMARY KEY,
+			room_id TEXT NOT NULL,
+			statement TEXT NOT NULL,
+			customer TEXT,
+			problem TEXT,
+			approach TEXT,
+			path_id TEXT,
+			competition TEXT,
+			differentiation TEXT,
+			confidence TEXT NOT NULL,
+			validation_method TEXT,
+			success_metric TEXT,
+			status TEXT NOT NULL,
+			evidence TEXT,
+			source TEXT NOT NULL,
+			agent_name TEXT,
+			created_by TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+		)`,
+		
+		// Design Sprint test interviews and interviewee scorecards
+		`CREATE TABLE IF NOT EXISTS interviews (
+			id TEXT PRIMARY KEY,
+			room_id TEXT NOT NULL,
+			title TEXT NOT NULL,
+			interviewer TEXT,
+			conducted_at TIMESTAMP NOT NULL,
+			notes TEXT,
+			created_by TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+		)`,
+		
+		`CREATE TABLE IF NOT EXISTS scorecards (
+			id TEXT PRIMARY KEY,
+			interview_id TEXT NOT NULL,
+			room_id TEXT NOT NULL,
+			interviewee TEXT NOT NULL,
+			segment TEXT,
+			hypothesis_ids TEXT NOT NULL,
+			customer_fit TEXT,
+			problem_fit TEXT,
+			solution_appeal TEXT,
+			differentiation TEXT,
+			overall_resonance TEXT,
+			notes TEXT,
+			created_by TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (interview_id) REFERENCES interviews(id) ON DELETE CASCADE,
+			FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+		)`,
+		
+		// Append-only room audit log
+		`CREATE TABLE IF NOT EXISTS audit_events (
+			id TEXT PRIMARY KEY,
+			room_id TEXT NOT NULL,
+			actor TEXT,
+			action TEXT NOT NULL,
+			target_type TEXT NOT NULL,
+			target_id TEXT,
+			details TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+		)`,
+		
+		// Room templates for recurring industry playbooks
+		`CREATE TABLE IF NOT EXISTS room_templates (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT,
+			industry TEXT,
+			template_data TEXT NOT NULL,
+			created_by TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		
 		// Indexes
 		`CREATE INDEX IF NOT EXISTS idx_rooms_created_by ON rooms(created_by)`,
 		`CREATE INDEX IF NOT EXISTS idx_rooms_status ON rooms(status)`,
 		`CREATE INDEX IF NOT EXISTS idx_votes_room_id ON votes(room_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_votes_room_type ON votes(room_id, vote_type)`,
+		`CREATE INDEX IF NOT EXISTS idx_vote_sessions_room ON vote_sessions(room_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_sessions_agent ON sessions(agent_name)`,
 		`CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_sessions_room ON sessions(room_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_sessions_expires ON sessions(expires_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_llm_usage_room ON llm_usage(room_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_llm_usage_date ON llm_usage(usage_date)`,
+		`CREATE INDEX IF NOT EXISTS idx_proposals_room_status ON proposals(room_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_knowledge_documents_source ON knowledge_documents(source_type, source_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_knowledge_documents_room ON knowledge_documents(room_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_knowledge_chunks_document ON knowledge_chunks(document_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_attachments_room ON attachments(room_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_risks_room_status ON risks(room_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_hypotheses_room_status ON hypotheses(room_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_interviews_room ON interviews(room_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_scorecards_interview ON scorecards(interview_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_scorecards_room ON scorecards(room_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_events_room ON audit_events(room_id, created_at)`,
 	}
 	
 	for _, migration := range migrations {
@@ -138,6 +407,53 @@ func (s *sqliteDB) Migrate(ctx context.Context) error {
 		}
 	}
 	
+	// Columns added to tables created by earlier versions
+	columns := []struct{ table, name, definition string }{
+		{"vote_sessions", "settings_data", "TEXT"},
+		{"vote_sessions", "decision_data", "TEXT"},
+		{"rooms", "settings_data", "TEXT"},
+		{"llm_usage", "unpriced", "INTEGER NOT NULL DEFAULT 0"},
+	}
+	for _, column := range columns {
+		if err := addColumnIfMissing(ctx, s.db, column.table, column.name, column.definition); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+	}
+	
+	if err := migrateFoundationCards(ctx, s.db); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	
+	return nil
+}
+
+// addColumnIfMissing adds a column to an existing table; SQLite has no ADD COLUMN IF NOT EXISTS
+func addColumnIfMissing(ctx context.Context, db dbExecutor, table, column, definition string) error {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, primaryKey int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+			return fmt.Errorf("failed to scan columns of %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	rows.Close()
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
 	return nil
 }
 
@@ -163,10 +479,54 @@ func (t *sqliteTx) Votes() VoteRepository {
 	return &sqliteVoteRepo{db: t.tx}
 }
 
+func (t *sqliteTx) VoteSessions() VoteSessionRepository {
+	return &sqliteVoteSessionRepo{db: t.tx}
+}
+
 func (t *sqliteTx) Sessions() SessionRepository {
 	return &sqliteSessionRepo{db: t.tx}
 }
 
+func (t *sqliteTx) Proposals() ProposalRepository {
+	return &sqliteProposalRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Usage() UsageRepository {
+	return &sqliteUsageRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Knowledge() KnowledgeRepository {
+	return &sqliteKnowledgeRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Attachments() AttachmentRepository {
+	return &sqliteAttachmentRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Risks() RiskRepository {
+	return &sqliteRiskRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Hypotheses() HypothesisRepository {
+	return &sqliteHypothesisRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Interviews() InterviewRepository {
+	return &sqliteInterviewRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Scorecards() ScorecardRepository {
+	return &sqliteScorecardRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Audit() AuditRepository {
+	return &sqliteAuditRepo{db: t.tx}
+}
+
+func (t *sqliteTx) Templates() TemplateRepository {
+	return &sqliteTemplateRepo{db: t.tx}
+}
+
 // dbExecutor interface for both *sql.DB and *sql.Tx
 type dbExecutor interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"foundation-sprint/internal/models"
 	"time"
 )
@@ -19,7 +20,18 @@ type Database interface {
 	// Repository getters
 	Rooms() RoomRepository
 	Votes() VoteRepository
+	VoteSessions() VoteSessionRepository
 	Sessions() SessionRepository
+	Usage() UsageRepository
+	Proposals() ProposalRepository
+	Knowledge() KnowledgeRepository
+	Attachments() AttachmentRepository
+	Risks() RiskRepository
+	Hypotheses() HypothesisRepository
+	Interviews() InterviewRepository
+	Scorecards() ScorecardRepository
+	Audit() AuditRepository
+	Templates() TemplateRepository
 	
 	// Migration
 	Migrate(ctx context.Context) error
@@ -33,7 +45,18 @@ type Transaction interface {
 	// Repository getters within transaction
 	Rooms() RoomRepository
 	Votes() VoteRepository
+	VoteSessions() VoteSessionRepository
 	Sessions() SessionRepository
+	Usage() UsageRepository
+	Proposals() ProposalRepository
+	Knowledge() KnowledgeRepository
+	Attachments() AttachmentRepository
+	Risks() RiskRepository
+	Hypotheses() HypothesisRepository
+	Interviews() InterviewRepository
+	Scorecards() ScorecardRepository
+	Audit() AuditRepository
+	Templates() TemplateRepository
 }
 
 // RoomRepository defines operations for Room entities
@@ -67,6 +90,9 @@ type RoomRepository interface {
 	
 	// UpdateStatus updates the room status
 	UpdateStatus(ctx context.Context, roomID string, status string) error
+	
+	// UpdateSettings updates the room agenda and agent settings
+	UpdateSettings(ctx context.Context, roomID string, settings *models.RoomSettings) error
 }
 
 // VoteRepository defines operations for Vote entities
@@ -93,6 +119,23 @@ type VoteRepository interface {
 	CountByOption(ctx context.Context, roomID string, voteType string) (map[string]int, error)
 }
 
+// VoteSessionRepository defines operations for voting sessions (models.Vote with options and ballots)
+type VoteSessionRepository interface {
+	// Create creates a new voting session
+	Create(ctx context.Context, vote *models.Vote) error
+	
+	// Get retrieves a voting session by ID
+	Get(ctx context.Context, id string) (*models.Vote, error)
+	
+	// GetByRoom retrieves all voting sessions for a room, oldest first
+	GetByRoom(ctx context.Context, roomID string) ([]*models.Vote, error)
+	
+	// Update updates a voting session including its options and ballots
+	Update(ctx context.Context, vote *models.Vote) error
+	
+	// Delete deletes a voting session
+	Delete(ctx context.Context, id string) error
+}
 
 // SessionRepository defines operations for Session entities
 type SessionRepository interface {
@@ -127,6 +170,172 @@ type SessionRepository interface {
 	UpdateExpiry(ctx context.Context, sessionID string, expiresAt time.Time) error
 }
 
+// UsageRepository defines operations for LLM usage records and budgets
+type UsageRepository interface {
+	// Create records a single LLM call
+	Create(ctx context.Context, usage *models.LLMUsage) error
+	
+	// GetByRoom retrieves the most recent usage records for a room
+	GetByRoom(ctx context.Context, roomID string, limit int) ([]*models.LLMUsage, error)
+	
+	// SummarizeRoom aggregates a room's usage grouped by "agent", "user", "model" or "session"
+	SummarizeRoom(ctx context.Context, roomID string, groupBy string) ([]*models.UsageSummary, error)
+	
+	// SummarizeByDay aggregates usage per UTC day; an empty roomID covers all rooms
+	SummarizeByDay(ctx context.Context, roomID string, from, to time.Time) ([]*models.UsageSummary, error)
+	
+	// TotalCost sums the cost since the given time; an empty roomID covers all rooms
+	TotalCost(ctx context.Context, roomID string, since time.Time) (float64, error)
+	
+	// GetBudget returns the configured budget for a scope, if any
+	GetBudget(ctx context.Context, scope, scopeID string) (float64, bool, error)
+	
+	// SetBudget sets the budget override for a scope; a limit <= 0 removes the override so the
+	// default budget applies again
+	SetBudget(ctx context.Context, scope, scopeID string, limitUSD float64) error
+}
+
+// ProposalRepository defines operations for agent proposals
+type ProposalRepository interface {
+	// Create creates a new proposal
+	Create(ctx context.Context, proposal *models.Proposal) error
+	
+	// Get retrieves a proposal by ID
+	Get(ctx context.Context, id string) (*models.Proposal, error)
+	
+	// GetByRoom retrieves a room's proposals, optionally filtered by status
+	GetByRoom(ctx context.Context, roomID string, status string) ([]*models.Proposal, error)
+	
+	// UpdateStatus records the facilitator's decision on a proposal
+	UpdateStatus(ctx context.Context, id string, status string, decidedBy string) error
+}
+
+// KnowledgeRepository defines operations for the knowledge base documents and their chunks
+type KnowledgeRepository interface {
+	// SaveDocument creates or updates a document and replaces its chunks
+	SaveDocument(ctx context.Context, document *models.KnowledgeDocument, chunks []*models.KnowledgeChunk) error
+	
+	// GetDocument retrieves a document with its content
+	GetDocument(ctx context.Context, id string) (*models.KnowledgeDocument, error)
+	
+	// GetBySource retrieves the document indexed from a source, e.g. a room
+	GetBySource(ctx context.Context, sourceType, sourceID string) (*models.KnowledgeDocument, error)
+	
+	// ListDocuments lists documents without their content, optionally filtered by source type
+	ListDocuments(ctx context.Context, sourceType string) ([]*models.KnowledgeDocument, error)
+	
+	// DeleteDocument deletes a document and its chunks
+	DeleteDocument(ctx context.Context, id string) error
+	
+	// ListChunks retrieves the chunks to search, optionally filtered by their document's room and source type
+	ListChunks(ctx context.Context, roomID, sourceType string) ([]*models.KnowledgeChunk, error)
+}
+
+// AttachmentRepository defines operations for uploaded room attachments
+type AttachmentRepository interface {
+	// Create creates a new attachment record
+	Create(ctx context.Context, attachment *models.Attachment) error
+	
+	// Get retrieves an attachment by ID
+	Get(ctx context.Context, id string) (*models.Attachment, error)
+	
+	// GetByRoom retrieves a room's attachments
+	GetByRoom(ctx context.Context, roomID string) ([]*models.Attachment, error)
+	
+	// UpdateStatus records the ingestion result of an attachment
+	UpdateStatus(ctx context.Context, id string, status string, errorMessage string, chunkCount int) error
+	
+	// RelinkCard moves a room's attachments from one card to another, e.g. when duplicate cards are merged
+	RelinkCard(ctx context.Context, roomID string, fromCardID string, toCardID string) error
+	
+	// Delete deletes an attachment record
+	Delete(ctx context.Context, id string) error
+}
+
+// RiskRepository defines operations for the room risk register
+type RiskRepository interface {
+	// Create creates a new risk
+	Create(ctx context.Context, risk *models.Risk) error
+	
+	// Get retrieves a risk by ID
+	Get(ctx context.Context, id string) (*models.Risk, error)
+	
+	// GetByRoom retrieves a room's risks, highest score first, optionally filtered by status
+	GetByRoom(ctx context.Context, roomID string, status string) ([]*models.Risk, error)
+	
+	// Update updates a risk
+	Update(ctx context.Context, risk *models.Risk) error
+	
+	// Delete deletes a risk
+	Delete(ctx context.Context, id string) error
+}
+
+// HypothesisRepository defines operations for founding hypotheses
+type HypothesisRepository interface {
+	// Create creates a new hypothesis
+	Create(ctx context.Context, hypothesis *models.Hypothesis) error
+	
+	// Get retrieves a hypothesis by ID
+	Get(ctx context.Context, id string) (*models.Hypothesis, error)
+	
+	// GetByRoom retrieves a room's hypotheses in creation order, optionally filtered by status
+	GetByRoom(ctx context.Context, roomID string, status string) ([]*models.Hypothesis, error)
+	
+	// Update updates a hypothesis
+	Update(ctx context.Context, hypothesis *models.Hypothesis) error
+	
+	// Delete deletes a hypothesis
+	Delete(ctx context.Context, id string) error
+}
+
+// InterviewRepository defines operations for Design Sprint test interviews
+type InterviewRepository interface {
+	// Create creates a new interview
+	Create(ctx context.Context, interview *models.Interview) error
+	
+	// Get retrieves an interview by ID
+	Get(ctx context.Context, id string) (*models.Interview, error)
+	
+	// GetByRoom retrieves a room's interviews, earliest first
+	GetByRoom(ctx context.Context, roomID string) ([]*models.Interview, error)
+	
+	// Update updates an interview
+	Update(ctx context.Context, interview *models.Interview) error
+	
+	// Delete deletes an interview and its scorecards
+	Delete(ctx context.Context, id string) error
+}
+
+// ScorecardRepository defines operations for interviewee scorecards
+type ScorecardRepository interface {
+	// Create creates a new scorecard
+	Create(ctx context.Context, scorecard *models.Scorecard) error
+	
+	// Get retrieves a scorecard by ID
+	Get(ctx context.Context, id string) (*models.Scorecard, error)
+	
+	// GetByInterview retrieves the scorecards of an interview
+	GetByInterview(ctx context.Context, interviewID string) ([]*models.Scorecard, error)
+	
+	// GetByRoom retrieves all scorecards of a room
+	GetByRoom(ctx context.Context, roomID string) ([]*models.Scorecard, error)
+	
+	// Update updates a scorecard
+	Update(ctx context.Context, scorecard *models.Scorecard) error
+	
+	// Delete deletes a scorecard
+	Delete(ctx context.Context, id string) error
+}
+
+// AuditRepository defines operations for the append-only room audit log
+type AuditRepository interface {
+	// Create appends an event to the audit log
+	Create(ctx context.Context, event *models.AuditEvent) error
+	
+	// GetByRoom retrieves a room's events in chronological order, optionally filtered by target
+	GetByRoom(ctx context.Context, roomID string, targetType, targetID string) ([]*models.AuditEvent, error)
+}
+
 // QueryOptions provides options for queries
 type QueryOptions struct {
 	Offset  int
@@ -167,4 +376,34 @@ var (
 	ErrInvalidInput = &Error{Code: ErrCodeInvalidInput, Message: "invalid input"}
 	ErrDatabase     = &Error{Code: ErrCodeDatabase, Message: "database error"}
 	ErrTransaction  = &Error{Code: ErrCodeTransaction, Message: "transaction error"}
-)
\ No newline at end of file
+)
+// SaveRoomPhase persists one phase ("foundation", "differentiation" or "approach") of the room
+func SaveRoomPhase(ctx context.Context, rooms RoomRepository, room *models.Room, phase string) error {
+	switch phase {
+	case "foundation":
+		return rooms.UpdateFoundation(ctx, room.ID, &room.Foundation)
+	case "differentiation":
+		return rooms.UpdateDifferentiation(ctx, room.ID, &room.Differentiation)
+	case "approach":
+		return rooms.UpdateApproach(ctx, room.ID, &room.Approach)
+	}
+	return fmt.Errorf("unknown phase: %s", phase)
+}
+
+// TemplateRepository defines operations for room templates
+type TemplateRepository interface {
+	// Create creates a new template
+	Create(ctx context.Context, template *models.RoomTemplate) error
+	
+	// Get retrieves a template by ID
+	Get(ctx context.Context, id string) (*models.RoomTemplate, error)
+	
+	// List retrieves all templates ordered by name
+	List(ctx context.Context) ([]*models.RoomTemplate, error)
+	
+	// Update updates a template
+	Update(ctx context.Context, template *models.RoomTemplate) error
+	
+	// Delete deletes a template
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"foundation-sprint/internal/models"
+	"time"
+)
+
+// sqliteAttachmentRepo implements AttachmentRepository for SQLite
+type sqliteAttachmentRepo struct {
+	db dbExecutor
+}
+
+func (a *sqliteAttachmentRepo) Create(ctx context.Context, attachment *models.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, room_id, card_id, filename, content_type, size, blob_key, status, uploaded_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := a.db.ExecContext(ctx, query,
+		attachment.ID,
+		attachment.RoomID,
+		attachment.CardID,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.BlobKey,
+		attachment.Status,
+		attachment.UploadedBy,
+		attachment.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return nil
+}
+
+func (a *sqliteAttachmentRepo) Get(ctx context.Context, id string) (*models.Attachment, error) {
+	query := `
+		SELECT id, room_id, card_id, filename, content_type, size, blob_key, status, error, chunk_count, uploaded_by, created_at, processed_at
+		FROM attachments
+		WHERE id = ?
+	`
+
+	attachment, err := scanAttachment(a.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (a *sqliteAttachmentRepo) GetByRoom(ctx context.Context, roomID string) ([]*models.Attachment, error) {
+	query := `
+		SELECT id, room_id, card_id, filename, content_type, size, blob_key, status, error, chunk_count, uploaded_by, created_at, processed_at
+		FROM attachments
+		WHERE room_id = ?
+		ORDER BY created_at ASC
+	`
+
+	rows, err := a.db.QueryContext(ctx, query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := make([]*models.Attachment, 0)
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
+
+func (a *sqliteAttachmentRepo) UpdateStatus(ctx context.Context, id string, status string, errorMessage string, chunkCount int) error {
+	var processedAt interface{}
+	if status != models.AttachmentProcessing {
+		processedAt = time.Now()
+	}
+
+	query := `
+		UPDATE attachments
+		SET status = ?, error = ?, chunk_count = ?, processed_at = ?
+		WHERE id = ?
+	`
+
+	result, err := a.db.ExecContext(ctx, query, status, errorMessage, chunkCount, processedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update attachment status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func (a *sqliteAttachmentRepo) RelinkCard(ctx context.Context, roomID string, fromCardID string, toCardID string) error {
+	query := `UPDATE attachments SET card_id = ? WHERE room_id = ? AND card_id = ?`
+
+	if _, err := a.db.ExecContext(ctx, query, toCardID, roomID, fromCardID); err != nil {
+		return fmt.Errorf("failed to relink attachments: %w", err)
+	}
+
+	return nil
+}
+
+func (a *sqliteAttachmentRepo) Delete(ctx context.Context, id string) error {
+	result, err := a.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	var attachment models.Attachment
+	var cardID, contentType, errorMessage, uploadedBy sql.NullString
+	var processedAt sql.NullTime
+
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.RoomID,
+		&cardID,
+		&attachment.Filename,
+		&contentType,
+		&attachment.Size,
+		&attachment.BlobKey,
+		&attachment.Status,
+		&errorMessage,
+		&attachment.ChunkCount,
+		&uploadedBy,
+		&attachment.CreatedAt,
+		&processedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.CardID = cardID.String
+	attachment.ContentType = contentType.String
+	attachment.Error = errorMessage.String
+	attachment.UploadedBy = uploadedBy.String
+	if processedAt.Valid {
+		attachment.ProcessedAt = &processedAt.Time
+	}
+
+	return &attachment, nil
+}
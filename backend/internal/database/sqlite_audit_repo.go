@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"foundation-sprint/internal/models"
+)
+
+// sqliteAuditRepo implements AuditRepository for SQLite
+type sqliteAuditRepo struct {
+	db dbExecutor
+}
+
+func (a *sqliteAuditRepo) Create(ctx context.Context, event *models.AuditEvent) error {
+	var details sql.NullString
+	if len(event.Details) > 0 {
+		data, err := json.Marshal(event.Details)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit details: %w", err)
+		}
+		details = sql.NullString{String: string(data), Valid: true}
+	}
+
+	query := `
+		INSERT INTO audit_events (id, room_id, actor, action, target_type, target_id, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := a.db.ExecContext(ctx, query,
+		event.ID,
+		event.RoomID,
+		event.Actor,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		details,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	return nil
+}
+
+func (a *sqliteAuditRepo) GetByRoom(ctx context.Context, roomID string, targetType, targetID string) ([]*models.AuditEvent, error) {
+	query := `
+		SELECT id, room_id, actor, action, target_type, target_id, details, created_at
+		FROM audit_events
+		WHERE room_id = ? AND (? = '' OR target_type = ?) AND (? = '' OR target_id = ?)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := a.db.QueryContext(ctx, query, roomID, targetType, targetType, targetID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*models.AuditEvent, 0)
+	for rows.Next() {
+		var event models.AuditEvent
+		var actor, target, details sql.NullString
+		if err := rows.Scan(&event.ID, &event.RoomID, &actor, &event.Action, &event.TargetType, &target, &details, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		event.Actor = actor.String
+		event.TargetID = target.String
+		if details.Valid && details.String != "" {
+			if err := json.Unmarshal([]byte(details.String), &event.Details); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal audit details: %w", err)
+			}
+		}
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,194 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"foundation-sprint/internal/models"
+)
+
+// sqliteHypothesisRepo implements HypothesisRepository for SQLite
+type sqliteHypothesisRepo struct {
+	db dbExecutor
+}
+
+const hypothesisColumns = `id, room_id, statement, customer, problem, approach, path_id, competition, differentiation,
+		confidence, validation_method, success_metric, status, evidence, source, agent_name, created_by, created_at, updated_at`
+
+func (r *sqliteHypothesisRepo) Create(ctx context.Context, h *models.Hypothesis) error {
+	query := `
+		INSERT INTO hypotheses (` + hypothesisColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		h.ID,
+		h.RoomID,
+		h.Statement,
+		h.Customer,
+		h.Problem,
+		h.Approach,
+		h.PathID,
+		h.Competition,
+		h.Differentiation,
+		h.Confidence,
+		h.ValidationMethod,
+		h.SuccessMetric,
+		h.Status,
+		h.Evidence,
+		h.Source,
+		h.AgentName,
+		h.CreatedBy,
+		h.CreatedAt,
+		h.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create hypothesis: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteHypothesisRepo) Get(ctx context.Context, id string) (*models.Hypothesis, error) {
+	query := `SELECT ` + hypothesisColumns + ` FROM hypotheses WHERE id = ?`
+
+	h, err := scanHypothesis(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hypothesis: %w", err)
+	}
+
+	return h, nil
+}
+
+func (r *sqliteHypothesisRepo) GetByRoom(ctx context.Context, roomID string, status string) ([]*models.Hypothesis, error) {
+	query := `
+		SELECT ` + hypothesisColumns + `
+		FROM hypotheses
+		WHERE room_id = ? AND (? = '' OR status = ?)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, roomID, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hypotheses: %w", err)
+	}
+	defer rows.Close()
+
+	hypotheses := make([]*models.Hypothesis, 0)
+	for rows.Next() {
+		h, err := scanHypothesis(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hypothesis: %w", err)
+		}
+		hypotheses = append(hypotheses, h)
+	}
+
+	return hypotheses, nil
+}
+
+func (r *sqliteHypothesisRepo) Update(ctx context.Context, h *models.Hypothesis) error {
+	query := `
+		UPDATE hypotheses
+		SET statement = ?, customer = ?, problem = ?, approach = ?, path_id = ?, competition = ?, differentiation = ?,
+			confidence = ?, validation_method = ?, success_metric = ?, status = ?, evidence = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		h.Statement,
+		h.Customer,
+		h.Problem,
+		h.Approach,
+		h.PathID,
+		h.Competition,
+		h.Differentiation,
+		h.Confidence,
+		h.ValidationMethod,
+		h.SuccessMetric,
+		h.Status,
+		h.Evidence,
+		h.UpdatedAt,
+		h.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update hypothesis: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func (r *sqliteHypothesisRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM hypotheses WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete hypothesis: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func scanHypothesis(row rowScanner) (*models.Hypothesis, error) {
+	var h models.Hypothesis
+	var customer, problem, approach, pathID, competition, differentiation sql.NullString
+	var validationMethod, successMetric, evidence, agentName, createdBy sql.NullString
+
+	err := row.Scan(
+		&h.ID,
+		&h.RoomID,
+		&h.Statement,
+		&customer,
+		&problem,
+		&approach,
+		&pathID,
+		&competition,
+		&differentiation,
+		&h.Confidence,
+		&validationMethod,
+		&successMetric,
+		&h.Status,
+		&evidence,
+		&h.Source,
+		&agentName,
+		&createdBy,
+		&h.CreatedAt,
+		&h.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Customer = customer.String
+	h.Problem = problem.String
+	h.Approach = approach.String
+	h.PathID = pathID.String
+	h.Competition = competition.String
+	h.Differentiation = differentiation.String
+	h.ValidationMethod = validationMethod.String
+	h.SuccessMetric = successMetric.String
+	h.Evidence = evidence.String
+	h.AgentName = agentName.String
+	h.CreatedBy = createdBy.String
+
+	return &h, nil
+}
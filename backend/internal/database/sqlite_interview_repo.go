@@ -0,0 +1,346 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"foundation-sprint/internal/models"
+)
+
+// sqliteInterviewRepo implements InterviewRepository for SQLite
+type sqliteInterviewRepo struct {
+	db dbExecutor
+}
+
+const interviewColumns = `id, room_id, title, interviewer, conducted_at, notes, created_by, created_at, updated_at`
+
+func (r *sqliteInterviewRepo) Create(ctx context.Context, interview *models.Interview) error {
+	query := `
+		INSERT INTO interviews (` + interviewColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		interview.ID,
+		interview.RoomID,
+		interview.Title,
+		interview.Interviewer,
+		interview.ConductedAt,
+		interview.Notes,
+		interview.CreatedBy,
+		interview.CreatedAt,
+		interview.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create interview: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteInterviewRepo) Get(ctx context.Context, id string) (*models.Interview, error) {
+	query := `SELECT ` + interviewColumns + ` FROM interviews WHERE id = ?`
+
+	interview, err := scanInterview(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interview: %w", err)
+	}
+
+	return interview, nil
+}
+
+func (r *sqliteInterviewRepo) GetByRoom(ctx context.Context, roomID string) ([]*models.Interview, error) {
+	query := `
+		SELECT ` + interviewColumns + `
+		FROM interviews
+		WHERE room_id = ?
+		ORDER BY conducted_at ASC, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query interviews: %w", err)
+	}
+	defer rows.Close()
+
+	interviews := make([]*models.Interview, 0)
+	for rows.Next() {
+		interview, err := scanInterview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan interview: %w", err)
+		}
+		interviews = append(interviews, interview)
+	}
+
+	return interviews, nil
+}
+
+func (r *sqliteInterviewRepo) Update(ctx context.Context, interview *models.Interview) error {
+	query := `
+		UPDATE interviews
+		SET title = ?, interviewer = ?, conducted_at = ?, notes = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		interview.Title,
+		interview.Interviewer,
+		interview.ConductedAt,
+		interview.Notes,
+		interview.UpdatedAt,
+		interview.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update interview: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func (r *sqliteInterviewRepo) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM scorecards WHERE interview_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete scorecards: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM interviews WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete interview: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func scanInterview(row rowScanner) (*models.Interview, error) {
+	var interview models.Interview
+	var interviewer, notes, createdBy sql.NullString
+
+	err := row.Scan(
+		&interview.ID,
+		&interview.RoomID,
+		&interview.Title,
+		&interviewer,
+		&interview.ConductedAt,
+		&notes,
+		&createdBy,
+		&interview.CreatedAt,
+		&interview.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	interview.Interviewer = interviewer.String
+	interview.Notes = notes.String
+	interview.CreatedBy = createdBy.String
+
+	return &interview, nil
+}
+
+// sqliteScorecardRepo implements ScorecardRepository for SQLite
+type sqliteScorecardRepo struct {
+	db dbExecutor
+}
+
+const scorecardColumns = `id, interview_id, room_id, interviewee, segment, hypothesis_ids, customer_fit, problem_fit,
+		solution_appeal, differentiation, overall_resonance, notes, created_by, created_at, updated_at`
+
+func (r *sqliteScorecardRepo) Create(ctx context.Context, scorecard *models.Scorecard) error {
+	hypothesisIDs, err := json.Marshal(scorecard.HypothesisIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hypothesis ids: %w", err)
+	}
+
+	query := `
+		INSERT INTO scorecards (` + scorecardColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		scorecard.ID,
+		scorecard.InterviewID,
+		scorecard.RoomID,
+		scorecard.Interviewee,
+		scorecard.Segment,
+		string(hypothesisIDs),
+		scorecard.CustomerFit,
+		scorecard.ProblemFit,
+		scorecard.SolutionAppeal,
+		scorecard.Differentiation,
+		scorecard.OverallResonance,
+		scorecard.Notes,
+		scorecard.CreatedBy,
+		scorecard.CreatedAt,
+		scorecard.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create scorecard: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteScorecardRepo) Get(ctx context.Context, id string) (*models.Scorecard, error) {
+	query := `SELECT ` + scorecardColumns + ` FROM scorecards WHERE id = ?`
+
+	scorecard, err := scanScorecard(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scorecard: %w", err)
+	}
+
+	return scorecard, nil
+}
+
+func (r *sqliteScorecardRepo) GetByInterview(ctx context.Context, interviewID string) ([]*models.Scorecard, error) {
+	return r.query(ctx, `SELECT `+scorecardColumns+` FROM scorecards WHERE interview_id = ? ORDER BY created_at ASC`, interviewID)
+}
+
+func (r *sqliteScorecardRepo) GetByRoom(ctx context.Context, roomID string) ([]*models.Scorecard, error) {
+	return r.query(ctx, `SELECT `+scorecardColumns+` FROM scorecards WHERE room_id = ? ORDER BY created_at ASC`, roomID)
+}
+
+func (r *sqliteScorecardRepo) query(ctx context.Context, query string, args ...interface{}) ([]*models.Scorecard, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scorecards: %w", err)
+	}
+	defer rows.Close()
+
+	scorecards := make([]*models.Scorecard, 0)
+	for rows.Next() {
+		scorecard, err := scanScorecard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scorecard: %w", err)
+		}
+		scorecards = append(scorecards, scorecard)
+	}
+
+	return scorecards, nil
+}
+
+func (r *sqliteScorecardRepo) Update(ctx context.Context, scorecard *models.Scorecard) error {
+	hypothesisIDs, err := json.Marshal(scorecard.HypothesisIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hypothesis ids: %w", err)
+	}
+
+	query := `
+		UPDATE scorecards
+		SET interviewee = ?, segment = ?, hypothesis_ids = ?, customer_fit = ?, problem_fit = ?, solution_appeal = ?,
+			differentiation = ?, overall_resonance = ?, notes = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		scorecard.Interviewee,
+		scorecard.Segment,
+		string(hypothesisIDs),
+		scorecard.CustomerFit,
+		scorecard.ProblemFit,
+		scorecard.SolutionAppeal,
+		scorecard.Differentiation,
+		scorecard.OverallResonance,
+		scorecard.Notes,
+		scorecard.UpdatedAt,
+		scorecard.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update scorecard: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func (r *sqliteScorecardRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM scorecards WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete scorecard: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func scanScorecard(row rowScanner) (*models.Scorecard, error) {
+	var scorecard models.Scorecard
+	var hypothesisIDs string
+	var segment, customerFit, problemFit, solutionAppeal, differentiation, overallResonance, notes, createdBy sql.NullString
+
+	err := row.Scan(
+		&scorecard.ID,
+		&scorecard.InterviewID,
+		&scorecard.RoomID,
+		&scorecard.Interviewee,
+		&segment,
+		&hypothesisIDs,
+		&customerFit,
+		&problemFit,
+		&solutionAppeal,
+		&differentiation,
+		&overallResonance,
+		&notes,
+		&createdBy,
+		&scorecard.CreatedAt,
+		&scorecard.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(hypothesisIDs), &scorecard.HypothesisIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal hypothesis ids: %w", err)
+	}
+	scorecard.Segment = segment.String
+	scorecard.CustomerFit = customerFit.String
+	scorecard.ProblemFit = problemFit.String
+	scorecard.SolutionAppeal = solutionAppeal.String
+	scorecard.Differentiation = differentiation.String
+	scorecard.OverallResonance = overallResonance.String
+	scorecard.Notes = notes.String
+	scorecard.CreatedBy = createdBy.String
+
+	return &scorecard, nil
+}
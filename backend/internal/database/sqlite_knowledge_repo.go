@@ -0,0 +1,225 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"foundation-sprint/internal/models"
+	"math"
+)
+
+// sqliteKnowledgeRepo implements KnowledgeRepository for SQLite
+type sqliteKnowledgeRepo struct {
+	db dbExecutor
+}
+
+func (k *sqliteKnowledgeRepo) SaveDocument(ctx context.Context, document *models.KnowledgeDocument, chunks []*models.KnowledgeChunk) error {
+	query := `
+		INSERT INTO knowledge_documents (id, source_type, source_id, room_id, title, content, created_by, chunk_count, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title,
+			content = excluded.content,
+			chunk_count = excluded.chunk_count,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := k.db.ExecContext(ctx, query,
+		document.ID,
+		document.SourceType,
+		document.SourceID,
+		document.RoomID,
+		document.Title,
+		document.Content,
+		document.CreatedBy,
+		len(chunks),
+		document.CreatedAt,
+		document.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save knowledge document: %w", err)
+	}
+
+	if _, err := k.db.ExecContext(ctx, `DELETE FROM knowledge_chunks WHERE document_id = ?`, document.ID); err != nil {
+		return fmt.Errorf("failed to clear knowledge chunks: %w", err)
+	}
+
+	for _, chunk := range chunks {
+		_, err := k.db.ExecContext(ctx, `
+			INSERT INTO knowledge_chunks (id, document_id, ordinal, text, embedding, embedding_model)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, chunk.ID, document.ID, chunk.Ordinal, chunk.Text, encodeEmbedding(chunk.Embedding), chunk.EmbeddingModel)
+		if err != nil {
+			return fmt.Errorf("failed to save knowledge chunk: %w", err)
+		}
+	}
+
+	document.ChunkCount = len(chunks)
+	return nil
+}
+
+func (k *sqliteKnowledgeRepo) GetDocument(ctx context.Context, id string) (*models.KnowledgeDocument, error) {
+	query := `
+		SELECT id, source_type, source_id, room_id, title, content, created_by, chunk_count, created_at, updated_at
+		FROM knowledge_documents
+		WHERE id = ?
+	`
+
+	document, err := scanKnowledgeDocument(k.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get knowledge document: %w", err)
+	}
+
+	return document, nil
+}
+
+func (k *sqliteKnowledgeRepo) GetBySource(ctx context.Context, sourceType, sourceID string) (*models.KnowledgeDocument, error) {
+	query := `
+		SELECT id, source_type, source_id, room_id, title, content, created_by, chunk_count, created_at, updated_at
+		FROM knowledge_documents
+		WHERE source_type = ? AND source_id = ?
+	`
+
+	document, err := scanKnowledgeDocument(k.db.QueryRowContext(ctx, query, sourceType, sourceID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get knowledge document: %w", err)
+	}
+
+	return document, nil
+}
+
+func (k *sqliteKnowledgeRepo) ListDocuments(ctx context.Context, sourceType string) ([]*models.KnowledgeDocument, error) {
+	query := `
+		SELECT id, source_type, source_id, room_id, title, '', created_by, chunk_count, created_at, updated_at
+		FROM knowledge_documents
+		WHERE ? = '' OR source_type = ?
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := k.db.QueryContext(ctx, query, sourceType, sourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query knowledge documents: %w", err)
+	}
+	defer rows.Close()
+
+	documents := make([]*models.KnowledgeDocument, 0)
+	for rows.Next() {
+		document, err := scanKnowledgeDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan knowledge document: %w", err)
+		}
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+func (k *sqliteKnowledgeRepo) DeleteDocument(ctx context.Context, id string) error {
+	if _, err := k.db.ExecContext(ctx, `DELETE FROM knowledge_chunks WHERE document_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete knowledge chunks: %w", err)
+	}
+
+	result, err := k.db.ExecContext(ctx, `DELETE FROM knowledge_documents WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete knowledge document: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func (k *sqliteKnowledgeRepo) ListChunks(ctx context.Context, roomID, sourceType string) ([]*models.KnowledgeChunk, error) {
+	query := `
+		SELECT c.id, c.document_id, c.ordinal, c.text, c.embedding, c.embedding_model
+		FROM knowledge_chunks c
+		JOIN knowledge_documents d ON d.id = c.document_id
+		WHERE (? = '' OR d.room_id = ?) AND (? = '' OR d.source_type = ?)
+		ORDER BY c.document_id, c.ordinal
+	`
+
+	rows, err := k.db.QueryContext(ctx, query, roomID, roomID, sourceType, sourceType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query knowledge chunks: %w", err)
+	}
+	defer rows.Close()
+
+	chunks := make([]*models.KnowledgeChunk, 0)
+	for rows.Next() {
+		var chunk models.KnowledgeChunk
+		var embedding []byte
+		var model sql.NullString
+		if err := rows.Scan(&chunk.ID, &chunk.DocumentID, &chunk.Ordinal, &chunk.Text, &embedding, &model); err != nil {
+			return nil, fmt.Errorf("failed to scan knowledge chunk: %w", err)
+		}
+		chunk.Embedding = decodeEmbedding(embedding)
+		chunk.EmbeddingModel = model.String
+		chunks = append(chunks, &chunk)
+	}
+
+	return chunks, nil
+}
+
+func scanKnowledgeDocument(row rowScanner) (*models.KnowledgeDocument, error) {
+	var document models.KnowledgeDocument
+	var sourceID, roomID, content, createdBy sql.NullString
+
+	err := row.Scan(
+		&document.ID,
+		&document.SourceType,
+		&sourceID,
+		&roomID,
+		&document.Title,
+		&content,
+		&createdBy,
+		&document.ChunkCount,
+		&document.CreatedAt,
+		&document.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	document.SourceID = sourceID.String
+	document.RoomID = roomID.String
+	document.Content = content.String
+	document.CreatedBy = createdBy.String
+	return &document, nil
+}
+
+// encodeEmbedding stores a vector as little-endian float32s
+func encodeEmbedding(vector []float32) []byte {
+	if len(vector) == 0 {
+		return nil
+	}
+	data := make([]byte, 4*len(vector))
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(value))
+	}
+	return data
+}
+
+func decodeEmbedding(data []byte) []float32 {
+	if len(data) < 4 {
+		return nil
+	}
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector
+}
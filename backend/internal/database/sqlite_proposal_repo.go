@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"foundation-sprint/internal/models"
+	"time"
+)
+
+// sqliteProposalRepo implements ProposalRepository for SQLite
+type sqliteProposalRepo struct {
+	db dbExecutor
+}
+
+func (p *sqliteProposalRepo) Create(ctx context.Context, proposal *models.Proposal) error {
+	payload, err := json.Marshal(proposal.ProposalPayload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal proposal payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO proposals (id, room_id, type, status, agent_name, requested_by, rationale, payload, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = p.db.ExecContext(ctx, query,
+		proposal.ID,
+		proposal.RoomID,
+		proposal.Type,
+		proposal.Status,
+		proposal.AgentName,
+		proposal.RequestedBy,
+		proposal.Rationale,
+		string(payload),
+		proposal.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create proposal: %w", err)
+	}
+
+	return nil
+}
+
+func (p *sqliteProposalRepo) Get(ctx context.Context, id string) (*models.Proposal, error) {
+	query := `
+		SELECT id, room_id, type, status, agent_name, requested_by, rationale, payload, decided_by, decided_at, created_at
+		FROM proposals
+		WHERE id = ?
+	`
+
+	proposal, err := scanProposal(p.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proposal: %w", err)
+	}
+
+	return proposal, nil
+}
+
+func (p *sqliteProposalRepo) GetByRoom(ctx context.Context, roomID string, status string) ([]*models.Proposal, error) {
+	query := `
+		SELECT id, room_id, type, status, agent_name, requested_by, rationale, payload, decided_by, decided_at, created_at
+		FROM proposals
+		WHERE room_id = ? AND (? = '' OR status = ?)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := p.db.QueryContext(ctx, query, roomID, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query proposals: %w", err)
+	}
+	defer rows.Close()
+
+	proposals := make([]*models.Proposal, 0)
+	for rows.Next() {
+		proposal, err := scanProposal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan proposal: %w", err)
+		}
+		proposals = append(proposals, proposal)
+	}
+
+	return proposals, nil
+}
+
+func (p *sqliteProposalRepo) UpdateStatus(ctx context.Context, id string, status string, decidedBy string) error {
+	query := `
+		UPDATE proposals
+		SET status = ?, decided_by = ?, decided_at = ?
+		WHERE id = ?
+	`
+
+	result, err := p.db.ExecContext(ctx, query, status, decidedBy, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update proposal status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func scanProposal(row rowScanner) (*models.Proposal, error) {
+	var proposal models.Proposal
+	var requestedBy, rationale, payload, decidedBy sql.NullString
+	var decidedAt sql.NullTime
+
+	err := row.Scan(
+		&proposal.ID,
+		&proposal.RoomID,
+		&proposal.Type,
+		&proposal.Status,
+		&proposal.AgentName,
+		&requestedBy,
+		&rationale,
+		&payload,
+		&decidedBy,
+		&decidedAt,
+		&proposal.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal.RequestedBy = requestedBy.String
+	proposal.Rationale = rationale.String
+	proposal.DecidedBy = decidedBy.String
+	if decidedAt.Valid {
+		proposal.DecidedAt = &decidedAt.Time
+	}
+
+	if payload.Valid && payload.String != "" {
+		if err := json.Unmarshal([]byte(payload.String), &proposal.ProposalPayload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal proposal payload: %w", err)
+		}
+	}
+
+	return &proposal, nil
+}
@@ -15,9 +15,21 @@ type sqliteRoomRepo struct {
 }
 
 func (r *sqliteRoomRepo) Create(ctx context.Context, room *models.Room) error {
+	// Rooms created from a template or cloned from another room start with phase data and settings
+	room.Foundation.Normalize(room.ID, room.CreatedAt)
+	data := make([]string, 0, 4)
+	for _, value := range []interface{}{room.Foundation, room.Differentiation, room.Approach, room.Settings} {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal room data: %w", err)
+		}
+		data = append(data, string(encoded))
+	}
+	
 	query := `
-		INSERT INTO rooms (id, name, created_by, created_at, updated_at, status)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO rooms (id, name, created_by, created_at, updated_at, status,
+		                   foundation_data, differentiation_data, approach_data, settings_data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	
 	_, err := r.db.ExecContext(ctx, query,
@@ -27,6 +39,10 @@ func (r *sqliteRoomRepo) Create(ctx context.Context, room *models.Room) error {
 		room.CreatedAt,
 		room.UpdatedAt,
 		room.Status,
+		data[0],
+		data[1],
+		data[2],
+		data[3],
 	)
 	
 	if err != nil {
@@ -39,13 +55,13 @@ func (r *sqliteRoomRepo) Create(ctx context.Context, room *models.Room) error {
 func (r *sqliteRoomRepo) Get(ctx context.Context, id string) (*models.Room, error) {
 	query := `
 		SELECT id, name, created_by, created_at, updated_at, status,
-		       foundation_data, differentiation_data, approach_data
+		       foundation_data, differentiation_data, approach_data, settings_data
 		FROM rooms
 		WHERE id = ?
 	`
 	
 	var room models.Room
-	var foundationData, differentiationData, approachData sql.NullString
+	var foundationData, differentiationData, approachData, settingsData sql.NullString
 	
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&room.ID,
@@ -57,6 +73,7 @@ func (r *sqliteRoomRepo) Get(ctx context.Context, id string) (*models.Room, erro
 		&foundationData,
 		&differentiationData,
 		&approachData,
+		&settingsData,
 	)
 	
 	if err == sql.ErrNoRows {
@@ -68,10 +85,10 @@ func (r *sqliteRoomRepo) Get(ctx context.Context, id string) (*models.Room, erro
 	
 	// Initialize with empty slices to avoid nil values
 	room.Foundation = models.Foundation{
-		Customers:   make([]string, 0),
-		Problems:    make([]string, 0),
-		Competition: make([]string, 0),
-		Advantages:  make([]string, 0),
+		Customers:   make([]models.Card, 0),
+		Problems:    make([]models.Card, 0),
+		Competition: make([]models.Card, 0),
+		Advantages:  make([]models.Card, 0),
 	}
 	room.Differentiation = models.Differentiation{
 		ClassicFactors: make([]models.DifferentiationFactor, 0),
@@ -95,6 +112,8 @@ func (r *sqliteRoomRepo) Get(ctx context.Context, id string) (*models.Room, erro
 			return nil, fmt.Errorf("failed to unmarshal foundation data: %w", err)
 		}
 	}
+	// Rooms saved before foundation items became cards hold plain strings
+	room.Foundation.Normalize(room.ID, room.CreatedAt)
 	
 	if differentiationData.Valid && differentiationData.String != "" {
 		if err := json.Unmarshal([]byte(differentiationData.String), &room.Differentiation); err != nil {
@@ -108,6 +127,13 @@ func (r *sqliteRoomRepo) Get(ctx context.Context, id string) (*models.Room, erro
 		}
 	}
 	
+	room.Settings = models.RoomSettings{Agenda: make([]models.AgendaItem, 0)}
+	if settingsData.Valid && settingsData.String != "" {
+		if err := json.Unmarshal([]byte(settingsData.String), &room.Settings); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal settings data: %w", err)
+		}
+	}
+	
 	return &room, nil
 }
 
@@ -230,6 +256,7 @@ func (r *sqliteRoomRepo) List(ctx context.Context, offset, limit int) ([]*models
 }
 
 func (r *sqliteRoomRepo) UpdateFoundation(ctx context.Context, roomID string, foundation *models.Foundation) error {
+	foundation.Normalize(roomID, time.Now())
 	data, err := json.Marshal(foundation)
 	if err != nil {
 		return fmt.Errorf("failed to marshal foundation data: %w", err)
@@ -258,6 +285,56 @@ func (r *sqliteRoomRepo) UpdateFoundation(ctx context.Context, roomID string, fo
 	return nil
 }
 
+// migrateFoundationCards rewrites foundation data saved as string arrays into cards so that
+// card IDs stay stable once the room is edited again
+func migrateFoundationCards(ctx context.Context, db dbExecutor) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, created_at, foundation_data
+		FROM rooms
+		WHERE foundation_data IS NOT NULL AND foundation_data != ''
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to query foundation data: %w", err)
+	}
+
+	type legacyRoom struct {
+		id         string
+		createdAt  time.Time
+		foundation models.Foundation
+	}
+	var legacy []legacyRoom
+	for rows.Next() {
+		var room legacyRoom
+		var data string
+		if err := rows.Scan(&room.id, &room.createdAt, &data); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan foundation data: %w", err)
+		}
+		if err := json.Unmarshal([]byte(data), &room.foundation); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to unmarshal foundation data of room %s: %w", room.id, err)
+		}
+		if room.foundation.Normalize(room.id, room.createdAt) {
+			legacy = append(legacy, room)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read foundation data: %w", err)
+	}
+
+	for _, room := range legacy {
+		data, err := json.Marshal(room.foundation)
+		if err != nil {
+			return fmt.Errorf("failed to marshal foundation data: %w", err)
+		}
+		if _, err := db.ExecContext(ctx, `UPDATE rooms SET foundation_data = ? WHERE id = ?`, string(data), room.id); err != nil {
+			return fmt.Errorf("failed to migrate foundation of room %s: %w", room.id, err)
+		}
+	}
+	return nil
+}
+
 func (r *sqliteRoomRepo) UpdateDifferentiation(ctx context.Context, roomID string, differentiation *models.Differentiation) error {
 	data, err := json.Marshal(differentiation)
 	if err != nil {
@@ -316,6 +393,35 @@ func (r *sqliteRoomRepo) UpdateApproach(ctx context.Context, roomID string, appr
 	return nil
 }
 
+func (r *sqliteRoomRepo) UpdateSettings(ctx context.Context, roomID string, settings *models.RoomSettings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings data: %w", err)
+	}
+	
+	query := `
+		UPDATE rooms
+		SET settings_data = ?, updated_at = ?
+		WHERE id = ?
+	`
+	
+	result, err := r.db.ExecContext(ctx, query, string(data), time.Now(), roomID)
+	if err != nil {
+		return fmt.Errorf("failed to update settings: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+	
+	return nil
+}
+
 func (r *sqliteRoomRepo) UpdateStatus(ctx context.Context, roomID string, status string) error {
 	query := `
 		UPDATE rooms
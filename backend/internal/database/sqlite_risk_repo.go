@@ -0,0 +1,190 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"foundation-sprint/internal/models"
+)
+
+// sqliteRiskRepo implements RiskRepository for SQLite
+type sqliteRiskRepo struct {
+	db dbExecutor
+}
+
+const riskColumns = `id, room_id, title, description, category, likelihood, impact, score, level, owner, mitigation,
+		status, path_id, assumption, source, agent_name, created_by, created_at, updated_at`
+
+func (r *sqliteRiskRepo) Create(ctx context.Context, risk *models.Risk) error {
+	query := `
+		INSERT INTO risks (` + riskColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		risk.ID,
+		risk.RoomID,
+		risk.Title,
+		risk.Description,
+		risk.Category,
+		risk.Likelihood,
+		risk.Impact,
+		risk.Score,
+		risk.Level,
+		risk.Owner,
+		risk.Mitigation,
+		risk.Status,
+		risk.PathID,
+		risk.Assumption,
+		risk.Source,
+		risk.AgentName,
+		risk.CreatedBy,
+		risk.CreatedAt,
+		risk.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create risk: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteRiskRepo) Get(ctx context.Context, id string) (*models.Risk, error) {
+	query := `SELECT ` + riskColumns + ` FROM risks WHERE id = ?`
+
+	risk, err := scanRisk(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk: %w", err)
+	}
+
+	return risk, nil
+}
+
+func (r *sqliteRiskRepo) GetByRoom(ctx context.Context, roomID string, status string) ([]*models.Risk, error) {
+	query := `
+		SELECT ` + riskColumns + `
+		FROM risks
+		WHERE room_id = ? AND (? = '' OR status = ?)
+		ORDER BY score DESC, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, roomID, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query risks: %w", err)
+	}
+	defer rows.Close()
+
+	risks := make([]*models.Risk, 0)
+	for rows.Next() {
+		risk, err := scanRisk(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan risk: %w", err)
+		}
+		risks = append(risks, risk)
+	}
+
+	return risks, nil
+}
+
+func (r *sqliteRiskRepo) Update(ctx context.Context, risk *models.Risk) error {
+	query := `
+		UPDATE risks
+		SET title = ?, description = ?, category = ?, likelihood = ?, impact = ?, score = ?, level = ?,
+			owner = ?, mitigation = ?, status = ?, path_id = ?, assumption = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		risk.Title,
+		risk.Description,
+		risk.Category,
+		risk.Likelihood,
+		risk.Impact,
+		risk.Score,
+		risk.Level,
+		risk.Owner,
+		risk.Mitigation,
+		risk.Status,
+		risk.PathID,
+		risk.Assumption,
+		risk.UpdatedAt,
+		risk.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update risk: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func (r *sqliteRiskRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM risks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete risk: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func scanRisk(row rowScanner) (*models.Risk, error) {
+	var risk models.Risk
+	var description, category, owner, mitigation, pathID, assumption, agentName, createdBy sql.NullString
+
+	err := row.Scan(
+		&risk.ID,
+		&risk.RoomID,
+		&risk.Title,
+		&description,
+		&category,
+		&risk.Likelihood,
+		&risk.Impact,
+		&risk.Score,
+		&risk.Level,
+		&owner,
+		&mitigation,
+		&risk.Status,
+		&pathID,
+		&assumption,
+		&risk.Source,
+		&agentName,
+		&createdBy,
+		&risk.CreatedAt,
+		&risk.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	risk.Description = description.String
+	risk.Category = category.String
+	risk.Owner = owner.String
+	risk.Mitigation = mitigation.String
+	risk.PathID = pathID.String
+	risk.Assumption = assumption.String
+	risk.AgentName = agentName.String
+	risk.CreatedBy = createdBy.String
+
+	return &risk, nil
+}
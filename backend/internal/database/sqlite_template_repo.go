@@ -0,0 +1,190 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"foundation-sprint/internal/models"
+)
+
+// sqliteTemplateRepo implements TemplateRepository for SQLite
+type sqliteTemplateRepo struct {
+	db dbExecutor
+}
+
+// templateData holds the template contents stored as JSON in template_data
+type templateData struct {
+	ClassicFactors []models.DifferentiationFactor `json:"classic_factors"`
+	MagicLenses    []models.MagicLens             `json:"magic_lenses"`
+	Agenda         []models.AgendaItem            `json:"agenda"`
+	Agents         models.AgentSettings           `json:"agents"`
+}
+
+const templateColumns = `id, name, description, industry, template_data, created_by, created_at, updated_at`
+
+func (t *sqliteTemplateRepo) Create(ctx context.Context, template *models.RoomTemplate) error {
+	data, err := marshalTemplateData(template)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO room_templates (` + templateColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = t.db.ExecContext(ctx, query,
+		template.ID,
+		template.Name,
+		template.Description,
+		template.Industry,
+		data,
+		template.CreatedBy,
+		template.CreatedAt,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return nil
+}
+
+func (t *sqliteTemplateRepo) Get(ctx context.Context, id string) (*models.RoomTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM room_templates WHERE id = ?`
+
+	template, err := scanTemplate(t.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return template, nil
+}
+
+func (t *sqliteTemplateRepo) List(ctx context.Context) ([]*models.RoomTemplate, error) {
+	query := `SELECT ` + templateColumns + ` FROM room_templates ORDER BY name ASC`
+
+	rows, err := t.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]*models.RoomTemplate, 0)
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+func (t *sqliteTemplateRepo) Update(ctx context.Context, template *models.RoomTemplate) error {
+	data, err := marshalTemplateData(template)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE room_templates
+		SET name = ?, description = ?, industry = ?, template_data = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := t.db.ExecContext(ctx, query,
+		template.Name,
+		template.Description,
+		template.Industry,
+		data,
+		template.UpdatedAt,
+		template.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func (t *sqliteTemplateRepo) Delete(ctx context.Context, id string) error {
+	result, err := t.db.ExecContext(ctx, `DELETE FROM room_templates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func marshalTemplateData(template *models.RoomTemplate) (string, error) {
+	data, err := json.Marshal(templateData{
+		ClassicFactors: template.ClassicFactors,
+		MagicLenses:    template.MagicLenses,
+		Agenda:         template.Agenda,
+		Agents:         template.Agents,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal template data: %w", err)
+	}
+	return string(data), nil
+}
+
+func scanTemplate(row rowScanner) (*models.RoomTemplate, error) {
+	var template models.RoomTemplate
+	var description, industry, createdBy sql.NullString
+	var data string
+	err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&description,
+		&industry,
+		&data,
+		&createdBy,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	template.Description = description.String
+	template.Industry = industry.String
+	template.CreatedBy = createdBy.String
+
+	contents := templateData{
+		ClassicFactors: make([]models.DifferentiationFactor, 0),
+		MagicLenses:    make([]models.MagicLens, 0),
+		Agenda:         make([]models.AgendaItem, 0),
+	}
+	if err := json.Unmarshal([]byte(data), &contents); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template data: %w", err)
+	}
+	template.ClassicFactors = contents.ClassicFactors
+	template.MagicLenses = contents.MagicLenses
+	template.Agenda = contents.Agenda
+	template.Agents = contents.Agents
+	return &template, nil
+}
@@ -0,0 +1,237 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"foundation-sprint/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// usageGroupColumns whitelists the columns SummarizeRoom may group by
+var usageGroupColumns = map[string]string{
+	"agent":   "agent_name",
+	"user":    "user_id",
+	"model":   "model",
+	"session": "session_id",
+}
+
+// sqliteUsageRepo implements UsageRepository for SQLite
+type sqliteUsageRepo struct {
+	db dbExecutor
+}
+
+func (u *sqliteUsageRepo) Create(ctx context.Context, usage *models.LLMUsage) error {
+	if usage.ID == "" {
+		usage.ID = uuid.New().String()
+	}
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO llm_usage (id, room_id, user_id, agent_name, session_id, model, call_type,
+		                       prompt_tokens, completion_tokens, total_tokens, cost_usd, unpriced, usage_date, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := u.db.ExecContext(ctx, query,
+		usage.ID,
+		usage.RoomID,
+		usage.UserID,
+		usage.AgentName,
+		usage.SessionID,
+		usage.Model,
+		usage.CallType,
+		usage.PromptTokens,
+		usage.CompletionTokens,
+		usage.TotalTokens,
+		usage.CostUSD,
+		usage.Unpriced,
+		usage.CreatedAt.UTC().Format("2006-01-02"),
+		usage.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create usage record: %w", err)
+	}
+
+	return nil
+}
+
+func (u *sqliteUsageRepo) GetByRoom(ctx context.Context, roomID string, limit int) ([]*models.LLMUsage, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `
+		SELECT id, room_id, user_id, agent_name, session_id, model, call_type,
+		       prompt_tokens, completion_tokens, total_tokens, cost_usd, unpriced, created_at
+		FROM llm_usage
+		WHERE room_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+
+	rows, err := u.db.QueryContext(ctx, query, roomID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*models.LLMUsage
+	for rows.Next() {
+		var record models.LLMUsage
+		var userID, agentName, sessionID, callType sql.NullString
+		err := rows.Scan(
+			&record.ID,
+			&record.RoomID,
+			&userID,
+			&agentName,
+			&sessionID,
+			&record.Model,
+			&callType,
+			&record.PromptTokens,
+			&record.CompletionTokens,
+			&record.TotalTokens,
+			&record.CostUSD,
+			&record.Unpriced,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		record.UserID = userID.String
+		record.AgentName = agentName.String
+		record.SessionID = sessionID.String
+		record.CallType = callType.String
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+func (u *sqliteUsageRepo) SummarizeRoom(ctx context.Context, roomID string, groupBy string) ([]*models.UsageSummary, error) {
+	column, ok := usageGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported group_by: %s", groupBy)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COALESCE(%s, ''), COUNT(*), SUM(prompt_tokens), SUM(completion_tokens), SUM(total_tokens), SUM(cost_usd), SUM(unpriced)
+		FROM llm_usage
+		WHERE room_id = ?
+		GROUP BY COALESCE(%s, '')
+		ORDER BY SUM(cost_usd) DESC
+	`, column, column)
+
+	rows, err := u.db.QueryContext(ctx, query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize usage: %w", err)
+	}
+	defer rows.Close()
+
+	return scanUsageSummaries(rows)
+}
+
+func (u *sqliteUsageRepo) SummarizeByDay(ctx context.Context, roomID string, from, to time.Time) ([]*models.UsageSummary, error) {
+	query := `
+		SELECT usage_date, COUNT(*), SUM(prompt_tokens), SUM(completion_tokens), SUM(total_tokens), SUM(cost_usd), SUM(unpriced)
+		FROM llm_usage
+		WHERE usage_date >= ? AND usage_date <= ? AND (? = '' OR room_id = ?)
+		GROUP BY usage_date
+		ORDER BY usage_date ASC
+	`
+
+	rows, err := u.db.QueryContext(ctx, query,
+		from.UTC().Format("2006-01-02"),
+		to.UTC().Format("2006-01-02"),
+		roomID,
+		roomID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize usage by day: %w", err)
+	}
+	defer rows.Close()
+
+	return scanUsageSummaries(rows)
+}
+
+func (u *sqliteUsageRepo) TotalCost(ctx context.Context, roomID string, since time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(cost_usd), 0)
+		FROM llm_usage
+		WHERE usage_date >= ? AND (? = '' OR room_id = ?)
+	`
+
+	var total float64
+	err := u.db.QueryRowContext(ctx, query, since.UTC().Format("2006-01-02"), roomID, roomID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum usage cost: %w", err)
+	}
+
+	return total, nil
+}
+
+func (u *sqliteUsageRepo) GetBudget(ctx context.Context, scope, scopeID string) (float64, bool, error) {
+	query := `SELECT limit_usd FROM usage_budgets WHERE scope = ? AND scope_id = ?`
+
+	var limit float64
+	err := u.db.QueryRowContext(ctx, query, scope, scopeID).Scan(&limit)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get budget: %w", err)
+	}
+
+	return limit, true, nil
+}
+
+func (u *sqliteUsageRepo) SetBudget(ctx context.Context, scope, scopeID string, limitUSD float64) error {
+	if limitUSD <= 0 {
+		_, err := u.db.ExecContext(ctx, `DELETE FROM usage_budgets WHERE scope = ? AND scope_id = ?`, scope, scopeID)
+		if err != nil {
+			return fmt.Errorf("failed to delete budget: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO usage_budgets (scope, scope_id, limit_usd, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(scope, scope_id)
+		DO UPDATE SET limit_usd = excluded.limit_usd, updated_at = excluded.updated_at
+	`
+
+	if _, err := u.db.ExecContext(ctx, query, scope, scopeID, limitUSD, time.Now()); err != nil {
+		return fmt.Errorf("failed to set budget: %w", err)
+	}
+
+	return nil
+}
+
+// scanUsageSummaries scans rows of (key, calls, prompt, completion, total, cost)
+func scanUsageSummaries(rows *sql.Rows) ([]*models.UsageSummary, error) {
+	summaries := make([]*models.UsageSummary, 0)
+	for rows.Next() {
+		var summary models.UsageSummary
+		err := rows.Scan(
+			&summary.Key,
+			&summary.Calls,
+			&summary.PromptTokens,
+			&summary.CompletionTokens,
+			&summary.TotalTokens,
+			&summary.CostUSD,
+			&summary.UnpricedCalls,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan usage summary: %w", err)
+		}
+		summaries = append(summaries, &summary)
+	}
+
+	return summaries, nil
+}
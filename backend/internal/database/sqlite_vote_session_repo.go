@@ -0,0 +1,231 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"foundation-sprint/internal/models"
+)
+
+// sqliteVoteSessionRepo implements VoteSessionRepository for SQLite
+type sqliteVoteSessionRepo struct {
+	db dbExecutor
+}
+
+func (v *sqliteVoteSessionRepo) Create(ctx context.Context, vote *models.Vote) error {
+	optionsData, err := json.Marshal(vote.Options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vote options: %w", err)
+	}
+	settingsData, err := json.Marshal(vote.Settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vote settings: %w", err)
+	}
+	var decisionData sql.NullString
+	if vote.Decision != nil {
+		data, err := json.Marshal(vote.Decision)
+		if err != nil {
+			return fmt.Errorf("failed to marshal vote decision: %w", err)
+		}
+		decisionData = sql.NullString{String: string(data), Valid: true}
+	}
+
+	query := `
+		INSERT INTO vote_sessions (id, room_id, title, description, vote_type, status, created_by, created_at, ended_at, options_data, settings_data, decision_data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = v.db.ExecContext(ctx, query,
+		vote.ID,
+		vote.RoomID,
+		vote.Title,
+		vote.Description,
+		vote.Type,
+		vote.Status,
+		vote.CreatedBy,
+		vote.CreatedAt,
+		vote.EndedAt,
+		string(optionsData),
+		string(settingsData),
+		decisionData,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create vote session: %w", err)
+	}
+
+	return nil
+}
+
+func (v *sqliteVoteSessionRepo) Get(ctx context.Context, id string) (*models.Vote, error) {
+	query := `
+		SELECT id, room_id, title, description, vote_type, status, created_by, created_at, ended_at, options_data, settings_data, decision_data
+		FROM vote_sessions
+		WHERE id = ?
+	`
+
+	vote, err := scanVoteSession(v.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote session: %w", err)
+	}
+
+	return vote, nil
+}
+
+func (v *sqliteVoteSessionRepo) GetByRoom(ctx context.Context, roomID string) ([]*models.Vote, error) {
+	query := `
+		SELECT id, room_id, title, description, vote_type, status, created_by, created_at, ended_at, options_data, settings_data, decision_data
+		FROM vote_sessions
+		WHERE room_id = ?
+		ORDER BY created_at ASC
+	`
+
+	rows, err := v.db.QueryContext(ctx, query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vote sessions: %w", err)
+	}
+	defer rows.Close()
+
+	votes := make([]*models.Vote, 0)
+	for rows.Next() {
+		vote, err := scanVoteSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan vote session: %w", err)
+		}
+		votes = append(votes, vote)
+	}
+
+	return votes, nil
+}
+
+func (v *sqliteVoteSessionRepo) Update(ctx context.Context, vote *models.Vote) error {
+	optionsData, err := json.Marshal(vote.Options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vote options: %w", err)
+	}
+	settingsData, err := json.Marshal(vote.Settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vote settings: %w", err)
+	}
+	var decisionData sql.NullString
+	if vote.Decision != nil {
+		data, err := json.Marshal(vote.Decision)
+		if err != nil {
+			return fmt.Errorf("failed to marshal vote decision: %w", err)
+		}
+		decisionData = sql.NullString{String: string(data), Valid: true}
+	}
+
+	query := `
+		UPDATE vote_sessions
+		SET title = ?, description = ?, vote_type = ?, status = ?, ended_at = ?, options_data = ?, settings_data = ?, decision_data = ?
+		WHERE id = ?
+	`
+
+	result, err := v.db.ExecContext(ctx, query,
+		vote.Title,
+		vote.Description,
+		vote.Type,
+		vote.Status,
+		vote.EndedAt,
+		string(optionsData),
+		string(settingsData),
+		decisionData,
+		vote.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update vote session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+func (v *sqliteVoteSessionRepo) Delete(ctx context.Context, id string) error {
+	result, err := v.db.ExecContext(ctx, `DELETE FROM vote_sessions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vote session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("not found")
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanVoteSession(row rowScanner) (*models.Vote, error) {
+	var vote models.Vote
+	var description sql.NullString
+	var endedAt sql.NullTime
+	var optionsData sql.NullString
+	var settingsData sql.NullString
+	var decisionData sql.NullString
+
+	err := row.Scan(
+		&vote.ID,
+		&vote.RoomID,
+		&vote.Title,
+		&description,
+		&vote.Type,
+		&vote.Status,
+		&vote.CreatedBy,
+		&vote.CreatedAt,
+		&endedAt,
+		&optionsData,
+		&settingsData,
+		&decisionData,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	vote.Description = description.String
+	if endedAt.Valid {
+		vote.EndedAt = &endedAt.Time
+	}
+
+	vote.Options = make([]models.VoteOption, 0)
+	if optionsData.Valid && optionsData.String != "" {
+		if err := json.Unmarshal([]byte(optionsData.String), &vote.Options); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal vote options: %w", err)
+		}
+	}
+	if settingsData.Valid && settingsData.String != "" {
+		if err := json.Unmarshal([]byte(settingsData.String), &vote.Settings); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal vote settings: %w", err)
+		}
+	}
+	if decisionData.Valid && decisionData.String != "" {
+		vote.Decision = &models.VoteDecision{}
+		if err := json.Unmarshal([]byte(decisionData.String), vote.Decision); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal vote decision: %w", err)
+		}
+	}
+	vote.ApplyDefaults()
+
+	return &vote, nil
+}
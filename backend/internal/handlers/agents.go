@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"foundation-sprint/internal/agents"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/models"
 	"net/http"
 	"time"
 
@@ -18,15 +22,27 @@ func InitAgentService() error {
 	if err != nil {
 		return err
 	}
+	if db, err := database.GetDatabase(); err == nil {
+		service.SetUsageStore(db.Usage())
+		builder := agents.NewRoomContextBuilder(db.Rooms(), db.VoteSessions())
+		if store, err := knowledge.GetStore(); err == nil {
+			builder.SetEvidenceSource(store)
+		}
+		service.SetRoomContextBuilder(builder)
+		service.SetProposalStore(db.Proposals())
+		service.SetRiskStore(db.Risks())
+		service.SetHypothesisStore(db.Hypotheses())
+	}
 	AgentService = service
 	return nil
 }
 
 // AgentRequest AI Agent 请求结构
 type AgentRequest struct {
-	Context string                 `json:"context" binding:"required"`
+	Context string                 `json:"context"` // Optional when room_id is set: the room state is loaded server-side
 	Query   string                 `json:"query" binding:"required"`
 	RoomID  string                 `json:"room_id"`
+	UserID  string                 `json:"user_id"`
 	Phase   string                 `json:"phase"`
 	History []HistoryItem          `json:"history"`
 	Data    map[string]interface{} `json:"data"`
@@ -49,6 +65,10 @@ type AgentResponse struct {
 	NextActions []string               `json:"next_actions,omitempty"`
 	Confidence  float64                `json:"confidence"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Usage       agents.TokenUsage      `json:"usage"`
+	Proposals   []*models.Proposal     `json:"proposals,omitempty"`
+	Risks       []*models.Risk         `json:"risks,omitempty"`      // 本次运行新增到风险登记表的风险
+	Hypotheses  []*models.Hypothesis   `json:"hypotheses,omitempty"` // 本次运行记录的待验证假设
 }
 
 // ReasoningStep represents a step in the reasoning process
@@ -93,6 +113,7 @@ func ThinkAgent(c *gin.Context) {
 		Query:   req.Query,
 		Context: req.Context,
 		RoomID:  req.RoomID,
+		UserID:  req.UserID,
 		Phase:   req.Phase,
 		Data:    req.Data,
 	}
@@ -112,13 +133,14 @@ func ThinkAgent(c *gin.Context) {
 	// Process with ThinkAgent
 	output, err := AgentService.ProcessThink(ctx, input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to process request",
-			"details": err.Error(),
-		})
+		respondAgentError(c, err)
 		return
 	}
 
+	announceProposals(input.RoomID, output.Proposals)
+	announceRisks(input.RoomID, output.Risks)
+	announceHypotheses(input.RoomID, output.Hypotheses)
+
 	// Convert output to response
 	response := convertAgentOutput("think", req.Context, output)
 	c.JSON(http.StatusOK, response)
@@ -148,6 +170,7 @@ func CritiqueAgent(c *gin.Context) {
 		Query:   req.Query,
 		Context: req.Context,
 		RoomID:  req.RoomID,
+		UserID:  req.UserID,
 		Phase:   req.Phase,
 		Data:    req.Data,
 	}
@@ -167,13 +190,14 @@ func CritiqueAgent(c *gin.Context) {
 	// Process with CritiqueAgent
 	output, err := AgentService.ProcessCritique(ctx, input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to process request",
-			"details": err.Error(),
-		})
+		respondAgentError(c, err)
 		return
 	}
 
+	announceProposals(input.RoomID, output.Proposals)
+	announceRisks(input.RoomID, output.Risks)
+	announceHypotheses(input.RoomID, output.Hypotheses)
+
 	// Convert output to response
 	response := convertAgentOutput("critique", req.Context, output)
 	c.JSON(http.StatusOK, response)
@@ -203,6 +227,7 @@ func ResearchAgent(c *gin.Context) {
 		Query:   req.Query,
 		Context: req.Context,
 		RoomID:  req.RoomID,
+		UserID:  req.UserID,
 		Phase:   req.Phase,
 		Data:    req.Data,
 	}
@@ -222,18 +247,35 @@ func ResearchAgent(c *gin.Context) {
 	// Process with ResearchAgent
 	output, err := AgentService.ProcessResearch(ctx, input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to process request",
-			"details": err.Error(),
-		})
+		respondAgentError(c, err)
 		return
 	}
 
+	announceProposals(input.RoomID, output.Proposals)
+	announceRisks(input.RoomID, output.Risks)
+	announceHypotheses(input.RoomID, output.Hypotheses)
+
 	// Convert output to response
 	response := convertAgentOutput("research", req.Context, output)
 	c.JSON(http.StatusOK, response)
 }
 
+// respondAgentError writes an agent processing error, using 402 when a budget is exhausted
+func respondAgentError(c *gin.Context, err error) {
+	if errors.Is(err, agents.ErrBudgetExceeded) {
+		c.JSON(http.StatusPaymentRequired, gin.H{
+			"error": "LLM budget exceeded",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": "Failed to process request",
+		"details": err.Error(),
+	})
+}
+
 // convertAgentOutput converts agent output to HTTP response
 func convertAgentOutput(agentName, context string, output *agents.AgentOutput) AgentResponse {
 	response := AgentResponse{
@@ -244,6 +286,10 @@ func convertAgentOutput(agentName, context string, output *agents.AgentOutput) A
 		NextActions: output.NextActions,
 		Confidence:  output.Confidence,
 		Metadata:    output.Metadata,
+		Usage:       output.Usage,
+		Proposals:   output.Proposals,
+		Risks:       output.Risks,
+		Hypotheses:  output.Hypotheses,
 	}
 
 	// Convert reasoning steps
@@ -268,4 +314,48 @@ func convertAgentOutput(agentName, context string, output *agents.AgentOutput) A
 	}
 
 	return response
-}
\ No newline at end of file
+}
+// AgentInfo 已注册 Agent 的描述
+type AgentInfo struct {
+	Name   string   `json:"name"`
+	Role   string   `json:"role"`
+	Custom bool     `json:"custom"`
+	Tools  []string `json:"tools"`
+	Model  string   `json:"model,omitempty"`
+	Source string   `json:"source,omitempty"`
+}
+
+// ListAgents 列出所有可用的 Agent，包括配置文件定义的自定义 Agent
+func ListAgents(c *gin.Context) {
+	if AgentService == nil {
+		if err := InitAgentService(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Agent service not initialized",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	infos := make([]AgentInfo, 0)
+	for _, name := range AgentService.AgentNames() {
+		agent, err := AgentService.GetAgent(name)
+		if err != nil {
+			continue
+		}
+
+		info := AgentInfo{Name: name, Role: agent.GetRole(), Tools: []string{}}
+		for _, tool := range agents.GetToolsForAgent(name) {
+			info.Tools = append(info.Tools, tool.GetName())
+		}
+		if configAgent, ok := agent.(*agents.ConfigAgent); ok {
+			definition := configAgent.Definition()
+			info.Custom = true
+			info.Model = definition.Model
+			info.Source = definition.Source
+		}
+		infos = append(infos, info)
+	}
+
+	c.JSON(http.StatusOK, infos)
+}
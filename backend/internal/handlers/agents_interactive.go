@@ -86,6 +86,7 @@ func ThinkAgentInteractive(c *gin.Context) {
 		Query:   req.Query,
 		Context: req.Context,
 		RoomID:  req.RoomID,
+		UserID:  req.UserID,
 		Phase:   req.Phase,
 		Data:    req.Data,
 	}
@@ -107,16 +108,27 @@ func ThinkAgentInteractive(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Reject the request once the room or workspace budget is spent
+	if err := AgentService.CheckBudget(ctx, input.RoomID); err != nil {
+		respondAgentError(c, err)
+		return
+	}
+	AgentService.EnrichInput(ctx, &input)
+
 	// Process with interactive support
 	output, err := processor.ProcessInteractive(ctx, input, req.SessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to process request",
-			"details": err.Error(),
-		})
+		respondAgentError(c, err)
 		return
 	}
 
+	AgentService.SaveProposals(ctx, output.AgentOutput)
+	AgentService.SaveRisks(ctx, input.RoomID, "ThinkAgent", output.AgentOutput)
+	AgentService.SaveHypotheses(ctx, input.RoomID, "ThinkAgent", output.AgentOutput)
+	announceProposals(input.RoomID, output.AgentOutput.Proposals)
+	announceRisks(input.RoomID, output.AgentOutput.Risks)
+	announceHypotheses(input.RoomID, output.AgentOutput.Hypotheses)
+
 	// Convert to response
 	response := InteractiveAgentResponse{
 		AgentResponse: convertAgentOutput("think", req.Context, output.AgentOutput),
@@ -182,6 +194,7 @@ func CritiqueAgentInteractive(c *gin.Context) {
 		Query:   req.Query,
 		Context: req.Context,
 		RoomID:  req.RoomID,
+		UserID:  req.UserID,
 		Phase:   req.Phase,
 		Data:    req.Data,
 	}
@@ -203,16 +216,27 @@ func CritiqueAgentInteractive(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Reject the request once the room or workspace budget is spent
+	if err := AgentService.CheckBudget(ctx, input.RoomID); err != nil {
+		respondAgentError(c, err)
+		return
+	}
+	AgentService.EnrichInput(ctx, &input)
+
 	// Process with interactive support
 	output, err := processor.ProcessInteractive(ctx, input, req.SessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to process request",
-			"details": err.Error(),
-		})
+		respondAgentError(c, err)
 		return
 	}
 
+	AgentService.SaveProposals(ctx, output.AgentOutput)
+	AgentService.SaveRisks(ctx, input.RoomID, "CritiqueAgent", output.AgentOutput)
+	AgentService.SaveHypotheses(ctx, input.RoomID, "CritiqueAgent", output.AgentOutput)
+	announceProposals(input.RoomID, output.AgentOutput.Proposals)
+	announceRisks(input.RoomID, output.AgentOutput.Risks)
+	announceHypotheses(input.RoomID, output.AgentOutput.Hypotheses)
+
 	// Convert to response
 	response := InteractiveAgentResponse{
 		AgentResponse: convertAgentOutput("critique", req.Context, output.AgentOutput),
@@ -278,6 +302,7 @@ func ResearchAgentInteractive(c *gin.Context) {
 		Query:   req.Query,
 		Context: req.Context,
 		RoomID:  req.RoomID,
+		UserID:  req.UserID,
 		Phase:   req.Phase,
 		Data:    req.Data,
 	}
@@ -299,16 +324,27 @@ func ResearchAgentInteractive(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Reject the request once the room or workspace budget is spent
+	if err := AgentService.CheckBudget(ctx, input.RoomID); err != nil {
+		respondAgentError(c, err)
+		return
+	}
+	AgentService.EnrichInput(ctx, &input)
+
 	// Process with interactive support
 	output, err := processor.ProcessInteractive(ctx, input, req.SessionID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to process request",
-			"details": err.Error(),
-		})
+		respondAgentError(c, err)
 		return
 	}
 
+	AgentService.SaveProposals(ctx, output.AgentOutput)
+	AgentService.SaveRisks(ctx, input.RoomID, "ResearchAgent", output.AgentOutput)
+	AgentService.SaveHypotheses(ctx, input.RoomID, "ResearchAgent", output.AgentOutput)
+	announceProposals(input.RoomID, output.AgentOutput.Proposals)
+	announceRisks(input.RoomID, output.AgentOutput.Risks)
+	announceHypotheses(input.RoomID, output.AgentOutput.Hypotheses)
+
 	// Convert to response
 	response := InteractiveAgentResponse{
 		AgentResponse: convertAgentOutput("research", req.Context, output.AgentOutput),
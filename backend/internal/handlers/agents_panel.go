@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"foundation-sprint/internal/agents"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// agentAliases maps the short names used by the API to registered agent names
+var agentAliases = map[string]string{
+	"think":    "ThinkAgent",
+	"critique": "CritiqueAgent",
+	"research": "ResearchAgent",
+}
+
+// PanelRequest 多 Agent 面板请求
+type PanelRequest struct {
+	AgentRequest
+	Agents []string `json:"agents"` // 参与的 Agent（think/critique/research 或完整名称），为空时全部参与
+	Mode   string   `json:"mode"`   // "parallel"（默认）或 "debate"
+}
+
+// PanelResponse 并行模式的响应，部分 Agent 失败时仍返回其余结果
+type PanelResponse struct {
+	Mode    string                   `json:"mode"`
+	Results map[string]AgentResponse `json:"results"`
+	Errors  map[string]string        `json:"errors,omitempty"`
+}
+
+// DebateTurnResponse 辩论中的一轮发言
+type DebateTurnResponse struct {
+	Stage    string         `json:"stage"`
+	Agent    string         `json:"agent"`
+	Query    string         `json:"query"`
+	Response *AgentResponse `json:"response,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Duration int64          `json:"duration_ms"`
+}
+
+// DebateResponse 辩论模式的响应
+type DebateResponse struct {
+	Mode           string               `json:"mode"`
+	Recommendation string               `json:"recommendation"`
+	Trace          []DebateTurnResponse `json:"trace"`
+	Usage          agents.TokenUsage    `json:"usage"`
+}
+
+// AgentPanel 多 Agent 面板：并行运行多个 Agent，或按 Think → Critique → Research 进行辩论
+func AgentPanel(c *gin.Context) {
+	var req PanelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if AgentService == nil {
+		if err := InitAgentService(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Agent service not initialized",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	input := agents.AgentInput{
+		Query:   req.Query,
+		Context: req.Context,
+		RoomID:  req.RoomID,
+		UserID:  req.UserID,
+		Phase:   req.Phase,
+		Data:    req.Data,
+	}
+	for _, h := range req.History {
+		input.History = append(input.History, agents.ConversationHistory{
+			Role:    h.Role,
+			Content: h.Content,
+		})
+	}
+
+	switch req.Mode {
+	case "", "parallel":
+		runPanel(c, req, input)
+	case "debate":
+		runDebate(c, req, input)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be 'parallel' or 'debate'"})
+	}
+}
+
+func runPanel(c *gin.Context, req PanelRequest, input agents.AgentInput) {
+	names := make([]string, 0, len(req.Agents))
+	for _, name := range req.Agents {
+		if alias, ok := agentAliases[name]; ok {
+			name = alias
+		}
+		names = append(names, name)
+	}
+
+	// Agents run concurrently, so the panel needs no more time than a single agent
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	outputs, errs := AgentService.ProcessMultiAgent(ctx, input, names)
+
+	response := PanelResponse{
+		Mode:    "parallel",
+		Results: make(map[string]AgentResponse),
+		Errors:  make(map[string]string),
+	}
+	for name, output := range outputs {
+		response.Results[shortAgentName(name)] = convertAgentOutput(shortAgentName(name), req.Context, output)
+		announceProposals(input.RoomID, output.Proposals)
+		announceRisks(input.RoomID, output.Risks)
+		announceHypotheses(input.RoomID, output.Hypotheses)
+	}
+	for name, err := range errs {
+		response.Errors[shortAgentName(name)] = err.Error()
+	}
+
+	// Report a failure only when no agent produced a result
+	if len(outputs) == 0 && len(errs) > 0 {
+		for _, err := range errs {
+			if errors.Is(err, agents.ErrBudgetExceeded) {
+				c.JSON(http.StatusPaymentRequired, response)
+				return
+			}
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func runDebate(c *gin.Context, req PanelRequest, input agents.AgentInput) {
+	// The debate runs its agents one after another
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	defer cancel()
+
+	result, err := AgentService.ProcessDebate(ctx, input)
+	if err != nil {
+		respondAgentError(c, err)
+		return
+	}
+
+	response := DebateResponse{
+		Mode:           "debate",
+		Recommendation: result.Recommendation,
+		Trace:          make([]DebateTurnResponse, 0, len(result.Trace)),
+		Usage:          result.Usage,
+	}
+	for _, turn := range result.Trace {
+		turnResponse := DebateTurnResponse{
+			Stage:    turn.Stage,
+			Agent:    shortAgentName(turn.Agent),
+			Query:    turn.Query,
+			Error:    turn.Error,
+			Duration: turn.Duration,
+		}
+		if turn.Output != nil {
+			converted := convertAgentOutput(turnResponse.Agent, req.Context, turn.Output)
+			turnResponse.Response = &converted
+			announceProposals(input.RoomID, turn.Output.Proposals)
+			announceRisks(input.RoomID, turn.Output.Risks)
+			announceHypotheses(input.RoomID, turn.Output.Hypotheses)
+		}
+		response.Trace = append(response.Trace, turnResponse)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// shortAgentName returns the API name of a registered agent
+func shortAgentName(name string) string {
+	for alias, agentName := range agentAliases {
+		if agentName == name {
+			return alias
+		}
+	}
+	return name
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"foundation-sprint/internal/attachments"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/storage"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	attachmentSvc     *attachments.Service
+	attachmentSvcOnce sync.Once
+	attachmentSvcErr  error
+)
+
+// attachmentService 懒加载附件服务，进度通过 WebSocket 广播给房间
+func attachmentService() (*attachments.Service, error) {
+	attachmentSvcOnce.Do(func() {
+		db, err := database.GetDatabase()
+		if err != nil {
+			attachmentSvcErr = err
+			return
+		}
+		blobs, err := storage.GetBlobStore()
+		if err != nil {
+			attachmentSvcErr = err
+			return
+		}
+		store, err := knowledge.GetStore()
+		if err != nil {
+			attachmentSvcErr = err
+			return
+		}
+		attachmentSvc = attachments.NewService(db, blobs, store, BroadcastToRoom)
+	})
+	if attachmentSvcErr != nil {
+		log.Printf("Attachment service not available: %v", attachmentSvcErr)
+	}
+	return attachmentSvc, attachmentSvcErr
+}
+
+// UploadAttachment 上传房间附件（multipart: file, card_id, user_id），后台提取文本并建立索引
+func UploadAttachment(c *gin.Context) {
+	roomID := c.Param("id")
+
+	service, err := attachmentService()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Attachment storage not available"})
+		return
+	}
+
+	// 为 multipart 表单的其他字段预留 1MB
+	maxBytes := attachments.MaxUploadBytes()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("File exceeds the %d byte limit", maxBytes)})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		}
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("File exceeds the %d byte limit", maxBytes)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	attachment, err := service.Upload(ctx, attachments.UploadRequest{
+		RoomID:      roomID,
+		CardID:      c.PostForm("card_id"),
+		UserID:      c.PostForm("user_id"),
+		Filename:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, attachments.ErrUnsupportedFormat):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		case err.Error() == "room not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, attachment)
+}
+
+// GetAttachments 获取房间的附件列表
+func GetAttachments(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := db.Attachments().GetByRoom(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get attachments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": list})
+}
+
+// GetAttachment 获取附件信息及处理状态
+func GetAttachment(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	attachment, err := db.Attachments().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get attachment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, attachment)
+}
+
+// DownloadAttachment 下载附件原文件
+func DownloadAttachment(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+	service, err := attachmentService()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Attachment storage not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	attachment, err := db.Attachments().Get(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+
+	reader, err := service.Open(ctx, attachment)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attachment file not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read attachment"})
+		}
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Length", fmt.Sprintf("%d", attachment.Size))
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, reader)
+}
+
+// ReprocessAttachment 重新提取并索引附件
+func ReprocessAttachment(c *gin.Context) {
+	service, err := attachmentService()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Attachment storage not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	attachment, err := service.Reprocess(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reprocess attachment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, attachment)
+}
+
+// DeleteAttachment 删除附件、文件及其索引
+func DeleteAttachment(c *gin.Context) {
+	service, err := attachmentService()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Attachment storage not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := service.Delete(ctx, c.Param("id")); err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete attachment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted"})
+}
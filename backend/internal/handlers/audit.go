@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"foundation-sprint/internal/database"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRoomAudit 主持人查看房间的审计日志，可按 target_type 与 target_id 筛选
+func GetRoomAudit(c *gin.Context) {
+	roomID := c.Param("id")
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if !requireFacilitator(c, ctx, db, roomID) {
+		return
+	}
+
+	events, err := db.Audit().GetByRoom(ctx, roomID, c.Query("target_type"), c.Query("target_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get audit events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// requireFacilitator 检查 ?user_id= 是否为房间的主持人（创建者）
+func requireFacilitator(c *gin.Context, ctx context.Context, db database.Database, roomID string) bool {
+	room, err := db.Rooms().Get(ctx, roomID)
+	if err != nil || room == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return false
+	}
+	if userID := c.Query("user_id"); userID == "" || userID != room.CreatedBy {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the facilitator can view the audit log"})
+		return false
+	}
+	return true
+}
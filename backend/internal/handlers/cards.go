@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CardRequest 创建或更新基础阶段卡片的请求；更新时未提供的字段保持不变
+type CardRequest struct {
+	Type          string  `json:"type"` // customer/problem/competition/advantage，仅创建时使用
+	Text          *string `json:"text"`
+	Description   *string `json:"description"`
+	PainIntensity *int    `json:"pain_intensity"` // 1-10，0 表示清除
+	AffectedShare *string `json:"affected_share"`
+	Kind          *string `json:"kind"`
+	Category      *string `json:"category"`
+	Order         *int    `json:"order"` // 移动到列表中的位置
+	UserID        string  `json:"user_id"`
+}
+
+// applyTo 将请求中提供的字段写入卡片
+func (r *CardRequest) applyTo(card *models.Card) {
+	setString := func(target *string, value *string) {
+		if value != nil {
+			*target = *value
+		}
+	}
+	setString(&card.Text, r.Text)
+	setString(&card.Description, r.Description)
+	setString(&card.AffectedShare, r.AffectedShare)
+	setString(&card.Kind, r.Kind)
+	setString(&card.Category, r.Category)
+	if r.PainIntensity != nil {
+		card.PainIntensity = *r.PainIntensity
+	}
+}
+
+// CreateFoundationCard 向基础信息中添加一张卡片，默认追加到列表末尾
+func CreateFoundationCard(c *gin.Context) {
+	var req CardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, ok := editFoundation(c, req.UserID, "created", func(foundation *models.Foundation) (*models.Card, int, error) {
+		cards, ok := foundation.List(req.Type)
+		if !ok {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid card type: %s", req.Type)
+		}
+		for _, existing := range *cards {
+			if req.Text != nil && equalText(existing.Text, *req.Text) {
+				return nil, http.StatusConflict, fmt.Errorf("%s card already exists: %s", req.Type, existing.Text)
+			}
+		}
+
+		card := models.NewCard(req.Type, "", req.UserID)
+		req.applyTo(&card)
+		if err := card.Validate(req.Type); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		*cards = insertCard(*cards, card, len(*cards), req.Order)
+		return &card, 0, nil
+	})
+	if ok {
+		c.JSON(http.StatusCreated, card)
+	}
+}
+
+// UpdateFoundationCard 更新卡片的文本与属性，提供 order 时移动卡片
+func UpdateFoundationCard(c *gin.Context) {
+	var req CardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	card, ok := editFoundation(c, req.UserID, "updated", func(foundation *models.Foundation) (*models.Card, int, error) {
+		cards, index, found := foundation.FindCard(c.Param("card_id"))
+		if !found {
+			return nil, http.StatusNotFound, fmt.Errorf("Card not found")
+		}
+
+		card := (*cards)[index]
+		before := card
+		req.applyTo(&card)
+		if err := card.Validate(card.Type); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		for i, existing := range *cards {
+			if i != index && equalText(existing.Text, card.Text) {
+				return nil, http.StatusConflict, fmt.Errorf("%s card already exists: %s", card.Type, existing.Text)
+			}
+		}
+		if !card.SameContent(&before) {
+			card.UpdatedAt = time.Now()
+		}
+
+		remaining := append(append([]models.Card{}, (*cards)[:index]...), (*cards)[index+1:]...)
+		*cards = insertCard(remaining, card, index, req.Order)
+		return &card, 0, nil
+	})
+	if ok {
+		c.JSON(http.StatusOK, card)
+	}
+}
+
+// DeleteFoundationCard 删除卡片
+func DeleteFoundationCard(c *gin.Context) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	// 请求体可以为空
+	_ = c.ShouldBindJSON(&req)
+
+	card, ok := editFoundation(c, req.UserID, "deleted", func(foundation *models.Foundation) (*models.Card, int, error) {
+		cards, index, found := foundation.FindCard(c.Param("card_id"))
+		if !found {
+			return nil, http.StatusNotFound, fmt.Errorf("Card not found")
+		}
+		deleted := (*cards)[index]
+		*cards = append((*cards)[:index], (*cards)[index+1:]...)
+		return &deleted, 0, nil
+	})
+	if ok {
+		c.JSON(http.StatusOK, gin.H{"message": "Card deleted", "card": card})
+	}
+}
+
+// editFoundation 在事务中修改房间的基础信息并保存，然后通知房间。edit 返回被修改的卡片，
+// 出错时返回状态码与错误；成功时返回保存后的卡片（编号已更新），删除时返回被删除的卡片
+func editFoundation(c *gin.Context, userID, action string, edit func(foundation *models.Foundation) (*models.Card, int, error)) (*models.Card, bool) {
+	roomID := c.Param("id")
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return nil, false
+	}
+	defer tx.Rollback()
+
+	room, err := tx.Rooms().Get(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return nil, false
+	}
+
+	card, status, err := edit(&room.Foundation)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if err := tx.Rooms().UpdateFoundation(ctx, roomID, &room.Foundation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update foundation"})
+		return nil, false
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return nil, false
+	}
+
+	if cards, index, found := room.Foundation.FindCard(card.ID); found {
+		card = &(*cards)[index]
+	}
+	BroadcastToRoom(roomID, "foundation_update", gin.H{"userId": userID, "action": action, "card": card})
+	return card, true
+}
+
+// insertCard 将卡片插入到 order 指定的位置，未指定时插入到 fallback
+func insertCard(cards []models.Card, card models.Card, fallback int, order *int) []models.Card {
+	position := fallback
+	if order != nil {
+		position = *order
+	}
+	if position < 0 {
+		position = 0
+	}
+	if position > len(cards) {
+		position = len(cards)
+	}
+
+	result := make([]models.Card, 0, len(cards)+1)
+	result = append(result, cards[:position]...)
+	result = append(result, card)
+	return append(result, cards[position:]...)
+}
+
+// equalText 两段卡片文本是否相同（忽略大小写与首尾空白）
+func equalText(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetApproachDecision 按魔术镜头权重计算各路径的加权得分、单镜头排名、权重敏感度与缺少的评分
+func GetApproachDecision(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	room, err := db.Rooms().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get room"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BuildApproachDecision(room.Approach))
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDuplicates 按相似度聚类房间中某一类别的条目（?kind=customers/problems/competition/advantages/factors），
+// 建议合并的分组与代表文本。配置了嵌入服务时比较嵌入向量，否则比较文本；?threshold= 覆盖默认阈值
+func GetDuplicates(c *gin.Context) {
+	kind := c.Query("kind")
+	if !models.IsDuplicateKind(kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid kind: %s", kind)})
+		return
+	}
+	threshold := 0.0
+	if value := c.Query("threshold"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be between 0 and 1"})
+			return
+		}
+		threshold = parsed
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
+		return
+	}
+
+	// 嵌入服务可能需要较长时间
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	room, err := db.Rooms().Get(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+	items, err := room.DuplicateItems(kind)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i] = item.Text
+	}
+	var similarities [][]float64
+	method := knowledge.SimilarityText
+	if store, err := knowledge.GetStore(); err == nil {
+		similarities, method = store.Similarities(ctx, texts)
+	} else {
+		similarities = knowledge.TextSimilarities(texts)
+	}
+	if threshold == 0 {
+		threshold = models.DefaultTextThreshold
+		if method == knowledge.SimilarityEmbedding {
+			threshold = models.DefaultEmbeddingThreshold
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"kind":      kind,
+		"method":    method,
+		"threshold": threshold,
+		"items":     len(items),
+		"clusters":  models.ClusterDuplicates(items, similarities, threshold),
+	})
+}
+
+// MergeDuplicates 应用接受的合并建议：保留原始条目的作者，投票中对应的选项合并为一个并保留所有人的投票，
+// 关联到被合并卡片的附件改为关联代表卡片
+func MergeDuplicates(c *gin.Context) {
+	roomID := c.Param("id")
+
+	var req struct {
+		Kind   string                  `json:"kind" binding:"required"`
+		Merges []models.DuplicateMerge `json:"merges" binding:"required"`
+		UserID string                  `json:"user_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !models.IsDuplicateKind(req.Kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid kind: %s", req.Kind)})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	room, err := tx.Rooms().Get(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	now := time.Now()
+	results := make([]*models.MergeResult, 0, len(req.Merges))
+	for i, merge := range req.Merges {
+		result, err := room.MergeDuplicates(req.Kind, merge, now)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("merge %d: %v", i+1, err)})
+			return
+		}
+		results = append(results, result)
+	}
+
+	if req.Kind == models.DuplicateFactors {
+		err = tx.Rooms().UpdateDifferentiation(ctx, roomID, &room.Differentiation)
+	} else {
+		err = tx.Rooms().UpdateFoundation(ctx, roomID, &room.Foundation)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save merged items"})
+		return
+	}
+
+	votes, err := tx.VoteSessions().GetByRoom(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get votes"})
+		return
+	}
+	updatedVotes := make([]*models.Vote, 0)
+	for _, vote := range votes {
+		changed := false
+		for _, result := range results {
+			if vote.MergeOptions(result.Texts, result.Label) {
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := tx.VoteSessions().Update(ctx, vote); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vote"})
+			return
+		}
+		updatedVotes = append(updatedVotes, vote)
+	}
+
+	for _, result := range results {
+		for _, merged := range result.Merged {
+			if req.Kind != models.DuplicateFactors {
+				if err := tx.Attachments().RelinkCard(ctx, roomID, merged.ID, result.RepresentativeID); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to relink attachments"})
+					return
+				}
+			}
+		}
+		details := map[string]interface{}{"kind": req.Kind, "label": result.Label, "merged": result.Merged}
+		event := models.NewAuditEvent(roomID, req.UserID, models.AuditItemsMerged, models.AuditTargetCard, result.RepresentativeID, details)
+		if err := tx.Audit().Create(ctx, event); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record audit event"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	if req.Kind == models.DuplicateFactors {
+		BroadcastToRoom(roomID, "differentiation_update", gin.H{"userId": req.UserID, "action": "merged", "merges": results})
+	} else {
+		BroadcastToRoom(roomID, "foundation_update", gin.H{"userId": req.UserID, "action": "merged", "merges": results})
+	}
+	for _, vote := range updatedVotes {
+		broadcastVote(vote, "options_merged", "")
+	}
+
+	c.JSON(http.StatusOK, gin.H{"merges": results, "votes_updated": len(updatedVotes), "room": room})
+}
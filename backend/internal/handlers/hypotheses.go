@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HypothesisRequest 创建或更新假设的请求；更新时未提供的字段保持不变。
+// 未提供 statement 时按模板由 customer、problem、approach、competition、differentiation 生成
+type HypothesisRequest struct {
+	Statement        *string `json:"statement"`
+	Customer         *string `json:"customer"`
+	Problem          *string `json:"problem"`
+	Approach         *string `json:"approach"`
+	PathID           *string `json:"path_id"`
+	Competition      *string `json:"competition"`
+	Differentiation  *string `json:"differentiation"`
+	Confidence       *string `json:"confidence"`
+	ValidationMethod *string `json:"validation_method"`
+	SuccessMetric    *string `json:"success_metric"`
+	Status           *string `json:"status"`
+	Evidence         *string `json:"evidence"`
+	UserID           string  `json:"user_id"`
+}
+
+// applyTo 将请求中提供的字段写入假设；修改了模板部分但未提供陈述时重新生成陈述
+func (r *HypothesisRequest) applyTo(h *models.Hypothesis) {
+	setString := func(target *string, value *string) {
+		if value != nil {
+			*target = strings.TrimSpace(*value)
+		}
+	}
+	setString(&h.Customer, r.Customer)
+	setString(&h.Problem, r.Problem)
+	setString(&h.Approach, r.Approach)
+	setString(&h.PathID, r.PathID)
+	setString(&h.Competition, r.Competition)
+	setString(&h.Differentiation, r.Differentiation)
+	setString(&h.Confidence, r.Confidence)
+	setString(&h.ValidationMethod, r.ValidationMethod)
+	setString(&h.SuccessMetric, r.SuccessMetric)
+	setString(&h.Status, r.Status)
+	setString(&h.Evidence, r.Evidence)
+
+	partsChanged := r.Customer != nil || r.Problem != nil || r.Approach != nil || r.Competition != nil || r.Differentiation != nil
+	if r.Statement != nil {
+		h.Statement = *r.Statement
+	} else if partsChanged {
+		h.Statement = h.BuildStatement()
+	}
+}
+
+// GetHypotheses 获取房间的创始假设，可按 status 过滤
+func GetHypotheses(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hypotheses, err := db.Hypotheses().GetByRoom(ctx, c.Param("id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hypotheses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hypotheses": hypotheses})
+}
+
+// CreateHypothesis 手动添加假设
+func CreateHypothesis(c *gin.Context) {
+	roomID := c.Param("id")
+
+	var req HypothesisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	room, err := db.Rooms().Get(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	hypothesis := models.NewHypothesis(roomID)
+	req.applyTo(hypothesis)
+	hypothesis.CreatedBy = req.UserID
+	if err := validateHypothesis(hypothesis, room); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Hypotheses().Create(ctx, hypothesis); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create hypothesis"})
+		return
+	}
+
+	BroadcastToRoom(roomID, "hypotheses_updated", gin.H{"action": "created", "hypotheses": []*models.Hypothesis{hypothesis}})
+	c.JSON(http.StatusCreated, hypothesis)
+}
+
+// DraftHypotheses 由房间的基础信息与执行路径起草假设，跳过房间中已有的陈述
+func DraftHypotheses(c *gin.Context) {
+	roomID := c.Param("id")
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	// 请求体可以为空
+	_ = c.ShouldBindJSON(&req)
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	room, err := db.Rooms().Get(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	drafts := models.DraftHypotheses(room)
+	if len(drafts) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Room has no customers, problems or paths to draft hypotheses from"})
+		return
+	}
+
+	existing, err := db.Hypotheses().GetByRoom(ctx, roomID, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hypotheses"})
+		return
+	}
+	known := make(map[string]bool, len(existing))
+	for _, hypothesis := range existing {
+		known[strings.ToLower(hypothesis.Statement)] = true
+	}
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	created := make([]*models.Hypothesis, 0, len(drafts))
+	for _, hypothesis := range drafts {
+		if known[strings.ToLower(hypothesis.Statement)] {
+			continue
+		}
+		hypothesis.CreatedBy = req.UserID
+		if err := tx.Hypotheses().Create(ctx, hypothesis); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create hypothesis"})
+			return
+		}
+		created = append(created, hypothesis)
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	announceHypotheses(roomID, created)
+	c.JSON(http.StatusCreated, gin.H{"hypotheses": created, "skipped": len(drafts) - len(created)})
+}
+
+// GetHypothesis 获取单条假设
+func GetHypothesis(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hypothesis, err := db.Hypotheses().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hypothesis not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hypothesis"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, hypothesis)
+}
+
+// UpdateHypothesis 更新假设，例如补充验证方法或记录验证结果
+func UpdateHypothesis(c *gin.Context) {
+	var req HypothesisRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hypothesis, err := db.Hypotheses().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hypothesis not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hypothesis"})
+		}
+		return
+	}
+
+	room, err := db.Rooms().Get(ctx, hypothesis.RoomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	req.applyTo(hypothesis)
+	hypothesis.UpdatedAt = time.Now()
+	if err := validateHypothesis(hypothesis, room); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Hypotheses().Update(ctx, hypothesis); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update hypothesis"})
+		return
+	}
+
+	BroadcastToRoom(hypothesis.RoomID, "hypotheses_updated", gin.H{"action": "updated", "hypotheses": []*models.Hypothesis{hypothesis}})
+	c.JSON(http.StatusOK, hypothesis)
+}
+
+// DeleteHypothesis 删除假设
+func DeleteHypothesis(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hypothesis, err := db.Hypotheses().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hypothesis not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hypothesis"})
+		}
+		return
+	}
+
+	if err := db.Hypotheses().Delete(ctx, hypothesis.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete hypothesis"})
+		return
+	}
+
+	BroadcastToRoom(hypothesis.RoomID, "hypotheses_updated", gin.H{"action": "deleted", "hypotheses": []*models.Hypothesis{hypothesis}})
+	c.JSON(http.StatusOK, gin.H{"message": "Hypothesis deleted"})
+}
+
+// validateHypothesis 校验假设字段，关联的执行路径必须属于该房间
+func validateHypothesis(hypothesis *models.Hypothesis, room *models.Room) error {
+	if err := hypothesis.Validate(); err != nil {
+		return err
+	}
+	if hypothesis.PathID == "" {
+		return nil
+	}
+	for _, path := range room.Approach.Paths {
+		if path.ID == hypothesis.PathID {
+			return nil
+		}
+	}
+	return fmt.Errorf("path %s not found in room", hypothesis.PathID)
+}
+
+// announceHypotheses notifies the room about hypotheses added by an agent run or a draft
+func announceHypotheses(roomID string, hypotheses []*models.Hypothesis) {
+	if roomID == "" || len(hypotheses) == 0 {
+		return
+	}
+	BroadcastToRoom(roomID, "hypotheses_updated", gin.H{"action": "created", "hypotheses": hypotheses})
+}
@@ -0,0 +1,464 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InterviewRequest 创建或更新访谈的请求；更新时未提供的字段保持不变
+type InterviewRequest struct {
+	Title       *string    `json:"title"`
+	Interviewer *string    `json:"interviewer"`
+	ConductedAt *time.Time `json:"conducted_at"` // RFC 3339，默认为创建时间
+	Notes       *string    `json:"notes"`
+	UserID      string     `json:"user_id"`
+}
+
+// applyTo 将请求中提供的字段写入访谈
+func (r *InterviewRequest) applyTo(interview *models.Interview) {
+	if r.Title != nil {
+		interview.Title = *r.Title
+	}
+	if r.Interviewer != nil {
+		interview.Interviewer = strings.TrimSpace(*r.Interviewer)
+	}
+	if r.ConductedAt != nil {
+		interview.ConductedAt = *r.ConductedAt
+	}
+	if r.Notes != nil {
+		interview.Notes = *r.Notes
+	}
+}
+
+// ScorecardRequest 创建或更新计分卡的请求；评级为 red、yellow、green，空字符串表示未评
+type ScorecardRequest struct {
+	Interviewee      *string   `json:"interviewee"`
+	Segment          *string   `json:"segment"`
+	HypothesisIDs    *[]string `json:"hypothesis_ids"`
+	CustomerFit      *string   `json:"customer_fit"`
+	ProblemFit       *string   `json:"problem_fit"`
+	SolutionAppeal   *string   `json:"solution_appeal"`
+	Differentiation  *string   `json:"differentiation"`
+	OverallResonance *string   `json:"overall_resonance"`
+	Notes            *string   `json:"notes"`
+	UserID           string    `json:"user_id"`
+}
+
+// applyTo 将请求中提供的字段写入计分卡
+func (r *ScorecardRequest) applyTo(scorecard *models.Scorecard) {
+	setString := func(target *string, value *string) {
+		if value != nil {
+			*target = strings.TrimSpace(*value)
+		}
+	}
+	setString(&scorecard.Interviewee, r.Interviewee)
+	setString(&scorecard.Segment, r.Segment)
+	setString(&scorecard.CustomerFit, r.CustomerFit)
+	setString(&scorecard.ProblemFit, r.ProblemFit)
+	setString(&scorecard.SolutionAppeal, r.SolutionAppeal)
+	setString(&scorecard.Differentiation, r.Differentiation)
+	setString(&scorecard.OverallResonance, r.OverallResonance)
+	setString(&scorecard.Notes, r.Notes)
+	if r.HypothesisIDs != nil {
+		scorecard.HypothesisIDs = *r.HypothesisIDs
+	}
+}
+
+// GetInterviews 获取房间的访谈及计分卡，按访谈时间排序
+func GetInterviews(c *gin.Context) {
+	roomID := c.Param("id")
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	interviews, err := db.Interviews().GetByRoom(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get interviews"})
+		return
+	}
+
+	scorecards, err := db.Scorecards().GetByRoom(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scorecards"})
+		return
+	}
+	byInterview := make(map[string][]*models.Scorecard)
+	for _, scorecard := range scorecards {
+		byInterview[scorecard.InterviewID] = append(byInterview[scorecard.InterviewID], scorecard)
+	}
+	for _, interview := range interviews {
+		interview.Scorecards = byInterview[interview.ID]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"interviews": interviews})
+}
+
+// CreateInterview 记录一场访谈
+func CreateInterview(c *gin.Context) {
+	roomID := c.Param("id")
+
+	var req InterviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := db.Rooms().Get(ctx, roomID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	interview := models.NewInterview(roomID, "")
+	req.applyTo(interview)
+	interview.CreatedBy = req.UserID
+	if err := interview.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Interviews().Create(ctx, interview); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create interview"})
+		return
+	}
+
+	BroadcastToRoom(roomID, "interviews_updated", gin.H{"action": "created", "interview": interview})
+	c.JSON(http.StatusCreated, interview)
+}
+
+// GetInterview 获取访谈及其计分卡
+func GetInterview(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	interview, ok := loadInterview(ctx, c, db, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	interview.Scorecards, err = db.Scorecards().GetByInterview(ctx, interview.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scorecards"})
+		return
+	}
+
+	c.JSON(http.StatusOK, interview)
+}
+
+// UpdateInterview 更新访谈信息或访谈记录
+func UpdateInterview(c *gin.Context) {
+	var req InterviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	interview, ok := loadInterview(ctx, c, db, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	req.applyTo(interview)
+	interview.UpdatedAt = time.Now()
+	if err := interview.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Interviews().Update(ctx, interview); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update interview"})
+		return
+	}
+
+	BroadcastToRoom(interview.RoomID, "interviews_updated", gin.H{"action": "updated", "interview": interview})
+	c.JSON(http.StatusOK, interview)
+}
+
+// DeleteInterview 删除访谈及其计分卡
+func DeleteInterview(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	interview, ok := loadInterview(ctx, c, db, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := db.Interviews().Delete(ctx, interview.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete interview"})
+		return
+	}
+
+	BroadcastToRoom(interview.RoomID, "interviews_updated", gin.H{"action": "deleted", "interview": interview})
+	c.JSON(http.StatusOK, gin.H{"message": "Interview deleted"})
+}
+
+// CreateScorecard 为访谈中的一位受访者添加计分卡
+func CreateScorecard(c *gin.Context) {
+	var req ScorecardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	interview, ok := loadInterview(ctx, c, db, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	scorecard := models.NewScorecard(interview, "")
+	req.applyTo(scorecard)
+	scorecard.CreatedBy = req.UserID
+	if err := validateScorecard(ctx, db, scorecard); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Scorecards().Create(ctx, scorecard); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scorecard"})
+		return
+	}
+
+	BroadcastToRoom(scorecard.RoomID, "scorecards_updated", gin.H{"action": "created", "scorecard": scorecard})
+	c.JSON(http.StatusCreated, scorecard)
+}
+
+// UpdateScorecard 更新计分卡
+func UpdateScorecard(c *gin.Context) {
+	var req ScorecardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	scorecard, ok := loadScorecard(ctx, c, db, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	req.applyTo(scorecard)
+	scorecard.UpdatedAt = time.Now()
+	if err := validateScorecard(ctx, db, scorecard); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Scorecards().Update(ctx, scorecard); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update scorecard"})
+		return
+	}
+
+	BroadcastToRoom(scorecard.RoomID, "scorecards_updated", gin.H{"action": "updated", "scorecard": scorecard})
+	c.JSON(http.StatusOK, scorecard)
+}
+
+// DeleteScorecard 删除计分卡
+func DeleteScorecard(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	scorecard, ok := loadScorecard(ctx, c, db, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := db.Scorecards().Delete(ctx, scorecard.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete scorecard"})
+		return
+	}
+
+	BroadcastToRoom(scorecard.RoomID, "scorecards_updated", gin.H{"action": "deleted", "scorecard": scorecard})
+	c.JSON(http.StatusOK, gin.H{"message": "Scorecard deleted"})
+}
+
+// GetHypothesisValidations 汇总房间每条假设在各场访谈中的计分卡与趋势
+func GetHypothesisValidations(c *gin.Context) {
+	roomID := c.Param("id")
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hypotheses, err := db.Hypotheses().GetByRoom(ctx, roomID, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hypotheses"})
+		return
+	}
+
+	interviews, scorecards, ok := loadRoomInterviews(ctx, c, db, roomID)
+	if !ok {
+		return
+	}
+
+	validations := make([]*models.HypothesisValidation, 0, len(hypotheses))
+	for _, hypothesis := range hypotheses {
+		validations = append(validations, models.BuildHypothesisValidation(hypothesis, interviews, scorecards))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hypotheses": validations})
+}
+
+// GetHypothesisValidation 汇总单条假设的验证情况
+func GetHypothesisValidation(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hypothesis, err := db.Hypotheses().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Hypothesis not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get hypothesis"})
+		}
+		return
+	}
+
+	interviews, scorecards, ok := loadRoomInterviews(ctx, c, db, hypothesis.RoomID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BuildHypothesisValidation(hypothesis, interviews, scorecards))
+}
+
+// validateScorecard 校验计分卡字段，关联的假设必须属于该房间
+func validateScorecard(ctx context.Context, db database.Database, scorecard *models.Scorecard) error {
+	if err := scorecard.Validate(); err != nil {
+		return err
+	}
+
+	hypotheses, err := db.Hypotheses().GetByRoom(ctx, scorecard.RoomID, "")
+	if err != nil {
+		return fmt.Errorf("failed to get hypotheses: %w", err)
+	}
+	known := make(map[string]bool, len(hypotheses))
+	for _, hypothesis := range hypotheses {
+		known[hypothesis.ID] = true
+	}
+	for _, id := range scorecard.HypothesisIDs {
+		if !known[id] {
+			return fmt.Errorf("hypothesis %s not found in room", id)
+		}
+	}
+	return nil
+}
+
+// loadInterview 读取访谈，失败时写入错误响应
+func loadInterview(ctx context.Context, c *gin.Context, db database.Database, id string) (*models.Interview, bool) {
+	interview, err := db.Interviews().Get(ctx, id)
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get interview"})
+		}
+		return nil, false
+	}
+	return interview, true
+}
+
+// loadScorecard 读取计分卡，失败时写入错误响应
+func loadScorecard(ctx context.Context, c *gin.Context, db database.Database, id string) (*models.Scorecard, bool) {
+	scorecard, err := db.Scorecards().Get(ctx, id)
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scorecard not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scorecard"})
+		}
+		return nil, false
+	}
+	return scorecard, true
+}
+
+// loadRoomInterviews 读取房间的访谈与计分卡，失败时写入错误响应
+func loadRoomInterviews(ctx context.Context, c *gin.Context, db database.Database, roomID string) ([]*models.Interview, []*models.Scorecard, bool) {
+	interviews, err := db.Interviews().GetByRoom(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get interviews"})
+		return nil, nil, false
+	}
+	scorecards, err := db.Scorecards().GetByRoom(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get scorecards"})
+		return nil, nil, false
+	}
+	return interviews, scorecards, true
+}
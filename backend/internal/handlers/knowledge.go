@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/knowledge"
+	"foundation-sprint/internal/models"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxKnowledgeDocumentBytes 上传资料的大小上限
+const maxKnowledgeDocumentBytes = 1 << 20
+
+// knowledgeIndexTimeout 索引包含向量化请求，给予比普通请求更长的时间
+const knowledgeIndexTimeout = 60 * time.Second
+
+// KnowledgeDocumentRequest 上传资料请求
+type KnowledgeDocumentRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+	RoomID  string `json:"room_id"`
+	UserID  string `json:"user_id"`
+}
+
+// SearchKnowledge 检索知识库
+func SearchKnowledge(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+
+	store, err := knowledge.GetStore()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Knowledge base not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	response, err := store.Search(ctx, query, knowledge.SearchOptions{
+		Limit:      limit,
+		SourceType: c.Query("source_type"),
+		RoomID:     c.Query("room_id"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListKnowledgeDocuments 列出知识库文档，可按 source_type 过滤
+func ListKnowledgeDocuments(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	documents, err := db.Knowledge().ListDocuments(ctx, c.Query("source_type"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list documents"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"documents": documents})
+}
+
+// GetKnowledgeDocument 获取知识库文档及其内容
+func GetKnowledgeDocument(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	document, err := db.Knowledge().GetDocument(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get document"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// CreateKnowledgeDocument 上传资料（访谈记录、市场报告等）并建立索引
+func CreateKnowledgeDocument(c *gin.Context) {
+	var req KnowledgeDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Content) > maxKnowledgeDocumentBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Document is too large"})
+		return
+	}
+
+	store, err := knowledge.GetStore()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Knowledge base not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), knowledgeIndexTimeout)
+	defer cancel()
+
+	document, err := store.AddDocument(ctx, req.Title, req.Content, req.RoomID, req.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// DeleteKnowledgeDocument 删除知识库文档
+func DeleteKnowledgeDocument(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.Knowledge().DeleteDocument(ctx, c.Param("id")); err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete document"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Document deleted"})
+}
+
+// IndexRoomKnowledge 将房间内容加入知识库（房间完成时会自动索引）
+func IndexRoomKnowledge(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+	store, err := knowledge.GetStore()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Knowledge base not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), knowledgeIndexTimeout)
+	defer cancel()
+
+	room, err := db.Rooms().Get(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	document, err := store.IndexRoom(ctx, room)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to index room"})
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+// ReindexKnowledge 重建全部文档的分块与向量，更换向量模型后使用
+func ReindexKnowledge(c *gin.Context) {
+	store, err := knowledge.GetStore()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Knowledge base not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	count, err := store.Reindex(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "reindexed": count})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reindexed": count, "mode": store.Mode()})
+}
+
+// indexCompletedRoom 在后台将已完成的房间加入知识库
+func indexCompletedRoom(room *models.Room) {
+	store, err := knowledge.GetStore()
+	if err != nil {
+		log.Printf("Knowledge base not available, room %s not indexed: %v", room.ID, err)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), knowledgeIndexTimeout)
+		defer cancel()
+
+		if _, err := store.IndexRoom(ctx, room); err != nil {
+			log.Printf("Failed to index room %s: %v", room.ID, err)
+			return
+		}
+		log.Printf("Room %s indexed into the knowledge base", room.ID)
+	}()
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMatrixAnalysis 分析 2x2 矩阵：各产品的象限、胜利象限的排他性、最近的竞争对手与坐标轴建议
+func GetMatrixAnalysis(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	room, err := db.Rooms().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get room"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BuildMatrixAnalysis(room.Differentiation))
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// phaseGates 阶段退出条件，由 InitPhaseGates 在启动时加载
+var phaseGates = models.DefaultPhaseGates()
+
+// InitPhaseGates 加载 PHASE_GATES_FILE 中的退出条件，未设置时使用默认条件
+func InitPhaseGates() error {
+	path := os.Getenv("PHASE_GATES_FILE")
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read phase gates file: %w", err)
+	}
+	gates, err := models.ParsePhaseGates(data)
+	if err != nil {
+		return err
+	}
+	phaseGates = gates
+	return nil
+}
+
+// GetPhaseGate 预览房间进入 ?to= 阶段的退出条件检查结果
+func GetPhaseGate(c *gin.Context) {
+	roomID := c.Param("id")
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	room, err := db.Rooms().Get(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	result, err := room.CheckPhaseTransition(c.Query("to"), phaseGates)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// phaseGateError 退出条件未满足时返回给客户端的错误说明，未通过的检查项由 unmet 字段单独返回
+func phaseGateError(result *models.PhaseGateResult) string {
+	return fmt.Sprintf("Exit criteria for %s not met", result.From)
+}
+
+// broadcastPhaseChange 通知房间阶段已变更
+func broadcastPhaseChange(roomID, userID string, result *models.PhaseGateResult, override bool) {
+	BroadcastToRoom(roomID, "phase_changed", gin.H{
+		"from":     result.From,
+		"to":       result.To,
+		"userId":   userID,
+		"override": override,
+		"checks":   result.Checks,
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"foundation-sprint/internal/report"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deckThemes 路演文稿主题，由 InitDeckThemes 在启动时加载
+var deckThemes = report.DefaultDeckThemes()
+
+// InitDeckThemes 加载 PITCH_DECK_THEMES_FILE 中的主题，未设置时只使用内置主题
+func InitDeckThemes() error {
+	path := os.Getenv("PITCH_DECK_THEMES_FILE")
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read deck themes file: %w", err)
+	}
+	themes, err := report.ParseDeckThemes(data)
+	if err != nil {
+		return err
+	}
+	deckThemes = themes
+	return nil
+}
+
+// GetPitchDeck 将已完成的房间导出为投资人路演文稿（PPTX），?theme= 指定主题，?version= 指定模板版本
+func GetPitchDeck(c *gin.Context) {
+	roomID := c.Param("id")
+
+	themeName := c.DefaultQuery("theme", report.DefaultDeckTheme)
+	theme, ok := deckThemes[themeName]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("theme must be one of %s", strings.Join(report.DeckThemeNames(deckThemes), ", "))})
+		return
+	}
+	version := c.Query("version")
+	if version != "" && !report.IsVersion(version) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("template version must be one of %s", strings.Join(report.Versions, ", "))})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sprint, err := report.Load(ctx, db, roomID)
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load room"})
+		}
+		return
+	}
+	if sprint.Room.Status != models.PhaseCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Pitch deck requires a completed room"})
+		return
+	}
+
+	output, err := report.PitchDeck(sprint, theme, version, time.Now())
+	if err != nil {
+		log.Printf("Failed to render pitch deck for room %s: %v", roomID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render pitch deck"})
+		return
+	}
+
+	filename := sprint.Room.Name + " - Pitch Deck" + output.Extension
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Data(http.StatusOK, output.ContentType, output.Body)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProposalDecisionRequest 主持人处理提议的请求
+type ProposalDecisionRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// GetProposals 获取房间的 Agent 提议，可按 status 过滤
+func GetProposals(c *gin.Context) {
+	roomID := c.Param("id")
+	status := c.Query("status")
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	proposals, err := db.Proposals().GetByRoom(ctx, roomID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get proposals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, proposals)
+}
+
+// AcceptProposal 主持人接受提议，将修改应用到房间并广播
+func AcceptProposal(c *gin.Context) {
+	decideProposal(c, models.ProposalAccepted)
+}
+
+// RejectProposal 主持人拒绝提议
+func RejectProposal(c *gin.Context) {
+	decideProposal(c, models.ProposalRejected)
+}
+
+func decideProposal(c *gin.Context, status string) {
+	proposalID := c.Param("id")
+
+	var req ProposalDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	proposal, err := tx.Proposals().Get(ctx, proposalID)
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Proposal not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get proposal"})
+		}
+		return
+	}
+
+	if proposal.Status != models.ProposalPending {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Proposal already %s", proposal.Status)})
+		return
+	}
+
+	room, err := tx.Rooms().Get(ctx, proposal.RoomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	// 只有主持人（房间创建者）可以处理提议
+	if room.CreatedBy != req.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the facilitator can decide on proposals"})
+		return
+	}
+
+	phase := ""
+	if status == models.ProposalAccepted {
+		phase, err = proposal.ApplyTo(room)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := database.SaveRoomPhase(ctx, tx.Rooms(), room, phase); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply proposal"})
+			return
+		}
+	}
+
+	if err := tx.Proposals().UpdateStatus(ctx, proposal.ID, status, req.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update proposal"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save decision"})
+		return
+	}
+
+	now := time.Now()
+	proposal.Status = status
+	proposal.DecidedBy = req.UserID
+	proposal.DecidedAt = &now
+
+	BroadcastToRoom(room.ID, "proposal_updated", proposal)
+	if phase != "" {
+		// 与前端协作消息保持一致，其他成员收到后刷新房间数据
+		BroadcastToRoom(room.ID, phase+"_update", gin.H{
+			"userId":      req.UserID,
+			"proposal_id": proposal.ID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"proposal": proposal,
+		"room":     room,
+	})
+}
+
+// announceProposals notifies the room about new pending proposals
+func announceProposals(roomID string, proposals []*models.Proposal) {
+	if roomID == "" || len(proposals) == 0 {
+		return
+	}
+	BroadcastToRoom(roomID, "proposals_created", proposals)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/report"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRoomReport 导出房间报告，包含各阶段内容、创始假设、访谈验证、风险登记表、投票结果与 Agent 亮点。
+// ?format= 为 md（默认）、html 或 docx，?version= 指定模板版本，未指定时使用最新版本
+func GetRoomReport(c *gin.Context) {
+	roomID := c.Param("id")
+
+	format := c.DefaultQuery("format", report.FormatMarkdown)
+	if !report.IsFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("format must be one of %s", strings.Join(report.Formats, ", "))})
+		return
+	}
+	version := c.Query("version")
+	if version != "" && !report.IsVersion(version) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("template version must be one of %s", strings.Join(report.Versions, ", "))})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sprint, err := report.Load(ctx, db, roomID)
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load report"})
+		}
+		return
+	}
+
+	output, err := report.Render(sprint, format, version, time.Now())
+	if err != nil {
+		log.Printf("Failed to render report for room %s: %v", roomID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render report"})
+		return
+	}
+
+	// Word 文档作为附件下载，Markdown 与 HTML 直接显示
+	disposition := "inline"
+	if format == report.FormatDOCX {
+		disposition = "attachment"
+	}
+	filename := sprint.Room.Name + " - Foundation Sprint" + output.Extension
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": filename}))
+	c.Data(http.StatusOK, output.ContentType, output.Body)
+}
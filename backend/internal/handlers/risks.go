@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RiskRequest 创建或更新风险的请求；更新时未提供的字段保持不变
+type RiskRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Category    *string `json:"category"`
+	Likelihood  *int    `json:"likelihood"` // 1-5
+	Impact      *int    `json:"impact"`     // 1-5
+	Owner       *string `json:"owner"`
+	Mitigation  *string `json:"mitigation"`
+	Status      *string `json:"status"`
+	PathID      *string `json:"path_id"`
+	Assumption  *string `json:"assumption"`
+	UserID      string  `json:"user_id"`
+}
+
+// applyTo 将请求中提供的字段写入风险
+func (r *RiskRequest) applyTo(risk *models.Risk) {
+	setString := func(target *string, value *string) {
+		if value != nil {
+			*target = *value
+		}
+	}
+	setString(&risk.Title, r.Title)
+	setString(&risk.Description, r.Description)
+	setString(&risk.Category, r.Category)
+	setString(&risk.Owner, r.Owner)
+	setString(&risk.Mitigation, r.Mitigation)
+	setString(&risk.Status, r.Status)
+	setString(&risk.PathID, r.PathID)
+	setString(&risk.Assumption, r.Assumption)
+	if r.Likelihood != nil {
+		risk.Likelihood = *r.Likelihood
+	}
+	if r.Impact != nil {
+		risk.Impact = *r.Impact
+	}
+}
+
+// GetRisks 获取房间的风险登记表，按得分从高到低，可按 status 过滤
+func GetRisks(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	risks, err := db.Risks().GetByRoom(ctx, c.Param("id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get risks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"risks": risks})
+}
+
+// CreateRisk 手动登记风险
+func CreateRisk(c *gin.Context) {
+	roomID := c.Param("id")
+
+	var req RiskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	room, err := db.Rooms().Get(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	risk := models.NewRisk(roomID, "")
+	req.applyTo(risk)
+	risk.CreatedBy = req.UserID
+	if err := validateRisk(risk, room); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Risks().Create(ctx, risk); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create risk"})
+		return
+	}
+
+	BroadcastToRoom(roomID, "risks_updated", gin.H{"action": "created", "risks": []*models.Risk{risk}})
+	c.JSON(http.StatusCreated, risk)
+}
+
+// GetRisk 获取单条风险
+func GetRisk(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	risk, err := db.Risks().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Risk not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get risk"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, risk)
+}
+
+// UpdateRisk 更新风险，例如调整评分、指定负责人或关闭风险
+func UpdateRisk(c *gin.Context) {
+	var req RiskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	risk, err := db.Risks().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Risk not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get risk"})
+		}
+		return
+	}
+
+	room, err := db.Rooms().Get(ctx, risk.RoomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	req.applyTo(risk)
+	risk.UpdatedAt = time.Now()
+	if err := validateRisk(risk, room); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Risks().Update(ctx, risk); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update risk"})
+		return
+	}
+
+	BroadcastToRoom(risk.RoomID, "risks_updated", gin.H{"action": "updated", "risks": []*models.Risk{risk}})
+	c.JSON(http.StatusOK, risk)
+}
+
+// DeleteRisk 删除风险
+func DeleteRisk(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	risk, err := db.Risks().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Risk not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get risk"})
+		}
+		return
+	}
+
+	if err := db.Risks().Delete(ctx, risk.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete risk"})
+		return
+	}
+
+	BroadcastToRoom(risk.RoomID, "risks_updated", gin.H{"action": "deleted", "risks": []*models.Risk{risk}})
+	c.JSON(http.StatusOK, gin.H{"message": "Risk deleted"})
+}
+
+// GetRiskHeatmap 按可能性 × 影响聚合房间风险；默认只统计未关闭、未接受的风险
+func GetRiskHeatmap(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	risks, err := db.Risks().GetByRoom(ctx, c.Param("id"), "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get risks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BuildRiskHeatmap(risks, c.Query("include_inactive") == "true"))
+}
+
+// validateRisk 校验风险字段，关联的执行路径必须属于该房间
+func validateRisk(risk *models.Risk, room *models.Room) error {
+	if err := risk.Validate(); err != nil {
+		return err
+	}
+	if risk.PathID == "" {
+		return nil
+	}
+	for _, path := range room.Approach.Paths {
+		if path.ID == risk.PathID {
+			return nil
+		}
+	}
+	return fmt.Errorf("path %s not found in room", risk.PathID)
+}
+
+// announceRisks notifies the room about risks added by an agent run
+func announceRisks(roomID string, risks []*models.Risk) {
+	if roomID == "" || len(risks) == 0 {
+		return
+	}
+	BroadcastToRoom(roomID, "risks_updated", gin.H{"action": "created", "risks": risks})
+}
@@ -6,16 +6,18 @@ import (
 	"foundation-sprint/internal/models"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// CreateRoom 创建新房间
+// CreateRoom 创建新房间，提供 template_id 时按模板预先填入差异化因素、魔术镜头、议程与 Agent 设置
 func CreateRoom(c *gin.Context) {
 	var req struct {
-		Name      string `json:"name" binding:"required"`
-		CreatedBy string `json:"created_by" binding:"required"`
+		Name       string `json:"name" binding:"required"`
+		CreatedBy  string `json:"created_by" binding:"required"`
+		TemplateID string `json:"template_id"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -30,15 +32,22 @@ func CreateRoom(c *gin.Context) {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	
 	// Create room
 	room := models.NewRoom(req.Name, req.CreatedBy)
+	if req.TemplateID != "" {
+		template, err := db.Templates().Get(ctx, req.TemplateID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
+		room = template.NewRoom(req.Name, req.CreatedBy)
+	}
 	
 	// Save to database
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	
-	if err := db.Rooms().Create(ctx, room); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create room"})
+	if !saveNewRoom(c, ctx, db, room) {
 		return
 	}
 
@@ -94,9 +103,24 @@ func UpdateFoundation(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	
-	beforeRoom, _ := db.Rooms().Get(ctx, roomID)
+	beforeRoom, err := db.Rooms().Get(ctx, roomID)
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get room"})
+		}
+		return
+	}
 	log.Printf("🔍 UpdateFoundation - 更新前房间状态: %s, ID: %s", beforeRoom.Status, roomID)
 	
+	// 沿用已有卡片的 ID、作者与创建时间；旧客户端提交的字符串按文本匹配已有卡片
+	if err := foundation.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	foundation.Reconcile(&beforeRoom.Foundation, c.Query("user_id"), time.Now())
+	
 	if err := db.Rooms().UpdateFoundation(ctx, roomID, &foundation); err != nil {
 		if err != nil && err.Error() == "not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
@@ -133,11 +157,20 @@ func UpdateDifferentiation(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := models.ValidateMatrix(differentiation.Matrix); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Update in database
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	
+	// 保留因素的合并记录
+	if current, err := db.Rooms().Get(ctx, roomID); err == nil {
+		differentiation.KeepMerged(&current.Differentiation)
+	}
+	
 	if err := db.Rooms().UpdateDifferentiation(ctx, roomID, &differentiation); err != nil {
 		if err != nil && err.Error() == "not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
@@ -157,12 +190,16 @@ func UpdateDifferentiation(c *gin.Context) {
 	c.JSON(http.StatusOK, room)
 }
 
-// UpdateRoomStatus 更新房间状态
+// UpdateRoomStatus 更新房间状态：只能前进或后退一个阶段，前进时需要满足当前阶段的退出条件，
+// 条件未满足时主持人可以填写理由强制推进，并记入审计日志
 func UpdateRoomStatus(c *gin.Context) {
 	roomID := c.Param("id")
 	
 	var req struct {
-		Status string `json:"status" binding:"required,oneof=foundation differentiation approach completed"`
+		Status   string `json:"status" binding:"required,oneof=foundation differentiation approach completed"`
+		UserID   string `json:"user_id"`
+		Override bool   `json:"override"`
+		Reason   string `json:"reason"`
 	}
 	
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -177,27 +214,87 @@ func UpdateRoomStatus(c *gin.Context) {
 		return
 	}
 	
-	// Update status in database
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	
-	if err := db.Rooms().UpdateStatus(ctx, roomID, req.Status); err != nil {
-		if err != nil && err.Error() == "not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+	
+	room, err := tx.Rooms().Get(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+	
+	// 检查阶段变更与退出条件
+	gate, err := room.CheckPhaseTransition(req.Status, phaseGates)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	
+	forced := false
+	if !gate.Passed {
+		if !req.Override {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": phaseGateError(gate),
+				"unmet": gate.Unmet(),
+				"gate":  gate,
+			})
+			return
+		}
+		if req.UserID == "" || req.UserID != room.CreatedBy {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the facilitator can override exit criteria"})
+			return
 		}
+		if strings.TrimSpace(req.Reason) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required to override exit criteria"})
+			return
+		}
+		forced = true
+	}
+	
+	if err := tx.Rooms().UpdateStatus(ctx, roomID, req.Status); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+		return
+	}
+	
+	action := models.AuditPhaseChange
+	details := map[string]interface{}{"from": gate.From, "to": gate.To}
+	if forced {
+		action = models.AuditPhaseForced
+		details["reason"] = strings.TrimSpace(req.Reason)
+		details["failed"] = gate.Failed()
+	}
+	if err := tx.Audit().Create(ctx, models.NewAuditEvent(roomID, req.UserID, action, models.AuditTargetRoom, roomID, details)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record audit event"})
+		return
+	}
+	
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
 		return
 	}
 	
 	// Get updated room
-	room, err := db.Rooms().Get(ctx, roomID)
+	room, err = db.Rooms().Get(ctx, roomID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get updated room"})
 		return
 	}
 	
-	log.Printf("✅ Room状态更新: %s -> %s, ID: %s", room.Status, req.Status, roomID)
+	log.Printf("✅ Room状态更新: %s -> %s, ID: %s", gate.From, gate.To, roomID)
+	broadcastPhaseChange(roomID, req.UserID, gate, forced)
+	
+	// 完成的房间加入知识库，供后续 Sprint 参考
+	if req.Status == models.PhaseCompleted {
+		indexCompletedRoom(room)
+	}
+	
 	c.JSON(http.StatusOK, room)
 }
 
@@ -217,6 +314,10 @@ func UpdateApproach(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := models.ValidateMagicLenses(approach.MagicLenses); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Update in database
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
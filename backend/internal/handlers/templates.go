@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateRequest 创建或更新房间模板的请求；更新时替换模板的全部内容
+type TemplateRequest struct {
+	Name           string                         `json:"name" binding:"required"`
+	Description    string                         `json:"description"`
+	Industry       string                         `json:"industry"`
+	ClassicFactors []models.DifferentiationFactor `json:"classic_factors"`
+	MagicLenses    []models.MagicLens             `json:"magic_lenses"`
+	Agenda         []models.AgendaItem            `json:"agenda"`
+	Agents         models.AgentSettings           `json:"agents"`
+	CreatedBy      string                         `json:"created_by"`
+}
+
+// applyTo 将请求写入模板
+func (r *TemplateRequest) applyTo(template *models.RoomTemplate) {
+	template.Name = r.Name
+	template.Description = strings.TrimSpace(r.Description)
+	template.Industry = strings.TrimSpace(r.Industry)
+	template.ClassicFactors = r.ClassicFactors
+	template.MagicLenses = r.MagicLenses
+	template.Agenda = r.Agenda
+	template.Agents = r.Agents
+}
+
+// GetTemplates 列出房间模板
+func GetTemplates(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	templates, err := db.Templates().List(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// CreateTemplate 创建房间模板
+func CreateTemplate(c *gin.Context) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template := models.NewRoomTemplate(req.Name, req.CreatedBy)
+	req.applyTo(template)
+	if err := template.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.Templates().Create(ctx, template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetTemplate 获取房间模板
+func GetTemplate(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	template, ok := getTemplate(c, ctx, db)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate 更新房间模板；已按模板创建的房间不受影响
+func UpdateTemplate(c *gin.Context) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	template, ok := getTemplate(c, ctx, db)
+	if !ok {
+		return
+	}
+
+	req.applyTo(template)
+	template.UpdatedAt = time.Now()
+	if err := template.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := db.Templates().Update(ctx, template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate 删除房间模板
+func DeleteTemplate(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.Templates().Delete(ctx, c.Param("id")); err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete template"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted"})
+}
+
+// CloneRoom 将房间克隆为新房间，保留 phases 中阶段的数据与房间设置；投票、成员、提议、风险等不会带到新房间，
+// 新房间的主持人为 created_by
+func CloneRoom(c *gin.Context) {
+	var req struct {
+		Name      string   `json:"name"`
+		CreatedBy string   `json:"created_by" binding:"required"`
+		Phases    []string `json:"phases"` // 保留的阶段：foundation/differentiation/approach
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	source, err := db.Rooms().Get(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = fmt.Sprintf("%s（副本）", source.Name)
+	}
+	room, err := source.Clone(name, req.CreatedBy, req.Phases)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !saveNewRoom(c, ctx, db, room) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, room)
+}
+
+// UpdateRoomSettings 主持人更新房间的议程与 Agent 设置
+func UpdateRoomSettings(c *gin.Context) {
+	roomID := c.Param("id")
+
+	var req struct {
+		Agenda []models.AgendaItem  `json:"agenda"`
+		Agents models.AgentSettings `json:"agents"`
+		UserID string               `json:"user_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	room, err := tx.Rooms().Get(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
+		return
+	}
+	if req.UserID == "" || req.UserID != room.CreatedBy {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the facilitator can change room settings"})
+		return
+	}
+
+	settings := room.Settings
+	settings.Agenda = req.Agenda
+	settings.Agents = req.Agents
+	if err := settings.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Rooms().UpdateSettings(ctx, roomID, &settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update settings"})
+		return
+	}
+	// 预算变化时同步到用量预算；设为 0 时恢复默认预算
+	if settings.Agents.BudgetUSD != room.Settings.Agents.BudgetUSD {
+		if err := tx.Usage().SetBudget(ctx, models.BudgetScopeRoom, roomID, settings.Agents.BudgetUSD); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update room budget"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return
+	}
+
+	BroadcastToRoom(roomID, "settings_update", gin.H{"userId": req.UserID, "settings": settings})
+	c.JSON(http.StatusOK, settings)
+}
+
+// getTemplate 按路径中的 ID 获取模板，失败时写入响应
+func getTemplate(c *gin.Context, ctx context.Context, db database.Database) (*models.RoomTemplate, bool) {
+	template, err := db.Templates().Get(ctx, c.Param("id"))
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get template"})
+		}
+		return nil, false
+	}
+	return template, true
+}
+
+// saveNewRoom 在事务中保存新房间，并将房间设置中的 Agent 预算写入用量预算
+func saveNewRoom(c *gin.Context, ctx context.Context, db database.Database, room *models.Room) bool {
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return false
+	}
+	defer tx.Rollback()
+
+	if err := tx.Rooms().Create(ctx, room); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create room"})
+		return false
+	}
+	if budget := room.Settings.Agents.BudgetUSD; budget > 0 {
+		if err := tx.Usage().SetBudget(ctx, models.BudgetScopeRoom, room.ID, budget); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set room budget"})
+			return false
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"foundation-sprint/internal/agents"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BudgetRequest 预算设置请求，limit_usd <= 0 表示删除覆盖值，恢复为默认预算
+// （LLM_BUDGET_ROOM_USD / LLM_BUDGET_WORKSPACE_USD）
+type BudgetRequest struct {
+	LimitUSD float64 `json:"limit_usd"`
+}
+
+// GetRoomUsage 获取房间的 LLM 用量及费用
+func GetRoomUsage(c *gin.Context) {
+	roomID := c.Param("id")
+	groupBy := c.DefaultQuery("group_by", "agent")
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	groups, err := db.Usage().SummarizeRoom(ctx, roomID, groupBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	total := models.UsageSummary{Key: roomID}
+	for _, group := range groups {
+		total.Calls += group.Calls
+		total.PromptTokens += group.PromptTokens
+		total.CompletionTokens += group.CompletionTokens
+		total.TotalTokens += group.TotalTokens
+		total.CostUSD += group.CostUSD
+		total.UnpricedCalls += group.UnpricedCalls
+	}
+
+	tracker, err := usageTracker(db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := tracker.Limit(ctx, models.BudgetScopeRoom, roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get budget"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room_id":  roomID,
+		"group_by": groupBy,
+		"total":    total,
+		"groups":   groups,
+		"budget": gin.H{
+			"limit_usd": limit,
+			"spent_usd": total.CostUSD,
+		},
+	})
+}
+
+// GetDailyUsage 按天汇总 LLM 用量，可按房间过滤，默认最近 30 天
+func GetDailyUsage(c *gin.Context) {
+	roomID := c.Query("room_id")
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -29)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+			return
+		}
+		to = parsed
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	days, err := db.Usage().SummarizeByDay(ctx, roomID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"room_id": roomID,
+		"from":    from.Format("2006-01-02"),
+		"to":      to.Format("2006-01-02"),
+		"days":    days,
+	})
+}
+
+// SetRoomBudget 设置房间预算（覆盖 LLM_BUDGET_ROOM_USD）
+func SetRoomBudget(c *gin.Context) {
+	setBudget(c, models.BudgetScopeRoom, c.Param("id"))
+}
+
+// SetWorkspaceBudget 设置工作区月度预算（覆盖 LLM_BUDGET_WORKSPACE_USD）
+func SetWorkspaceBudget(c *gin.Context) {
+	setBudget(c, models.BudgetScopeWorkspace, agents.WorkspaceBudgetID)
+}
+
+// GetPricing 获取当前使用的模型价格表
+func GetPricing(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	tracker, err := usageTracker(db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"unit":   "USD per 1M tokens",
+		"models": tracker.Prices(),
+	})
+}
+
+func setBudget(c *gin.Context, scope, scopeID string) {
+	var req BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not available"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.Usage().SetBudget(ctx, scope, scopeID, req.LimitUSD); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set budget"})
+		return
+	}
+
+	// 返回生效的预算：删除覆盖值后为默认预算
+	tracker, err := usageTracker(db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := tracker.Limit(ctx, scope, scopeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get budget"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scope":     scope,
+		"scope_id":  scopeID,
+		"limit_usd": limit,
+	})
+}
+
+// usageTracker returns the agent service's tracker, or a standalone one when the
+// agent service is not running (e.g. no LLM API key configured)
+func usageTracker(db database.Database) (*agents.UsageTracker, error) {
+	if AgentService != nil {
+		return AgentService.Usage, nil
+	}
+
+	prices, err := agents.LoadPriceTable()
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := agents.NewUsageTracker(prices, agents.LoadBudgetConfig())
+	tracker.SetStore(db.Usage())
+	return tracker, nil
+}
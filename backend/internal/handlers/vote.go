@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"foundation-sprint/internal/database"
 	"foundation-sprint/internal/models"
 	"net/http"
@@ -10,20 +11,18 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// 临时内存存储投票数据
-var votes = make(map[string]*models.Vote)
-var roomVotes = make(map[string][]string) // roomID -> []voteID
-
 // CreateVote 创建投票
 func CreateVote(c *gin.Context) {
 	roomID := c.Param("id")
-	
+
 	var req struct {
-		Title       string   `json:"title" binding:"required"`
-		Description string   `json:"description"`
-		Type        string   `json:"type" binding:"required"`
-		Options     []string `json:"options"`
-		CreatedBy   string   `json:"created_by" binding:"required"`
+		Title       string              `json:"title" binding:"required"`
+		Description string              `json:"description"`
+		Type        string              `json:"type" binding:"required"`
+		Options     []string            `json:"options"`
+		Settings    models.VoteSettings `json:"settings"`
+		Status      string              `json:"status"` // draft 或 open（默认）
+		CreatedBy   string              `json:"created_by" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -37,10 +36,10 @@ func CreateVote(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
 		return
 	}
-	
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	
+
 	room, err := db.Rooms().Get(ctx, roomID)
 	if err != nil || room == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
@@ -48,76 +47,363 @@ func CreateVote(c *gin.Context) {
 	}
 
 	vote := models.NewVote(roomID, req.Title, req.Description, req.Type, req.CreatedBy)
-	
+
 	// 添加选项
 	for _, optionText := range req.Options {
 		vote.AddOption(optionText, "")
 	}
+	vote.Settings = req.Settings
+	if err := vote.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	vote.ApplyDefaults()
+	switch req.Status {
+	case "", models.VoteOpen:
+	case models.VoteDraft:
+		vote.Status = models.VoteDraft
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A new vote must be draft or open"})
+		return
+	}
 
-	votes[vote.ID] = vote
-	
-	// 添加到房间投票列表
-	if roomVotes[roomID] == nil {
-		roomVotes[roomID] = make([]string, 0)
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	if err := tx.VoteSessions().Create(ctx, vote); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create vote"})
+		return
+	}
+	if err := recordVoteEvent(ctx, tx, vote, req.CreatedBy, models.AuditVoteCreated, map[string]interface{}{
+		"type": vote.Type, "status": vote.Status, "settings": vote.Settings,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record audit event"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save vote"})
+		return
 	}
-	roomVotes[roomID] = append(roomVotes[roomID], vote.ID)
 
-	c.JSON(http.StatusCreated, vote)
+	broadcastVote(vote, "created", "")
+	c.JSON(http.StatusCreated, vote.PublicView(""))
 }
 
-// GetVotes 获取房间的所有投票
+// GetVotes 获取房间的所有投票，?user_id= 为请求者
 func GetVotes(c *gin.Context) {
 	roomID := c.Param("id")
-	
-	voteIDs, exists := roomVotes[roomID]
-	if !exists {
-		c.JSON(http.StatusOK, []models.Vote{})
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
 		return
 	}
 
-	roomVoteList := make([]*models.Vote, 0)
-	for _, voteID := range voteIDs {
-		if vote, exists := votes[voteID]; exists {
-			roomVoteList = append(roomVoteList, vote)
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	roomVoteList, err := db.VoteSessions().GetByRoom(ctx, roomID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get votes"})
+		return
 	}
 
-	c.JSON(http.StatusOK, roomVoteList)
+	// 公布结果前只返回请求者自己的选票，匿名投票隐藏其他人的身份
+	views := make([]*models.Vote, 0, len(roomVoteList))
+	for _, vote := range roomVoteList {
+		views = append(views, vote.PublicView(c.Query("user_id")))
+	}
+	c.JSON(http.StatusOK, views)
+}
+
+// BallotRequest 提交选票的请求。option_ids 或 dots 为用户的完整选票，替换之前的选票；
+// 只提供 option_id 时为单个选项投票，合并到用户当前的选票
+type BallotRequest struct {
+	OptionID  string            `json:"option_id"`
+	OptionIDs []string          `json:"option_ids"` // 排序投票中按偏好从高到低
+	Dots      map[string]int    `json:"dots"`       // 圆点投票中每个选项的点数
+	Comments  map[string]string `json:"comments"`
+	UserID    string            `json:"user_id" binding:"required"`
+	UserName  string            `json:"user_name" binding:"required"`
+	Weight    int               `json:"weight"` // 只提供 option_id 时，圆点投票中投给该选项的点数
+	Comment   string            `json:"comment"`
 }
 
-// UpdateVote 更新投票（添加用户投票）
+// UpdateVote 更新投票（提交用户的选票）
 func UpdateVote(c *gin.Context) {
 	voteID := c.Param("id")
-	
-	vote, exists := votes[voteID]
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Vote not found"})
+
+	var req BallotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fullBallot := len(req.OptionIDs) > 0 || len(req.Dots) > 0
+	if req.OptionID == "" && !fullBallot {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "option_id, option_ids or dots is required"})
+		return
+	}
+
+	// 默认权重为1
+	if req.Weight == 0 {
+		req.Weight = 1
+	}
+
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 在事务中读取-修改-写入，避免并发投票互相覆盖
+	tx, err := db.BeginTx(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	vote, ok := getVote(c, ctx, tx.VoteSessions(), voteID)
+	if !ok {
+		return
+	}
+
+	if fullBallot {
+		err = vote.CastBallot(models.Ballot{
+			UserID:    req.UserID,
+			UserName:  req.UserName,
+			OptionIDs: req.OptionIDs,
+			Dots:      req.Dots,
+			Comments:  req.Comments,
+		})
+	} else {
+		err = vote.AddUserVote(req.OptionID, req.UserID, req.UserName, req.Weight, req.Comment)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.VoteSessions().Update(ctx, vote); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vote"})
 		return
 	}
 
+	ballot := vote.BallotOf(req.UserID)
+	if err := recordVoteEvent(ctx, tx, vote, req.UserID, models.AuditBallotCast, map[string]interface{}{
+		"user_name": req.UserName, "option_ids": ballot.OptionIDs, "dots": ballot.Dots,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record audit event"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save vote"})
+		return
+	}
+
+	broadcastVote(vote, "ballot_cast", req.UserID)
+	c.JSON(http.StatusOK, vote.PublicView(req.UserID))
+}
+
+// UpdateVoteStatus 主持人推进投票状态：draft → open → closed → revealed；closed 可以重新 open
+func UpdateVoteStatus(c *gin.Context) {
 	var req struct {
-		OptionID string `json:"option_id" binding:"required"`
-		UserID   string `json:"user_id" binding:"required"`
-		UserName string `json:"user_name" binding:"required"`
-		Weight   int    `json:"weight"`
-		Comment  string `json:"comment"`
+		Status string `json:"status" binding:"required"`
+		UserID string `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
+	var from string
+	editVote(c, req.UserID, func(vote *models.Vote, room *models.Room) (int, error) {
+		// 只有主持人（房间创建者）可以控制投票进程
+		if room.CreatedBy != req.UserID {
+			return http.StatusForbidden, fmt.Errorf("Only the facilitator can change the vote status")
+		}
+		from = vote.Status
+		if err := vote.Transition(req.Status, time.Now()); err != nil {
+			return http.StatusConflict, err
+		}
+		return 0, nil
+	}, func() (string, string, map[string]interface{}) {
+		return models.AuditVoteStatus, "status_changed", map[string]interface{}{"from": from, "to": req.Status}
+	})
+}
+
+// DecideVote 决策者在公布结果后拍板（超级投票），记录最终决定与理由
+func DecideVote(c *gin.Context) {
+	var req struct {
+		OptionID  string `json:"option_id" binding:"required"`
+		Rationale string `json:"rationale"`
+		UserID    string `json:"user_id" binding:"required"`
+	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 默认权重为1
-	if req.Weight == 0 {
-		req.Weight = 1
+	var decision *models.VoteDecision
+	editVote(c, req.UserID, func(vote *models.Vote, room *models.Room) (int, error) {
+		if vote.Decider(room.CreatedBy) != req.UserID {
+			return http.StatusForbidden, fmt.Errorf("Only the decider can record the decision")
+		}
+		if vote.Status != models.VoteRevealed {
+			return http.StatusConflict, fmt.Errorf("vote must be revealed before the decision, current status: %s", vote.Status)
+		}
+		if err := vote.Decide(req.OptionID, req.UserID, req.Rationale, time.Now()); err != nil {
+			return http.StatusBadRequest, err
+		}
+		decision = vote.Decision
+		return 0, nil
+	}, func() (string, string, map[string]interface{}) {
+		return models.AuditVoteDecided, "decided", map[string]interface{}{
+			"option_id": decision.OptionID, "rationale": decision.Rationale,
+			"winner": decision.Winner, "overrides": decision.Overrides,
+		}
+	})
+}
+
+// editVote 在事务中修改投票并写入审计记录，然后通知房间。edit 出错时返回状态码与错误；
+// event 返回审计动作、广播动作与审计详情
+func editVote(c *gin.Context, userID string, edit func(vote *models.Vote, room *models.Room) (int, error), event func() (string, string, map[string]interface{})) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
+		return
 	}
 
-	err := vote.AddUserVote(req.OptionID, req.UserID, req.UserName, req.Weight, req.Comment)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		return
+	}
+	defer tx.Rollback()
+
+	vote, ok := getVote(c, ctx, tx.VoteSessions(), c.Param("id"))
+	if !ok {
+		return
+	}
+	room, err := tx.Rooms().Get(ctx, vote.RoomID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Room not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, vote)
-}
\ No newline at end of file
+	if status, err := edit(vote, room); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.VoteSessions().Update(ctx, vote); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vote"})
+		return
+	}
+
+	auditAction, action, details := event()
+	if err := recordVoteEvent(ctx, tx, vote, userID, auditAction, details); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record audit event"})
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save vote"})
+		return
+	}
+
+	broadcastVote(vote, action, userID)
+	c.JSON(http.StatusOK, vote.PublicView(userID))
+}
+
+// GetVoteResults 按投票类型计票，公布结果前不可查看
+func GetVoteResults(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	vote, ok := getVote(c, ctx, db.VoteSessions(), c.Param("id"))
+	if !ok {
+		return
+	}
+	if !vote.ResultsVisible() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Results are hidden until the vote is revealed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, vote.Tally())
+}
+
+// GetVoteAudit 主持人审计投票：完整的选票（包括匿名投票的投票人）、计票结果与审计记录
+func GetVoteAudit(c *gin.Context) {
+	db, err := database.GetDatabase()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get database"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	vote, ok := getVote(c, ctx, db.VoteSessions(), c.Param("id"))
+	if !ok {
+		return
+	}
+	if !requireFacilitator(c, ctx, db, vote.RoomID) {
+		return
+	}
+
+	events, err := db.Audit().GetByRoom(ctx, vote.RoomID, models.AuditTargetVote, vote.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get audit events"})
+		return
+	}
+
+	vote.Ballots = vote.BallotCount()
+	c.JSON(http.StatusOK, gin.H{"vote": vote, "results": vote.Tally(), "events": events})
+}
+
+// getVote 读取投票，不存在时返回 404
+func getVote(c *gin.Context, ctx context.Context, votes database.VoteSessionRepository, voteID string) (*models.Vote, bool) {
+	vote, err := votes.Get(ctx, voteID)
+	if err != nil {
+		if err.Error() == "not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Vote not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get vote"})
+		}
+		return nil, false
+	}
+	return vote, true
+}
+
+// recordVoteEvent 写入投票的审计记录
+func recordVoteEvent(ctx context.Context, tx database.Transaction, vote *models.Vote, actor, action string, details map[string]interface{}) error {
+	return tx.Audit().Create(ctx, models.NewAuditEvent(vote.RoomID, actor, action, models.AuditTargetVote, vote.ID, details))
+}
+
+// broadcastVote 通知房间投票的变化：公布结果前不包含任何人的选票，匿名投票不包含操作者
+func broadcastVote(vote *models.Vote, action, userID string) {
+	data := gin.H{"action": action, "vote": vote.PublicView("")}
+	if userID != "" && !vote.Settings.Anonymous {
+		data["userId"] = userID
+	}
+	if vote.ResultsVisible() {
+		data["results"] = vote.Tally()
+	}
+	BroadcastToRoom(vote.RoomID, "vote_updated", data)
+}
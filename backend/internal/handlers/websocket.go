@@ -78,7 +78,8 @@ func (h *Hub) run() {
 					"userName": conn.userName,
 				},
 			}
-			h.broadcast <- joinMsg
+			// run 是 broadcast 的唯一接收方，这里直接投递，否则会阻塞自身
+			h.deliver(joinMsg)
 			
 			// 发送当前房间所有用户列表给新加入的用户
 			var users []map[string]interface{}
@@ -115,20 +116,25 @@ func (h *Hub) run() {
 							"userName": conn.userName,
 						},
 					}
-					h.broadcast <- leaveMsg
+					h.deliver(leaveMsg)
 				}
 			}
 			
 		case message := <-h.broadcast:
-			if connections, exists := h.rooms[message.RoomID]; exists {
-				for conn := range connections {
-					err := conn.ws.WriteJSON(message)
-					if err != nil {
-						log.Printf("WebSocket write error: %v", err)
-						conn.ws.Close()
-						delete(connections, conn)
-					}
-				}
+			h.deliver(message)
+		}
+	}
+}
+
+// deliver 将消息写给房间内的所有连接，写入失败的连接会被移除
+func (h *Hub) deliver(message *Message) {
+	if connections, exists := h.rooms[message.RoomID]; exists {
+		for conn := range connections {
+			err := conn.ws.WriteJSON(message)
+			if err != nil {
+				log.Printf("WebSocket write error: %v", err)
+				conn.ws.Close()
+				delete(connections, conn)
 			}
 		}
 	}
@@ -0,0 +1,109 @@
+package knowledge
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// Similarity methods
+const (
+	SimilarityEmbedding = "embedding" // cosine similarity of embeddings
+	SimilarityText      = "text"      // local word and character-bigram overlap
+)
+
+// Similarities returns the pairwise similarity (0-1) of texts and the method used. Texts are compared
+// by embedding when an embedder is configured, falling back to local text similarity when there is
+// none or the embedding call fails
+func (s *Store) Similarities(ctx context.Context, texts []string) ([][]float64, string) {
+	if s.embedder != nil && len(texts) > 1 {
+		vectors, err := s.embedder.Embed(ctx, texts)
+		if err == nil {
+			return pairwise(len(texts), func(i, j int) float64 {
+				// Clamp so opposite vectors do not produce negative similarities
+				if similarity := cosine(vectors[i], vectors[j]); similarity > 0 {
+					return similarity
+				}
+				return 0
+			}), SimilarityEmbedding
+		}
+		log.Printf("Embedding texts failed, using text similarity: %v", err)
+	}
+	return TextSimilarities(texts), SimilarityText
+}
+
+// TextSimilarities returns the pairwise local text similarity of texts
+func TextSimilarities(texts []string) [][]float64 {
+	words := make([]map[string]bool, len(texts))
+	bigrams := make([]map[string]bool, len(texts))
+	for i, text := range texts {
+		words[i] = termSet(tokenize(text))
+		bigrams[i] = termSet(characterBigrams(text))
+	}
+	return pairwise(len(texts), func(i, j int) float64 {
+		// Word overlap catches reordered statements, character bigrams catch typos and inflections
+		byWords := dice(words[i], words[j])
+		if byBigrams := dice(bigrams[i], bigrams[j]); byBigrams > byWords {
+			return byBigrams
+		}
+		return byWords
+	})
+}
+
+// pairwise builds a symmetric matrix with ones on the diagonal
+func pairwise(n int, similarity func(i, j int) float64) [][]float64 {
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+		matrix[i][i] = 1
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			matrix[i][j] = similarity(i, j)
+			matrix[j][i] = matrix[i][j]
+		}
+	}
+	return matrix
+}
+
+// characterBigrams returns the bigrams of the lowercased letters and digits in text, ignoring
+// spaces and punctuation
+func characterBigrams(text string) []string {
+	var runes []rune
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	if len(runes) == 1 {
+		return []string{string(runes)}
+	}
+	bigrams := make([]string, 0, len(runes))
+	for i := 1; i < len(runes); i++ {
+		bigrams = append(bigrams, string(runes[i-1:i+1]))
+	}
+	return bigrams
+}
+
+func termSet(terms []string) map[string]bool {
+	set := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		set[term] = true
+	}
+	return set
+}
+
+// dice returns the Dice coefficient of two sets
+func dice(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for term := range a {
+		if b[term] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
@@ -0,0 +1,455 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Search modes reported with results
+const (
+	ModeKeyword = "keyword" // BM25 over chunk text
+	ModeHybrid  = "hybrid"  // BM25 and embedding similarity, fused by rank
+)
+
+// rrfK dampens rank fusion so a single top rank does not dominate
+const rrfK = 60
+
+// maxSnippetChars bounds the chunk text returned with a hit
+const maxSnippetChars = 500
+
+// embedBatchSize is the number of chunks sent per embedding request
+const embedBatchSize = 64
+
+// ProgressFunc reports how many chunks of a document have been embedded
+type ProgressFunc func(done, total int)
+
+var (
+	defaultStore *Store
+	storeOnce    sync.Once
+	storeErr     error
+)
+
+// GetStore returns the knowledge store backed by the shared database
+func GetStore() (*Store, error) {
+	storeOnce.Do(func() {
+		db, err := database.GetDatabase()
+		if err != nil {
+			storeErr = err
+			return
+		}
+		embedder, err := NewEmbedderFromEnv()
+		if err != nil {
+			storeErr = err
+			return
+		}
+		defaultStore = NewStore(db, embedder)
+	})
+	return defaultStore, storeErr
+}
+
+// Store indexes documents into chunks and searches them
+type Store struct {
+	db       database.Database
+	embedder Embedder
+}
+
+// NewStore creates a store; embedder may be nil for keyword-only search
+func NewStore(db database.Database, embedder Embedder) *Store {
+	return &Store{db: db, embedder: embedder}
+}
+
+// Mode returns the search mode the store uses
+func (s *Store) Mode() string {
+	if s.embedder == nil {
+		return ModeKeyword
+	}
+	return ModeHybrid
+}
+
+// IndexRoom indexes a room's foundation, differentiation and approach, replacing an earlier index of it
+func (s *Store) IndexRoom(ctx context.Context, room *models.Room) (*models.KnowledgeDocument, error) {
+	document := &models.KnowledgeDocument{
+		ID:         uuid.New().String(),
+		SourceType: models.KnowledgeSourceRoom,
+		SourceID:   room.ID,
+		RoomID:     room.ID,
+		CreatedBy:  room.CreatedBy,
+		CreatedAt:  time.Now(),
+	}
+	if existing, err := s.db.Knowledge().GetBySource(ctx, models.KnowledgeSourceRoom, room.ID); err == nil {
+		document.ID = existing.ID
+		document.CreatedAt = existing.CreatedAt
+	}
+
+	document.Title = room.Name
+	document.Content = roomContent(room)
+	return document, s.save(ctx, document, nil)
+}
+
+// IndexAttachment indexes the extracted text of a room attachment, replacing an earlier index of it
+func (s *Store) IndexAttachment(ctx context.Context, attachment *models.Attachment, content string, progress ProgressFunc) (*models.KnowledgeDocument, error) {
+	document := &models.KnowledgeDocument{
+		ID:         uuid.New().String(),
+		SourceType: models.KnowledgeSourceAttachment,
+		SourceID:   attachment.ID,
+		RoomID:     attachment.RoomID,
+		Title:      attachment.Filename,
+		Content:    content,
+		CreatedBy:  attachment.UploadedBy,
+		CreatedAt:  time.Now(),
+	}
+	if existing, err := s.db.Knowledge().GetBySource(ctx, models.KnowledgeSourceAttachment, attachment.ID); err == nil {
+		document.ID = existing.ID
+		document.CreatedAt = existing.CreatedAt
+	}
+
+	return document, s.save(ctx, document, progress)
+}
+
+// DeleteSource removes the document indexed from a source, if any
+func (s *Store) DeleteSource(ctx context.Context, sourceType, sourceID string) error {
+	document, err := s.db.Knowledge().GetBySource(ctx, sourceType, sourceID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil
+		}
+		return err
+	}
+	return s.db.Knowledge().DeleteDocument(ctx, document.ID)
+}
+
+// AddDocument indexes an uploaded document
+func (s *Store) AddDocument(ctx context.Context, title, content, roomID, createdBy string) (*models.KnowledgeDocument, error) {
+	if strings.TrimSpace(title) == "" || strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("title and content are required")
+	}
+
+	now := time.Now()
+	document := &models.KnowledgeDocument{
+		ID:         uuid.New().String(),
+		SourceType: models.KnowledgeSourceDocument,
+		RoomID:     roomID,
+		Title:      strings.TrimSpace(title),
+		Content:    content,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+	}
+	return document, s.save(ctx, document, nil)
+}
+
+// Reindex rebuilds the chunks and embeddings of every document, e.g. after changing the embedding model
+func (s *Store) Reindex(ctx context.Context) (int, error) {
+	documents, err := s.db.Knowledge().ListDocuments(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, summary := range documents {
+		document, err := s.db.Knowledge().GetDocument(ctx, summary.ID)
+		if err != nil {
+			return count, err
+		}
+		if err := s.save(ctx, document, nil); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// save chunks and embeds a document, then stores it. Embedding happens before the transaction
+// so a slow provider does not hold the database; when it fails the chunks are stored without
+// vectors and stay searchable by keyword.
+func (s *Store) save(ctx context.Context, document *models.KnowledgeDocument, progress ProgressFunc) error {
+	texts := splitChunks(document.Content)
+	chunks := make([]*models.KnowledgeChunk, len(texts))
+	for i, text := range texts {
+		chunks[i] = &models.KnowledgeChunk{
+			ID:         uuid.New().String(),
+			DocumentID: document.ID,
+			Ordinal:    i,
+			Text:       text,
+		}
+	}
+
+	if s.embedder != nil {
+		if err := s.embedChunks(ctx, document.Title, chunks, progress); err != nil {
+			log.Printf("Embedding %q failed, indexing for keyword search only: %v", document.Title, err)
+			for _, chunk := range chunks {
+				chunk.Embedding = nil
+				chunk.EmbeddingModel = ""
+			}
+		}
+	}
+
+	document.UpdatedAt = time.Now()
+
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Knowledge().SaveDocument(ctx, document, chunks); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// embedChunks embeds the chunks in batches, reporting progress after each batch
+func (s *Store) embedChunks(ctx context.Context, title string, chunks []*models.KnowledgeChunk, progress ProgressFunc) error {
+	for start := 0; start < len(chunks); start += embedBatchSize {
+		end := start + embedBatchSize
+		if end > len(chunks) {
+			end = len(chunks)
+		}
+
+		inputs := make([]string, 0, end-start)
+		for _, chunk := range chunks[start:end] {
+			inputs = append(inputs, title+"\n\n"+chunk.Text)
+		}
+		vectors, err := s.embedder.Embed(ctx, inputs)
+		if err != nil {
+			return err
+		}
+		for i, chunk := range chunks[start:end] {
+			chunk.Embedding = vectors[i]
+			chunk.EmbeddingModel = s.embedder.Name()
+		}
+
+		if progress != nil {
+			progress(end, len(chunks))
+		}
+	}
+	return nil
+}
+
+// SearchOptions narrows a search
+type SearchOptions struct {
+	Limit      int
+	SourceType string // models.KnowledgeSource*, empty for all
+	RoomID     string // only documents of this room, empty for all
+}
+
+// Hit is the best matching chunk of a document
+type Hit struct {
+	DocumentID string  `json:"document_id"`
+	SourceType string  `json:"source_type"`
+	SourceID   string  `json:"source_id,omitempty"`
+	RoomID     string  `json:"room_id,omitempty"`
+	Title      string  `json:"title"`
+	URI        string  `json:"uri"`
+	Snippet    string  `json:"snippet"`
+	Score      float64 `json:"score"`
+}
+
+// SearchResponse is the result of a search
+type SearchResponse struct {
+	Query   string `json:"query"`
+	Mode    string `json:"mode"`
+	Results []Hit  `json:"results"`
+}
+
+// Search ranks chunks by BM25 and, when an embedder is configured, by cosine similarity to the
+// query embedding. The two rankings are then fused by reciprocal rank, so scores are BM25 in
+// keyword mode and fused ranks in hybrid mode. Each document is returned once with its best chunk.
+func (s *Store) Search(ctx context.Context, query string, opts SearchOptions) (*SearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 5
+	}
+
+	documents, err := s.db.Knowledge().ListDocuments(ctx, opts.SourceType)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.KnowledgeDocument, len(documents))
+	for _, document := range documents {
+		if opts.RoomID == "" || document.RoomID == opts.RoomID {
+			byID[document.ID] = document
+		}
+	}
+
+	// Chunks are filtered in SQL; the document check only drops chunks saved after the listing above
+	roomChunks, err := s.db.Knowledge().ListChunks(ctx, opts.RoomID, opts.SourceType)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make([]*models.KnowledgeChunk, 0, len(roomChunks))
+	for _, chunk := range roomChunks {
+		if byID[chunk.DocumentID] != nil {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	response := &SearchResponse{Query: query, Mode: ModeKeyword, Results: make([]Hit, 0)}
+	if len(chunks) == 0 {
+		return response, nil
+	}
+
+	terms := make([][]string, len(chunks))
+	for i, chunk := range chunks {
+		terms[i] = tokenize(byID[chunk.DocumentID].Title + " " + chunk.Text)
+	}
+	scores := bm25Scores(tokenize(query), terms)
+
+	if s.embedder != nil {
+		if similarities, ok := s.vectorScores(ctx, query, chunks); ok {
+			fused := make([]float64, len(chunks))
+			addRanks(fused, scores)
+			addRanks(fused, similarities)
+			scores = fused
+			response.Mode = ModeHybrid
+		}
+	}
+
+	best := make(map[string]int)
+	for i, chunk := range chunks {
+		if scores[i] == 0 {
+			continue
+		}
+		if j, ok := best[chunk.DocumentID]; !ok || scores[i] > scores[j] {
+			best[chunk.DocumentID] = i
+		}
+	}
+
+	for documentID, i := range best {
+		document := byID[documentID]
+		response.Results = append(response.Results, Hit{
+			DocumentID: document.ID,
+			SourceType: document.SourceType,
+			SourceID:   document.SourceID,
+			RoomID:     document.RoomID,
+			Title:      document.Title,
+			URI:        documentURI(document),
+			Snippet:    truncate(chunks[i].Text, maxSnippetChars),
+			Score:      scores[i],
+		})
+	}
+	sort.Slice(response.Results, func(i, j int) bool {
+		return response.Results[i].Score > response.Results[j].Score
+	})
+	if len(response.Results) > opts.Limit {
+		response.Results = response.Results[:opts.Limit]
+	}
+
+	return response, nil
+}
+
+// vectorScores returns the query's similarity to each chunk embedded by the current model,
+// and false when the query cannot be embedded or no chunk has a comparable vector
+func (s *Store) vectorScores(ctx context.Context, query string, chunks []*models.KnowledgeChunk) ([]float64, bool) {
+	vectors, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		log.Printf("Embedding query failed, using keyword search: %v", err)
+		return nil, false
+	}
+
+	similarities := make([]float64, len(chunks))
+	found := false
+	for i, chunk := range chunks {
+		if chunk.EmbeddingModel != s.embedder.Name() {
+			continue
+		}
+		// Shift into (0, 2] so every embedded chunk gets a rank
+		similarities[i] = cosine(vectors[0], chunk.Embedding) + 1
+		found = true
+	}
+	return similarities, found
+}
+
+// addRanks adds the reciprocal rank of every positive score to the fused scores
+func addRanks(fused []float64, scores []float64) {
+	order := make([]int, 0, len(scores))
+	for i, score := range scores {
+		if score > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	for rank, i := range order {
+		fused[i] += 1.0 / float64(rrfK+rank+1)
+	}
+}
+
+// documentURI identifies where a hit came from, matching the MCP server's room resources
+func documentURI(document *models.KnowledgeDocument) string {
+	switch document.SourceType {
+	case models.KnowledgeSourceRoom:
+		return "sprint://rooms/" + document.SourceID
+	case models.KnowledgeSourceAttachment:
+		return "sprint://rooms/" + document.RoomID + "/attachments/" + document.SourceID
+	}
+	return "sprint://knowledge/documents/" + document.ID
+}
+
+func truncate(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+	return string(runes[:maxRunes]) + "…"
+}
+
+// roomContent renders the parts of a room worth recalling in later sprints
+func roomContent(room *models.Room) string {
+	var sections []string
+	addList := func(title string, items []string) {
+		if len(items) > 0 {
+			sections = append(sections, title+"：\n- "+strings.Join(items, "\n- "))
+		}
+	}
+
+	addList("目标客户", models.CardTexts(room.Foundation.Customers))
+	addList("核心问题", models.CardTexts(room.Foundation.Problems))
+	addList("竞争对手", models.CardTexts(room.Foundation.Competition))
+	addList("团队优势", models.CardTexts(room.Foundation.Advantages))
+	addList("核心原则", room.Differentiation.Principles)
+
+	var factors []string
+	for _, factor := range append(append([]models.DifferentiationFactor{}, room.Differentiation.ClassicFactors...),
+		room.Differentiation.CustomFactors...) {
+		factors = append(factors, describe(factor.Name, factor.Description))
+	}
+	addList("差异化因素", factors)
+
+	var paths []string
+	selected := ""
+	for _, path := range room.Approach.Paths {
+		paths = append(paths, describe(path.Name, path.Description))
+		if path.ID == room.Approach.SelectedPath {
+			selected = describe(path.Name, path.Description)
+		}
+	}
+	addList("候选路径", paths)
+	if selected != "" {
+		sections = append(sections, "选定方案："+selected)
+	}
+	if reasoning := strings.TrimSpace(room.Approach.Reasoning); reasoning != "" {
+		sections = append(sections, "决策理由："+reasoning)
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+func describe(name, description string) string {
+	if description == "" {
+		return name
+	}
+	return name + "：" + description
+}
@@ -0,0 +1,161 @@
+package knowledge
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxChunkChars is the target chunk size; paragraphs are kept whole when they fit
+const maxChunkChars = 800
+
+// splitChunks splits text into chunks of whole paragraphs, breaking long paragraphs by runes
+func splitChunks(text string) []string {
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if chunk := strings.TrimSpace(current.String()); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current.Reset()
+	}
+
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(current.String())+utf8.RuneCountInString(paragraph) > maxChunkChars {
+			flush()
+		}
+
+		for utf8.RuneCountInString(paragraph) > maxChunkChars {
+			runes := []rune(paragraph)
+			current.WriteString(string(runes[:maxChunkChars]))
+			flush()
+			paragraph = string(runes[maxChunkChars:])
+		}
+
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(paragraph)
+	}
+	flush()
+
+	return chunks
+}
+
+// tokenize lowercases text into terms: words for alphabetic scripts, and single characters
+// plus bigrams for CJK text, which has no spaces between words
+func tokenize(text string) []string {
+	var terms []string
+	var word []rune
+	var previousCJK rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			terms = append(terms, string(word))
+			word = word[:0]
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flushWord()
+			terms = append(terms, string(r))
+			if previousCJK != 0 {
+				terms = append(terms, string([]rune{previousCJK, r}))
+			}
+			previousCJK = r
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flushWord()
+		}
+		previousCJK = 0
+	}
+	flushWord()
+
+	return terms
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// bm25Scores ranks documents, given as term lists, against the query terms
+func bm25Scores(query []string, documents [][]string) []float64 {
+	scores := make([]float64, len(documents))
+	if len(documents) == 0 || len(query) == 0 {
+		return scores
+	}
+
+	frequencies := make([]map[string]int, len(documents))
+	documentFrequency := make(map[string]int)
+	totalLength := 0
+	for i, terms := range documents {
+		counts := make(map[string]int, len(terms))
+		for _, term := range terms {
+			counts[term]++
+		}
+		for term := range counts {
+			documentFrequency[term]++
+		}
+		frequencies[i] = counts
+		totalLength += len(terms)
+	}
+	averageLength := float64(totalLength) / float64(len(documents))
+
+	queryTerms := make(map[string]bool, len(query))
+	for _, term := range query {
+		queryTerms[term] = true
+	}
+
+	n := float64(len(documents))
+	for term := range queryTerms {
+		df := float64(documentFrequency[term])
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for i, counts := range frequencies {
+			tf := float64(counts[term])
+			if tf == 0 {
+				continue
+			}
+			length := float64(len(documents[i]))
+			scores[i] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*length/averageLength))
+		}
+	}
+	return scores
+}
+
+// cosine returns the cosine similarity of two vectors of equal length
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,347 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+	"foundation-sprint/internal/report"
+	"strings"
+	"time"
+)
+
+// ErrIdentityRequired is returned by tools that change a room when the caller is anonymous
+var ErrIdentityRequired = fmt.Errorf("identity required: set MCP_USER_ID or send X-User-ID")
+
+// roomTool is a tool backed by the sprint repositories
+type roomTool struct {
+	name        string
+	description string
+	schema      map[string]interface{}
+	handler     func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error)
+}
+
+// objectSchema builds a JSON Schema for an object with string properties
+func objectSchema(required []string, properties map[string]string) map[string]interface{} {
+	props := make(map[string]interface{}, len(properties))
+	for name, description := range properties {
+		props[name] = map[string]interface{}{"type": "string", "description": description}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+		"required":   required,
+	}
+}
+
+var roomTools = []roomTool{
+	{
+		name:        "sprint_list_rooms",
+		description: "List Foundation Sprint rooms with their id, name and current phase.",
+		schema:      objectSchema([]string{}, map[string]string{}),
+		handler: func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error) {
+			rooms, err := s.db.Rooms().List(ctx, 0, 100)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list rooms: %w", err)
+			}
+			summaries := make([]map[string]interface{}, 0, len(rooms))
+			for _, room := range rooms {
+				summaries = append(summaries, roomSummary(room))
+			}
+			return map[string]interface{}{"rooms": summaries}, nil
+		},
+	},
+	{
+		name:        "sprint_get_room",
+		description: "Get a room with its foundation, differentiation and approach.",
+		schema:      objectSchema([]string{"room_id"}, map[string]string{"room_id": "Room ID"}),
+		handler: func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error) {
+			return s.getRoom(ctx, stringArg(args, "room_id"))
+		},
+	},
+	{
+		name:        "sprint_get_foundation",
+		description: "Get a room's foundation: target customers, problems, competition and advantages.",
+		schema:      objectSchema([]string{"room_id"}, map[string]string{"room_id": "Room ID"}),
+		handler: func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error) {
+			room, err := s.getRoom(ctx, stringArg(args, "room_id"))
+			if err != nil {
+				return nil, err
+			}
+			return room.Foundation, nil
+		},
+	},
+	{
+		name:        "sprint_list_votes",
+		description: "List a room's votes with options; ballots and results are included once a vote is revealed.",
+		schema:      objectSchema([]string{"room_id"}, map[string]string{"room_id": "Room ID"}),
+		handler: func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error) {
+			return s.listVotes(ctx, stringArg(args, "room_id"))
+		},
+	},
+	{
+		name:        "sprint_get_report",
+		description: "Get a room's final report as Markdown.",
+		schema:      objectSchema([]string{"room_id"}, map[string]string{"room_id": "Room ID"}),
+		handler: func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error) {
+			return s.roomReport(ctx, stringArg(args, "room_id"))
+		},
+	},
+	{
+		name: "sprint_add_problem",
+		description: "Add a core problem to a room's foundation. Applied directly for the facilitator, " +
+			"otherwise submitted as a proposal for the facilitator to review.",
+		schema: objectSchema([]string{"room_id", "text"}, map[string]string{
+			"room_id":   "Room ID",
+			"text":      "Problem statement",
+			"rationale": "Why this problem matters",
+		}),
+		handler: func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error) {
+			return s.applyOrPropose(ctx, userID, stringArg(args, "room_id"), models.ProposalAddProblem, args)
+		},
+	},
+	{
+		name: "sprint_add_customer",
+		description: "Add a target customer to a room's foundation. Applied directly for the facilitator, " +
+			"otherwise submitted as a proposal for the facilitator to review.",
+		schema: objectSchema([]string{"room_id", "text"}, map[string]string{
+			"room_id":   "Room ID",
+			"text":      "Customer segment",
+			"rationale": "Why this customer matters",
+		}),
+		handler: func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error) {
+			return s.applyOrPropose(ctx, userID, stringArg(args, "room_id"), models.ProposalAddCustomer, args)
+		},
+	},
+	{
+		name:        "sprint_list_proposals",
+		description: "List a room's proposals, optionally filtered by status (pending, accepted, rejected).",
+		schema: objectSchema([]string{"room_id"}, map[string]string{
+			"room_id": "Room ID",
+			"status":  "Optional status filter",
+		}),
+		handler: func(ctx context.Context, s *Server, userID string, args map[string]interface{}) (interface{}, error) {
+			roomID := stringArg(args, "room_id")
+			if _, err := s.getRoom(ctx, roomID); err != nil {
+				return nil, err
+			}
+			proposals, err := s.db.Proposals().GetByRoom(ctx, roomID, stringArg(args, "status"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list proposals: %w", err)
+			}
+			return map[string]interface{}{"proposals": proposals}, nil
+		},
+	},
+}
+
+var roomToolsByName = func() map[string]roomTool {
+	byName := make(map[string]roomTool, len(roomTools))
+	for _, tool := range roomTools {
+		byName[tool.name] = tool
+	}
+	return byName
+}()
+
+func stringArg(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return strings.TrimSpace(value)
+}
+
+func roomSummary(room *models.Room) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         room.ID,
+		"name":       room.Name,
+		"status":     room.Status,
+		"created_by": room.CreatedBy,
+		"updated_at": room.UpdatedAt,
+	}
+}
+
+func (s *Server) getRoom(ctx context.Context, roomID string) (*models.Room, error) {
+	if roomID == "" {
+		return nil, fmt.Errorf("room_id is required")
+	}
+	room, err := s.db.Rooms().Get(ctx, roomID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, fmt.Errorf("room %s not found", roomID)
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	return room, nil
+}
+
+func (s *Server) listVotes(ctx context.Context, roomID string) (interface{}, error) {
+	if _, err := s.getRoom(ctx, roomID); err != nil {
+		return nil, err
+	}
+	votes, err := s.db.VoteSessions().GetByRoom(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list votes: %w", err)
+	}
+
+	entries := make([]map[string]interface{}, 0, len(votes))
+	for _, vote := range votes {
+		// Like the web client, ballots and results stay hidden until the vote is revealed
+		entry := map[string]interface{}{"vote": vote.PublicView("")}
+		if vote.ResultsVisible() {
+			entry["results"] = vote.Tally()
+		}
+		entries = append(entries, entry)
+	}
+	return map[string]interface{}{"votes": entries}, nil
+}
+
+func (s *Server) roomReport(ctx context.Context, roomID string) (string, error) {
+	if _, err := s.getRoom(ctx, roomID); err != nil {
+		return "", err
+	}
+	sprint, err := report.Load(ctx, s.db, roomID)
+	if err != nil {
+		return "", err
+	}
+	return report.Markdown(sprint, time.Now())
+}
+
+// applyOrPropose applies a foundation change when the caller is the room's facilitator, like
+// accepting a proposal in the web UI; anyone else creates a pending proposal for review
+func (s *Server) applyOrPropose(ctx context.Context, userID, roomID, proposalType string, args map[string]interface{}) (interface{}, error) {
+	if userID == "" {
+		return nil, ErrIdentityRequired
+	}
+	if roomID == "" {
+		return nil, fmt.Errorf("room_id is required")
+	}
+
+	proposal := models.NewProposal(roomID, proposalType, "MCP")
+	proposal.RequestedBy = userID
+	proposal.Text = stringArg(args, "text")
+	proposal.Rationale = stringArg(args, "rationale")
+	if err := proposal.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	room, err := tx.Rooms().Get(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("room %s not found", roomID)
+	}
+
+	if err := tx.Proposals().Create(ctx, proposal); err != nil {
+		return nil, fmt.Errorf("failed to save proposal: %w", err)
+	}
+
+	// 只有主持人（房间创建者）可以直接修改房间
+	if room.CreatedBy != userID {
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to save proposal: %w", err)
+		}
+		s.notify(roomID, "proposals_created", []*models.Proposal{proposal})
+		return map[string]interface{}{"status": "proposed", "proposal": proposal}, nil
+	}
+
+	phase, err := proposal.ApplyTo(room)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.SaveRoomPhase(ctx, tx.Rooms(), room, phase); err != nil {
+		return nil, fmt.Errorf("failed to apply change: %w", err)
+	}
+	if err := tx.Proposals().UpdateStatus(ctx, proposal.ID, models.ProposalAccepted, userID); err != nil {
+		return nil, fmt.Errorf("failed to update proposal: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to save change: %w", err)
+	}
+
+	now := time.Now()
+	proposal.Status = models.ProposalAccepted
+	proposal.DecidedBy = userID
+	proposal.DecidedAt = &now
+
+	s.notify(roomID, phase+"_update", map[string]interface{}{
+		"userId":      userID,
+		"proposal_id": proposal.ID,
+	})
+	return map[string]interface{}{"status": "applied", "proposal": proposal, "foundation": room.Foundation}, nil
+}
+
+// resourceTemplates describe the per-room resources
+var resourceTemplates = []map[string]string{
+	{"uriTemplate": "sprint://rooms/{room_id}", "name": "room", "mimeType": "application/json",
+		"description": "A sprint room with all phases"},
+	{"uriTemplate": "sprint://rooms/{room_id}/foundation", "name": "foundation", "mimeType": "application/json",
+		"description": "A room's foundation"},
+	{"uriTemplate": "sprint://rooms/{room_id}/votes", "name": "votes", "mimeType": "application/json",
+		"description": "A room's votes and results"},
+	{"uriTemplate": "sprint://rooms/{room_id}/report", "name": "report", "mimeType": "text/markdown",
+		"description": "A room's final report"},
+}
+
+const roomURIPrefix = "sprint://rooms/"
+
+func (s *Server) listResources(ctx context.Context) (interface{}, error) {
+	rooms, err := s.db.Rooms().List(ctx, 0, 100)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rooms: %w", err)
+	}
+
+	resources := make([]map[string]string, 0, len(rooms))
+	for _, room := range rooms {
+		resources = append(resources, map[string]string{
+			"uri":      roomURIPrefix + room.ID,
+			"name":     room.Name,
+			"mimeType": "application/json",
+		})
+	}
+	return map[string]interface{}{"resources": resources}, nil
+}
+
+func (s *Server) readResource(ctx context.Context, uri string) (interface{}, error) {
+	if !strings.HasPrefix(uri, roomURIPrefix) {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown resource: " + uri}
+	}
+	roomID, view, _ := strings.Cut(strings.TrimPrefix(uri, roomURIPrefix), "/")
+
+	var value interface{}
+	var err error
+	switch view {
+	case "":
+		value, err = s.getRoom(ctx, roomID)
+	case "foundation":
+		var room *models.Room
+		if room, err = s.getRoom(ctx, roomID); err == nil {
+			value = room.Foundation
+		}
+	case "votes":
+		value, err = s.listVotes(ctx, roomID)
+	case "report":
+		var text string
+		if text, err = s.roomReport(ctx, roomID); err == nil {
+			return resourceContents(uri, "text/markdown", text), nil
+		}
+	default:
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown resource: " + uri}
+	}
+	if err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return resourceContents(uri, "application/json", string(data)), nil
+}
+
+func resourceContents(uri, mimeType, text string) map[string]interface{} {
+	return map[string]interface{}{
+		"contents": []map[string]string{{"uri": uri, "mimeType": mimeType, "text": text}},
+	}
+}
@@ -0,0 +1,262 @@
+// Package mcpserver exposes sprint rooms and agent tools to MCP clients such as desktop assistants.
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"foundation-sprint/internal/agents/tools"
+	"foundation-sprint/internal/database"
+	"sort"
+)
+
+// ProtocolVersion is the MCP revision this server speaks
+const ProtocolVersion = "2025-03-26"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Notifier tells web clients about changes, e.g. handlers.BroadcastToRoom; nil when running standalone
+type Notifier func(roomID, msgType string, data interface{})
+
+// Server answers MCP requests using the sprint repositories and the tools registry
+type Server struct {
+	db       database.Database
+	registry *tools.Registry
+	notify   Notifier
+}
+
+// New creates a server
+func New(db database.Database, registry *tools.Registry, notify Notifier) *Server {
+	if notify == nil {
+		notify = func(string, string, interface{}) {}
+	}
+	return &Server{db: db, registry: registry, notify: notify}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// Handle processes one JSON-RPC message on behalf of userID and returns the encoded response,
+// or nil for notifications
+func (s *Server) Handle(ctx context.Context, userID string, data []byte) []byte {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return encode(response{JSONRPC: "2.0", ID: json.RawMessage("null"),
+			Error: &rpcError{Code: codeParseError, Message: "invalid JSON-RPC message"}})
+	}
+	if len(req.ID) == 0 {
+		// Notifications need no answer
+		return nil
+	}
+
+	result, err := s.dispatch(ctx, userID, req)
+	resp := response{JSONRPC: "2.0", ID: req.ID, Result: result}
+	if err != nil {
+		rpcErr, ok := err.(*rpcError)
+		if !ok {
+			rpcErr = &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		resp.Result = nil
+		resp.Error = rpcErr
+	}
+	return encode(resp)
+}
+
+func encode(resp response) []byte {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(response{JSONRPC: "2.0", ID: resp.ID,
+			Error: &rpcError{Code: codeInternalError, Message: "failed to encode result"}})
+	}
+	return data
+}
+
+func (s *Server) dispatch(ctx context.Context, userID string, req request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]interface{}{
+				"tools":     map[string]interface{}{},
+				"resources": map[string]interface{}{},
+			},
+			"serverInfo": map[string]string{
+				"name":    "foundation-sprint",
+				"version": "1.0.0",
+			},
+			"instructions": "Read and contribute to Foundation Sprint rooms. Changes by anyone but the room's facilitator become proposals the facilitator reviews.",
+		}, nil
+
+	case "ping":
+		return map[string]interface{}{}, nil
+
+	case "tools/list":
+		return map[string]interface{}{"tools": s.listTools()}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/call params"}
+		}
+		return s.callTool(ctx, userID, params.Name, params.Arguments)
+
+	case "resources/list":
+		return s.listResources(ctx)
+
+	case "resources/templates/list":
+		return map[string]interface{}{"resourceTemplates": resourceTemplates}, nil
+
+	case "resources/read":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid resources/read params"}
+		}
+		return s.readResource(ctx, params.URI)
+	}
+
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+// toolDescriptor is a tool as listed by tools/list
+type toolDescriptor struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// listTools returns the room tools followed by the registry tools, sorted by name
+func (s *Server) listTools() []toolDescriptor {
+	descriptors := make([]toolDescriptor, 0, len(roomTools))
+	for _, tool := range roomTools {
+		descriptors = append(descriptors, toolDescriptor{
+			Name:        tool.name,
+			Description: tool.description,
+			InputSchema: tool.schema,
+		})
+	}
+
+	registryTools := s.registry.List()
+	sort.Slice(registryTools, func(i, j int) bool {
+		return registryTools[i].GetName() < registryTools[j].GetName()
+	})
+	for _, tool := range registryTools {
+		descriptors = append(descriptors, toolDescriptor{
+			Name:        tool.GetName(),
+			Description: tool.GetDescription(),
+			InputSchema: toolSchema(tool),
+		})
+	}
+	return descriptors
+}
+
+// toolSchema returns a tool's JSON Schema, deriving one from its input fields when it has none
+func toolSchema(tool tools.Tool) map[string]interface{} {
+	if provider, ok := tool.(tools.SchemaProvider); ok {
+		return provider.InputSchema()
+	}
+
+	properties := map[string]interface{}{}
+	required := []string{}
+	if fields, ok := tool.(interface {
+		InputFields() (required, optional []string)
+	}); ok {
+		requiredFields, optionalFields := fields.InputFields()
+		for _, name := range requiredFields {
+			properties[name] = map[string]interface{}{}
+			required = append(required, name)
+		}
+		for _, name := range optionalFields {
+			properties[name] = map[string]interface{}{}
+		}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// callTool runs a room tool or a registry tool. Tool failures are reported in the result
+// with isError so the assistant can read them; unknown tools are protocol errors.
+func (s *Server) callTool(ctx context.Context, userID, name string, arguments map[string]interface{}) (interface{}, error) {
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+
+	var result interface{}
+	var err error
+	if tool, ok := roomToolsByName[name]; ok {
+		result, err = tool.handler(ctx, s, userID, arguments)
+	} else if tool, ok := s.registry.Get(name); ok {
+		if err = tool.ValidateInput(arguments); err == nil {
+			result, err = tool.Execute(ctx, arguments)
+		}
+	} else {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + name}
+	}
+
+	if err != nil {
+		return toolResult(err.Error(), nil, true), nil
+	}
+	return encodeToolResult(result), nil
+}
+
+// encodeToolResult returns the result as text, plus structured content when it is a JSON object
+func encodeToolResult(result interface{}) map[string]interface{} {
+	if text, ok := result.(string); ok {
+		return toolResult(text, nil, false)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolResult(fmt.Sprintf("%v", result), nil, false)
+	}
+
+	var structured map[string]interface{}
+	if json.Unmarshal(data, &structured) != nil {
+		structured = nil
+	}
+	return toolResult(string(data), structured, false)
+}
+
+func toolResult(text string, structured map[string]interface{}, isError bool) map[string]interface{} {
+	result := map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+	if structured != nil {
+		result["structuredContent"] = structured
+	}
+	return result
+}
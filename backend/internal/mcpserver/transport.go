@@ -0,0 +1,78 @@
+package mcpserver
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// maxMessageBytes bounds a single request
+const maxMessageBytes = 4 << 20
+
+// UserHeader carries the caller's user ID on HTTP requests
+const UserHeader = "X-User-ID"
+
+// DefaultUserID returns the identity used when a request carries none
+func DefaultUserID() string {
+	return strings.TrimSpace(os.Getenv("MCP_USER_ID"))
+}
+
+// ServeStdio reads newline-delimited messages from in and writes responses to out until in
+// is closed. Requests are handled one at a time, in order.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer, userID string) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+	writer := bufio.NewWriter(out)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		response := s.Handle(ctx, userID, []byte(line))
+		if response == nil {
+			continue
+		}
+		writer.Write(response)
+		writer.WriteByte('\n')
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// HTTPHandler serves the streamable HTTP transport. Every response is a single JSON body;
+// the server never opens an SSE stream, so GET is not allowed.
+func (s *Server) HTTPHandler(defaultUserID string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageBytes))
+		if err != nil {
+			http.Error(w, "failed to read request", http.StatusBadRequest)
+			return
+		}
+
+		userID := strings.TrimSpace(r.Header.Get(UserHeader))
+		if userID == "" {
+			userID = defaultUserID
+		}
+
+		response := s.Handle(r.Context(), userID, body)
+		if response == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(response)
+	})
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// 附件处理状态
+const (
+	AttachmentProcessing = "processing" // 已上传，正在提取文本并建立索引
+	AttachmentReady      = "ready"
+	AttachmentFailed     = "failed"
+)
+
+// Attachment 上传到房间的证据材料，如访谈记录、问卷导出、分析报告
+type Attachment struct {
+	ID          string     `json:"id"`
+	RoomID      string     `json:"room_id"`
+	CardID      string     `json:"card_id,omitempty"` // 关联的卡片，可为空
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	BlobKey     string     `json:"-"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	ChunkCount  int        `json:"chunk_count"`
+	UploadedBy  string     `json:"uploaded_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
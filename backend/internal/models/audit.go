@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 审计动作
+const (
+	AuditVoteCreated = "vote_created"
+	AuditVoteStatus  = "vote_status_changed"
+	AuditBallotCast  = "ballot_cast" // 记录投票人，匿名投票中只有审计可以看到
+	AuditVoteDecided = "vote_decided"
+	AuditPhaseChange = "phase_changed"
+	AuditPhaseForced = "phase_override" // 主持人在退出条件未满足时强制推进阶段
+	AuditItemsMerged = "duplicates_merged"
+)
+
+// 审计对象类型
+const (
+	AuditTargetVote = "vote"
+	AuditTargetRoom = "room"
+	AuditTargetCard = "card" // 基础信息卡片或差异化因素
+)
+
+// AuditEvent 房间审计日志中的一条记录，只追加不修改
+type AuditEvent struct {
+	ID         string                 `json:"id"`
+	RoomID     string                 `json:"room_id"`
+	Actor      string                 `json:"actor"` // 操作者的用户 ID
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type"`
+	TargetID   string                 `json:"target_id"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// NewAuditEvent 创建审计记录
+func NewAuditEvent(roomID, actor, action, targetType, targetID string, details map[string]interface{}) *AuditEvent {
+	return &AuditEvent{
+		ID:         uuid.New().String(),
+		RoomID:     roomID,
+		Actor:      actor,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+		CreatedAt:  time.Now(),
+	}
+}
@@ -0,0 +1,322 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 基础阶段卡片类型
+const (
+	CardCustomer    = "customer"
+	CardProblem     = "problem"
+	CardCompetition = "competition"
+	CardAdvantage   = "advantage"
+)
+
+// 问题影响的客户比例
+const (
+	AffectedFew  = "few"
+	AffectedSome = "some"
+	AffectedMany = "many"
+	AffectedMost = "most"
+)
+
+// 竞争对手类型
+const (
+	CompetitionDirect      = "direct"
+	CompetitionAlternative = "alternative"
+	CompetitionWorkaround  = "workaround" // 客户自己拼凑的变通做法
+)
+
+// 团队优势类别
+const (
+	AdvantageTechnical  = "technical"
+	AdvantageInsight    = "insight"
+	AdvantageResource   = "resource"
+	AdvantageMotivation = "motivation"
+)
+
+// 痛点强度范围
+const (
+	PainIntensityMin = 1
+	PainIntensityMax = 10
+)
+
+// Card 基础阶段的一张卡片：目标客户、核心问题、竞争对手或团队优势
+type Card struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Text        string    `json:"text"`
+	Description string    `json:"description,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Order       int       `json:"order"` // 在所属列表中的位置，从 0 开始
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// 问题卡片
+	PainIntensity int    `json:"pain_intensity,omitempty"` // 痛点强度 1-10
+	AffectedShare string `json:"affected_share,omitempty"` // 受影响客户比例：few/some/many/most
+	// 竞争对手卡片
+	Kind string `json:"kind,omitempty"` // direct/alternative/workaround
+	// 团队优势卡片
+	Category string `json:"category,omitempty"` // technical/insight/resource/motivation
+
+	Merged []MergedItem `json:"merged,omitempty"` // 合并到这张卡片的重复卡片
+
+	legacy bool // 以旧版字符串格式提交或保存
+}
+
+// NewCard 创建卡片
+func NewCard(cardType, text, author string) Card {
+	now := time.Now()
+	return Card{
+		ID:        uuid.New().String(),
+		Type:      cardType,
+		Text:      strings.TrimSpace(text),
+		Author:    author,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// UnmarshalJSON 兼容旧版本以字符串保存的卡片
+func (c *Card) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		var text string
+		if err := json.Unmarshal(trimmed, &text); err != nil {
+			return err
+		}
+		*c = Card{Text: text, legacy: true}
+		return nil
+	}
+
+	type plainCard Card
+	var card plainCard
+	if err := json.Unmarshal(data, &card); err != nil {
+		return err
+	}
+	*c = Card(card)
+	return nil
+}
+
+// Validate 检查卡片字段，类型专属的属性只能出现在对应类型的卡片上
+func (c *Card) Validate(cardType string) error {
+	c.Text = strings.TrimSpace(c.Text)
+	if c.Text == "" {
+		return fmt.Errorf("card text is required")
+	}
+	if c.Type == "" {
+		c.Type = cardType
+	}
+	if c.Type != cardType {
+		return fmt.Errorf("%s card cannot be stored as %s", c.Type, cardType)
+	}
+
+	if c.PainIntensity != 0 || c.AffectedShare != "" {
+		if cardType != CardProblem {
+			return fmt.Errorf("pain_intensity and affected_share only apply to problem cards")
+		}
+		if c.PainIntensity != 0 && (c.PainIntensity < PainIntensityMin || c.PainIntensity > PainIntensityMax) {
+			return fmt.Errorf("pain_intensity must be between %d and %d", PainIntensityMin, PainIntensityMax)
+		}
+		switch c.AffectedShare {
+		case "", AffectedFew, AffectedSome, AffectedMany, AffectedMost:
+		default:
+			return fmt.Errorf("invalid affected_share: %s", c.AffectedShare)
+		}
+	}
+	if c.Kind != "" {
+		if cardType != CardCompetition {
+			return fmt.Errorf("kind only applies to competition cards")
+		}
+		switch c.Kind {
+		case CompetitionDirect, CompetitionAlternative, CompetitionWorkaround:
+		default:
+			return fmt.Errorf("invalid competition kind: %s", c.Kind)
+		}
+	}
+	if c.Category != "" {
+		if cardType != CardAdvantage {
+			return fmt.Errorf("category only applies to advantage cards")
+		}
+		switch c.Category {
+		case AdvantageTechnical, AdvantageInsight, AdvantageResource, AdvantageMotivation:
+		default:
+			return fmt.Errorf("invalid advantage category: %s", c.Category)
+		}
+	}
+	return nil
+}
+
+// SameContent 两张卡片的文本、描述与属性是否相同
+func (c *Card) SameContent(other *Card) bool {
+	return c.Text == other.Text && c.Description == other.Description &&
+		c.PainIntensity == other.PainIntensity && c.AffectedShare == other.AffectedShare &&
+		c.Kind == other.Kind && c.Category == other.Category
+}
+
+// CardTexts 返回卡片文本，跳过空白卡片
+func CardTexts(cards []Card) []string {
+	texts := make([]string, 0, len(cards))
+	for _, card := range cards {
+		if text := strings.TrimSpace(card.Text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+// CardList 基础阶段中的一组卡片
+type CardList struct {
+	Type  string
+	Cards *[]Card
+}
+
+// Lists 按固定顺序返回四组卡片
+func (f *Foundation) Lists() []CardList {
+	return []CardList{
+		{Type: CardCustomer, Cards: &f.Customers},
+		{Type: CardProblem, Cards: &f.Problems},
+		{Type: CardCompetition, Cards: &f.Competition},
+		{Type: CardAdvantage, Cards: &f.Advantages},
+	}
+}
+
+// List 返回指定类型的卡片列表
+func (f *Foundation) List(cardType string) (*[]Card, bool) {
+	for _, list := range f.Lists() {
+		if list.Type == cardType {
+			return list.Cards, true
+		}
+	}
+	return nil, false
+}
+
+// FindCard 按 ID 查找卡片，返回所在列表与下标
+func (f *Foundation) FindCard(cardID string) (*[]Card, int, bool) {
+	for _, list := range f.Lists() {
+		for i := range *list.Cards {
+			if (*list.Cards)[i].ID == cardID {
+				return list.Cards, i, true
+			}
+		}
+	}
+	return nil, -1, false
+}
+
+// Validate 检查所有卡片
+func (f *Foundation) Validate() error {
+	for _, list := range f.Lists() {
+		for i := range *list.Cards {
+			if err := (*list.Cards)[i].Validate(list.Type); err != nil {
+				return fmt.Errorf("%s card %d: %w", list.Type, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Normalize 补全卡片的类型、ID 与时间，并按数组顺序重新编号；返回是否补全了缺失的字段。
+// 旧版字符串卡片的 ID 由房间、类型与文本确定，迁移前后读取得到的 ID 相同
+func (f *Foundation) Normalize(roomID string, now time.Time) bool {
+	changed := false
+	seen := make(map[string]bool)
+	for _, list := range f.Lists() {
+		if *list.Cards == nil {
+			*list.Cards = make([]Card, 0)
+		}
+		for i := range *list.Cards {
+			card := &(*list.Cards)[i]
+			if card.Type == "" {
+				card.Type = list.Type
+				changed = true
+			}
+			if card.ID == "" || seen[card.ID] {
+				card.ID = legacyCardID(roomID, list.Type, card.Text, i, seen)
+				changed = true
+			}
+			seen[card.ID] = true
+			if card.CreatedAt.IsZero() {
+				card.CreatedAt = now
+				changed = true
+			}
+			if card.UpdatedAt.IsZero() {
+				card.UpdatedAt = card.CreatedAt
+				changed = true
+			}
+			if card.Order != i {
+				card.Order = i
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// legacyCardID 为没有 ID 的卡片生成确定的 ID，文本重复时加入下标区分
+func legacyCardID(roomID, cardType, text string, index int, seen map[string]bool) string {
+	name := roomID + "/" + cardType + "/" + strings.ToLower(strings.TrimSpace(text))
+	id := uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+	if seen[id] {
+		id = uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("%s#%d", name, index))).String()
+	}
+	return id
+}
+
+// Reconcile 将提交的基础信息与当前数据对齐：卡片先按 ID、再按文本匹配已有卡片（旧客户端只提交文本），
+// 匹配的卡片沿用原有的 ID、作者与创建时间，内容变化时更新修改时间；以字符串提交的卡片保留原有属性。
+// 未匹配的卡片视为新卡片，作者记为 author
+func (f *Foundation) Reconcile(current *Foundation, author string, now time.Time) {
+	for _, list := range f.Lists() {
+		existing, _ := current.List(list.Type)
+		byID := make(map[string]*Card, len(*existing))
+		byText := make(map[string]*Card, len(*existing))
+		for i := range *existing {
+			card := &(*existing)[i]
+			byID[card.ID] = card
+			key := strings.ToLower(strings.TrimSpace(card.Text))
+			if _, ok := byText[key]; !ok {
+				byText[key] = card
+			}
+		}
+
+		used := make(map[string]bool)
+		for i := range *list.Cards {
+			card := &(*list.Cards)[i]
+			match := byID[card.ID]
+			if match == nil || used[match.ID] {
+				match = byText[strings.ToLower(strings.TrimSpace(card.Text))]
+			}
+			if match == nil || used[match.ID] {
+				*card = Card{
+					ID: uuid.New().String(), Type: list.Type, Text: card.Text, Description: card.Description,
+					Author: author, CreatedAt: now, UpdatedAt: now,
+					PainIntensity: card.PainIntensity, AffectedShare: card.AffectedShare,
+					Kind: card.Kind, Category: card.Category,
+				}
+				continue
+			}
+
+			used[match.ID] = true
+			if card.legacy {
+				text := card.Text
+				*card = *match
+				card.Text = text
+			}
+			card.ID = match.ID
+			card.Type = list.Type
+			card.Author = match.Author
+			card.CreatedAt = match.CreatedAt
+			card.UpdatedAt = match.UpdatedAt
+			card.Merged = match.Merged
+			if !card.SameContent(match) {
+				card.UpdatedAt = now
+			}
+		}
+	}
+}
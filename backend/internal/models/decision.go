@@ -0,0 +1,426 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MagicLensScoreMax 魔术镜头评分的最高分（1-5）
+const MagicLensScoreMax = 5
+
+// EffectiveWeight 镜头的权重；未设置（旧数据）时为 1，设为 0 时该镜头不参与加权
+func (l MagicLens) EffectiveWeight() float64 {
+	if l.Weight == nil {
+		return 1
+	}
+	return *l.Weight
+}
+
+// ValidateMagicLenses 检查镜头权重与评分范围
+func ValidateMagicLenses(lenses []MagicLens) error {
+	for _, lens := range lenses {
+		if lens.Weight != nil && *lens.Weight < 0 {
+			return fmt.Errorf("weight of lens %s must not be negative", lens.Name)
+		}
+		for _, evaluation := range lens.Evaluations {
+			if evaluation.Score < 0 || evaluation.Score > MagicLensScoreMax {
+				return fmt.Errorf("score of lens %s for path %s must be within 0-%d", lens.Name, evaluation.PathID, MagicLensScoreMax)
+			}
+		}
+	}
+	return nil
+}
+
+// LensWeight 镜头权重及其占比
+type LensWeight struct {
+	Name             string  `json:"name"`
+	Weight           float64 `json:"weight"`
+	NormalizedWeight float64 `json:"normalized_weight"` // 占全部镜头权重的比例
+}
+
+// PathDecision 一条执行路径的加权得分
+type PathDecision struct {
+	PathID          string   `json:"path_id"`
+	Name            string   `json:"name"`
+	Rank            int      `json:"rank"`                     // 从 1 开始，没有任何评分的路径为 0
+	WeightedScore   float64  `json:"weighted_score"`           // 已评镜头按权重加权的平均分（1-5）
+	NormalizedScore float64  `json:"normalized_score"`         // weighted_score / 5
+	Coverage        float64  `json:"coverage"`                 // 已评镜头的权重占比
+	MissingLenses   []string `json:"missing_lenses,omitempty"` // 尚未给该路径评分的镜头
+}
+
+// LensRanking 单个镜头下的路径排名
+type LensRanking struct {
+	Lens     string            `json:"lens"`
+	Weight   float64           `json:"weight"`
+	Rankings []LensPathRanking `json:"rankings"`
+}
+
+// LensPathRanking 镜头下一条路径的名次
+type LensPathRanking struct {
+	PathID string  `json:"path_id"`
+	Name   string  `json:"name"`
+	Rank   int     `json:"rank"` // 同分同名次
+	Score  float64 `json:"score"`
+}
+
+// LensSensitivity 单个镜头权重变化对胜出路径的影响
+type LensSensitivity struct {
+	Lens   string  `json:"lens"`
+	Weight float64 `json:"weight"`
+	// Stable 为 true 表示该镜头的权重在 [0, ∞) 内如何调整都不会改变胜出路径
+	Stable bool `json:"stable"`
+	// FlipWeight 胜出路径改变时的权重，Change 为相对当前权重的变化量
+	FlipWeight float64 `json:"flip_weight,omitempty"`
+	Change     float64 `json:"change,omitempty"`
+	NewWinner  string  `json:"new_winner,omitempty"`
+}
+
+// MissingEvaluation 缺少的评分
+type MissingEvaluation struct {
+	Lens     string `json:"lens"`
+	PathID   string `json:"path_id"`
+	PathName string `json:"path_name"`
+}
+
+// ApproachDecision 魔术镜头决策矩阵
+type ApproachDecision struct {
+	Lenses             []LensWeight        `json:"lenses"`
+	Paths              []PathDecision      `json:"paths"` // 按名次排序
+	WinnerPathID       string              `json:"winner_path_id,omitempty"`
+	Tie                bool                `json:"tie"`    // 最高分有多条路径
+	Margin             float64             `json:"margin"` // 第一名领先第二名的加权分差
+	LensRankings       []LensRanking       `json:"lens_rankings"`
+	Sensitivity        []LensSensitivity   `json:"sensitivity"`
+	MissingEvaluations []MissingEvaluation `json:"missing_evaluations"`
+	SelectedPath       string              `json:"selected_path,omitempty"`
+	SelectedIsWinner   bool                `json:"selected_is_winner"`
+	SuggestedReasoning string              `json:"suggested_reasoning,omitempty"`
+}
+
+// decisionEpsilon 比较得分时的容差
+const decisionEpsilon = 1e-9
+
+// BuildApproachDecision 按镜头权重汇总各路径的评分。没有评分的镜头不计入该路径的加权平均，
+// 与网页端的平均分一致；缺少的评分在 MissingEvaluations 中列出
+func BuildApproachDecision(approach Approach) *ApproachDecision {
+	decision := &ApproachDecision{
+		Lenses:             make([]LensWeight, 0, len(approach.MagicLenses)),
+		Paths:              make([]PathDecision, 0, len(approach.Paths)),
+		LensRankings:       make([]LensRanking, 0, len(approach.MagicLenses)),
+		Sensitivity:        make([]LensSensitivity, 0, len(approach.MagicLenses)),
+		MissingEvaluations: make([]MissingEvaluation, 0),
+		SelectedPath:       approach.SelectedPath,
+	}
+
+	weights := make([]float64, len(approach.MagicLenses))
+	totalWeight := 0.0
+	for i, lens := range approach.MagicLenses {
+		weights[i] = lens.EffectiveWeight()
+		totalWeight += weights[i]
+	}
+	for i, lens := range approach.MagicLenses {
+		normalized := 0.0
+		if totalWeight > 0 {
+			normalized = round3(weights[i] / totalWeight)
+		}
+		decision.Lenses = append(decision.Lenses, LensWeight{Name: lens.Name, Weight: weights[i], NormalizedWeight: normalized})
+	}
+
+	scores := lensScores(approach)
+	for _, path := range approach.Paths {
+		result := PathDecision{PathID: path.ID, Name: path.Name}
+		evaluated := 0.0
+		for i, lens := range approach.MagicLenses {
+			if _, ok := scores[i][path.ID]; ok {
+				evaluated += weights[i]
+				continue
+			}
+			result.MissingLenses = append(result.MissingLenses, lens.Name)
+			decision.MissingEvaluations = append(decision.MissingEvaluations, MissingEvaluation{
+				Lens: lens.Name, PathID: path.ID, PathName: path.Name,
+			})
+		}
+		if score, ok := weightedScore(scores, weights, path.ID); ok {
+			result.WeightedScore = round3(score)
+			result.NormalizedScore = round3(score / MagicLensScoreMax)
+		}
+		if totalWeight > 0 {
+			result.Coverage = round3(evaluated / totalWeight)
+		}
+		decision.Paths = append(decision.Paths, result)
+	}
+
+	rankPaths(decision, approach, scores, weights)
+	for i, lens := range approach.MagicLenses {
+		decision.LensRankings = append(decision.LensRankings, rankLens(lens, weights[i], approach.Paths, scores[i]))
+	}
+	if decision.WinnerPathID != "" && !decision.Tie {
+		for i, lens := range approach.MagicLenses {
+			decision.Sensitivity = append(decision.Sensitivity,
+				lensSensitivity(lens.Name, i, approach.Paths, scores, weights, decision.WinnerPathID))
+		}
+	}
+
+	decision.SelectedIsWinner = approach.SelectedPath != "" && approach.SelectedPath == decision.WinnerPathID
+	decision.SuggestedReasoning = suggestReasoning(decision)
+	return decision
+}
+
+// lensScores 每个镜头下各路径的评分；同一路径有多条评分时取最后一条
+func lensScores(approach Approach) []map[string]float64 {
+	known := make(map[string]bool, len(approach.Paths))
+	for _, path := range approach.Paths {
+		known[path.ID] = true
+	}
+	scores := make([]map[string]float64, len(approach.MagicLenses))
+	for i, lens := range approach.MagicLenses {
+		scores[i] = make(map[string]float64)
+		for _, evaluation := range lens.Evaluations {
+			if known[evaluation.PathID] {
+				scores[i][evaluation.PathID] = evaluation.Score
+			}
+		}
+	}
+	return scores
+}
+
+// weightedScore 路径在已评镜头上的加权平均分；没有任何已评镜头（或权重为 0）时返回 false
+func weightedScore(scores []map[string]float64, weights []float64, pathID string) (float64, bool) {
+	sum, weight := 0.0, 0.0
+	for i := range weights {
+		if score, ok := scores[i][pathID]; ok {
+			sum += weights[i] * score
+			weight += weights[i]
+		}
+	}
+	if weight == 0 {
+		return 0, false
+	}
+	return sum / weight, true
+}
+
+// winner 按给定权重计算的第一名；第二个返回值表示是否并列
+func winner(paths []Path, scores []map[string]float64, weights []float64) (string, bool) {
+	best, bestScore, tie := "", math.Inf(-1), false
+	for _, path := range paths {
+		score, ok := weightedScore(scores, weights, path.ID)
+		if !ok {
+			continue
+		}
+		switch {
+		case score > bestScore+decisionEpsilon:
+			best, bestScore, tie = path.ID, score, false
+		case math.Abs(score-bestScore) <= decisionEpsilon:
+			tie = true
+		}
+	}
+	return best, tie
+}
+
+// rankPaths 按加权得分排序路径并确定胜出路径与领先分差
+func rankPaths(decision *ApproachDecision, approach Approach, scores []map[string]float64, weights []float64) {
+	rated := make(map[string]bool)
+	for _, path := range approach.Paths {
+		if _, ok := weightedScore(scores, weights, path.ID); ok {
+			rated[path.ID] = true
+		}
+	}
+
+	sort.SliceStable(decision.Paths, func(i, j int) bool {
+		a, b := decision.Paths[i], decision.Paths[j]
+		if rated[a.PathID] != rated[b.PathID] {
+			return rated[a.PathID]
+		}
+		return a.WeightedScore > b.WeightedScore
+	})
+
+	for i := range decision.Paths {
+		if !rated[decision.Paths[i].PathID] {
+			break
+		}
+		decision.Paths[i].Rank = i + 1
+		if i > 0 && math.Abs(decision.Paths[i].WeightedScore-decision.Paths[i-1].WeightedScore) <= decisionEpsilon {
+			decision.Paths[i].Rank = decision.Paths[i-1].Rank
+		}
+	}
+
+	if len(decision.Paths) == 0 || decision.Paths[0].Rank == 0 {
+		return
+	}
+	decision.WinnerPathID, decision.Tie = winner(approach.Paths, scores, weights)
+	if len(decision.Paths) > 1 && decision.Paths[1].Rank > 0 {
+		decision.Margin = round3(decision.Paths[0].WeightedScore - decision.Paths[1].WeightedScore)
+	}
+}
+
+// rankLens 单个镜头下的路径排名，没有评分的路径不参与
+func rankLens(lens MagicLens, weight float64, paths []Path, scores map[string]float64) LensRanking {
+	ranking := LensRanking{Lens: lens.Name, Weight: weight, Rankings: make([]LensPathRanking, 0, len(scores))}
+	for _, path := range paths {
+		if score, ok := scores[path.ID]; ok {
+			ranking.Rankings = append(ranking.Rankings, LensPathRanking{PathID: path.ID, Name: path.Name, Score: score})
+		}
+	}
+	sort.SliceStable(ranking.Rankings, func(i, j int) bool {
+		return ranking.Rankings[i].Score > ranking.Rankings[j].Score
+	})
+	for i := range ranking.Rankings {
+		ranking.Rankings[i].Rank = i + 1
+		if i > 0 && ranking.Rankings[i].Score == ranking.Rankings[i-1].Score {
+			ranking.Rankings[i].Rank = ranking.Rankings[i-1].Rank
+		}
+	}
+	return ranking
+}
+
+// lensSensitivity 求改变胜出路径所需的最小权重变化。
+// 只调整镜头 l 的权重 w 时，路径 p 的加权平均为 (A_p + w·s_p) / (B_p + w)，其中 A_p、B_p 为其他已评镜头的加权和与权重和；
+// 未被 l 评分的路径得分不随 w 变化。胜出路径与其他路径得分相等的 w 是一个二次方程的根，逐个验证越过该点后胜出路径是否改变
+func lensSensitivity(name string, lens int, paths []Path, scores []map[string]float64, weights []float64, winnerID string) LensSensitivity {
+	current := weights[lens]
+	result := LensSensitivity{Lens: name, Weight: current, Stable: true}
+
+	at := func(w float64) []float64 {
+		adjusted := append([]float64{}, weights...)
+		adjusted[lens] = w
+		return adjusted
+	}
+
+	// 候选根
+	var roots []float64
+	coefficients := func(pathID string) (a, b, s float64, rated bool) {
+		for i := range weights {
+			score, ok := scores[i][pathID]
+			if !ok {
+				continue
+			}
+			if i == lens {
+				s, rated = score, true
+				continue
+			}
+			a += weights[i] * score
+			b += weights[i]
+		}
+		if !rated && b > 0 {
+			// 得分与 w 无关，相当于以自身得分为 l 的评分
+			s, rated = a/b, true
+		}
+		return a, b, s, rated
+	}
+	ap, bp, sp, _ := coefficients(winnerID)
+	for _, path := range paths {
+		if path.ID == winnerID {
+			continue
+		}
+		aq, bq, sq, rated := coefficients(path.ID)
+		if !rated {
+			continue
+		}
+		// (A_p + w s_p)(B_q + w) = (A_q + w s_q)(B_p + w)
+		qa := sp - sq
+		qb := ap + sp*bq - aq - sq*bp
+		qc := ap*bq - aq*bp
+		roots = append(roots, quadraticRoots(qa, qb, qc)...)
+	}
+	roots = append(roots, 0)
+
+	best := math.Inf(1)
+	for _, root := range roots {
+		if root < 0 || math.Abs(root-current) <= decisionEpsilon {
+			continue
+		}
+		// 越过交点一小步后检查胜出路径
+		step := math.Max(1e-6, math.Abs(root)*1e-6)
+		probe := root + step
+		if root < current {
+			probe = math.Max(0, root-step)
+		}
+		newWinner, tie := winner(paths, scores, at(probe))
+		if newWinner == "" || (newWinner == winnerID && !tie) {
+			continue
+		}
+		if distance := math.Abs(root - current); distance < best {
+			best = distance
+			result.Stable = false
+			result.FlipWeight = round3(root)
+			result.Change = round3(root - current)
+			result.NewWinner = newWinner
+			if newWinner == winnerID {
+				result.NewWinner = ""
+			}
+		}
+	}
+	return result
+}
+
+// quadraticRoots 求 a·x² + b·x + c = 0 的实根
+func quadraticRoots(a, b, c float64) []float64 {
+	if math.Abs(a) <= decisionEpsilon {
+		if math.Abs(b) <= decisionEpsilon {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+	discriminant := b*b - 4*a*c
+	if discriminant < 0 {
+		return nil
+	}
+	sqrt := math.Sqrt(discriminant)
+	return []float64{(-b + sqrt) / (2 * a), (-b - sqrt) / (2 * a)}
+}
+
+// suggestReasoning 按决策结果生成可填入决策理由的说明
+func suggestReasoning(decision *ApproachDecision) string {
+	if decision.WinnerPathID == "" {
+		return ""
+	}
+	top := decision.Paths[0]
+	if decision.Tie {
+		var tied []string
+		for _, path := range decision.Paths {
+			if path.Rank == top.Rank {
+				tied = append(tied, path.Name)
+			}
+		}
+		return fmt.Sprintf("按魔术镜头加权评分，%s 并列第一（%.2f / 5），需要补充评估或调整权重后再决定。",
+			strings.Join(tied, "、"), top.WeightedScore)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "按魔术镜头加权评分，%s 以 %.2f / 5 排名第一", top.Name, top.WeightedScore)
+	if len(decision.Paths) > 1 && decision.Paths[1].Rank > 0 {
+		fmt.Fprintf(&b, "，领先 %s %.2f 分", decision.Paths[1].Name, decision.Margin)
+	}
+	b.WriteString("。")
+
+	var strongest []string
+	for _, ranking := range decision.LensRankings {
+		if len(ranking.Rankings) > 1 && ranking.Rankings[0].PathID == top.PathID && ranking.Rankings[1].Rank > 1 {
+			strongest = append(strongest, ranking.Lens)
+		}
+	}
+	if len(strongest) > 0 {
+		fmt.Fprintf(&b, "在%s镜头下单独领先。", strings.Join(strongest, "、"))
+	}
+
+	var fragile []string
+	for _, sensitivity := range decision.Sensitivity {
+		if !sensitivity.Stable && sensitivity.Weight > 0 && math.Abs(sensitivity.Change) <= 0.25*sensitivity.Weight {
+			fragile = append(fragile, sensitivity.Lens)
+		}
+	}
+	if len(fragile) > 0 {
+		fmt.Fprintf(&b, "结论对%s的权重较敏感（调整 25%% 以内即会改变）。", strings.Join(fragile, "、"))
+	}
+	if len(top.MissingLenses) > 0 {
+		fmt.Fprintf(&b, "尚缺%s的评分。", strings.Join(top.MissingLenses, "、"))
+	}
+	return b.String()
+}
+
+func round3(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
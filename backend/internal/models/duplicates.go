@@ -0,0 +1,477 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 可以查找重复条目的类别
+const (
+	DuplicateCustomers   = "customers"
+	DuplicateProblems    = "problems"
+	DuplicateCompetition = "competition"
+	DuplicateAdvantages  = "advantages"
+	DuplicateFactors     = "factors" // 经典与自定义差异化因素
+)
+
+// duplicateCardTypes 基础信息类别对应的卡片类型
+var duplicateCardTypes = map[string]string{
+	DuplicateCustomers:   CardCustomer,
+	DuplicateProblems:    CardProblem,
+	DuplicateCompetition: CardCompetition,
+	DuplicateAdvantages:  CardAdvantage,
+}
+
+// 默认的相似度阈值：嵌入向量的余弦相似度普遍较高，阈值也较高
+const (
+	DefaultEmbeddingThreshold = 0.85
+	DefaultTextThreshold      = 0.6
+)
+
+// MergedItem 被合并到代表条目中的原始条目，保留原文与作者
+type MergedItem struct {
+	ID        string     `json:"id"`
+	Text      string     `json:"text"`
+	Author    string     `json:"author,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"` // 差异化因素没有创建时间
+	MergedAt  time.Time  `json:"merged_at"`
+}
+
+// DuplicateItem 参与聚类的一个条目
+type DuplicateItem struct {
+	ID     string
+	Text   string
+	Author string
+}
+
+// DuplicateMember 聚类中的一个条目
+type DuplicateMember struct {
+	ID         string  `json:"id"`
+	Text       string  `json:"text"`
+	Author     string  `json:"author,omitempty"`
+	Similarity float64 `json:"similarity"` // 与代表条目的相似度
+}
+
+// DuplicateCluster 一组相似的条目及建议的合并方式
+type DuplicateCluster struct {
+	RepresentativeID string            `json:"representative_id"`
+	Label            string            `json:"label"`    // 建议的合并后文本：与其他条目平均最相似的条目
+	Members          []DuplicateMember `json:"members"`  // 代表条目排在第一位
+	Authors          []string          `json:"authors"`  // 各条目的作者，去重
+	Cohesion         float64           `json:"cohesion"` // 条目两两之间的平均相似度
+}
+
+// IsDuplicateKind 是否为可以查找重复条目的类别
+func IsDuplicateKind(kind string) bool {
+	_, ok := duplicateCardTypes[kind]
+	return ok || kind == DuplicateFactors
+}
+
+// DuplicateItems 返回房间中某一类别的条目，跳过空白条目
+func (r *Room) DuplicateItems(kind string) ([]DuplicateItem, error) {
+	items := make([]DuplicateItem, 0)
+	if kind == DuplicateFactors {
+		for _, factor := range r.allFactors() {
+			if strings.TrimSpace(factor.Name) != "" {
+				items = append(items, DuplicateItem{ID: factor.ID, Text: factor.Name})
+			}
+		}
+		return items, nil
+	}
+
+	cards, ok := r.Foundation.List(duplicateCardTypes[kind])
+	if !ok {
+		return nil, fmt.Errorf("invalid kind: %s", kind)
+	}
+	for _, card := range *cards {
+		if strings.TrimSpace(card.Text) != "" {
+			items = append(items, DuplicateItem{ID: card.ID, Text: card.Text, Author: card.Author})
+		}
+	}
+	return items, nil
+}
+
+func (r *Room) allFactors() []DifferentiationFactor {
+	return append(append([]DifferentiationFactor{}, r.Differentiation.ClassicFactors...), r.Differentiation.CustomFactors...)
+}
+
+// ClusterDuplicates 按平均链接层次聚类：每次合并平均相似度最高的两组，直到最高值低于阈值。
+// similarities 为条目两两之间的相似度；只返回包含两个及以上条目的组，条目多的排在前面
+func ClusterDuplicates(items []DuplicateItem, similarities [][]float64, threshold float64) []DuplicateCluster {
+	groups := make([][]int, len(items))
+	for i := range items {
+		groups[i] = []int{i}
+	}
+	average := func(a, b []int) float64 {
+		total := 0.0
+		for _, i := range a {
+			for _, j := range b {
+				total += similarities[i][j]
+			}
+		}
+		return total / float64(len(a)*len(b))
+	}
+
+	for len(groups) > 1 {
+		best, bestA, bestB := -1.0, -1, -1
+		for a := range groups {
+			for b := a + 1; b < len(groups); b++ {
+				if similarity := average(groups[a], groups[b]); similarity > best {
+					best, bestA, bestB = similarity, a, b
+				}
+			}
+		}
+		if best < threshold {
+			break
+		}
+		groups[bestA] = append(groups[bestA], groups[bestB]...)
+		groups = append(groups[:bestB], groups[bestB+1:]...)
+	}
+
+	clusters := make([]DuplicateCluster, 0)
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Ints(group)
+		clusters = append(clusters, buildCluster(items, similarities, group))
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].Members) > len(clusters[j].Members)
+	})
+	return clusters
+}
+
+// buildCluster 选出与其他条目平均最相似的条目作为代表，相同时取靠前的条目
+func buildCluster(items []DuplicateItem, similarities [][]float64, group []int) DuplicateCluster {
+	representative, bestScore, pairs, total := group[0], -1.0, 0, 0.0
+	for _, i := range group {
+		score := 0.0
+		for _, j := range group {
+			if i != j {
+				score += similarities[i][j]
+				if i < j {
+					total += similarities[i][j]
+					pairs++
+				}
+			}
+		}
+		if score > bestScore {
+			representative, bestScore = i, score
+		}
+	}
+
+	cluster := DuplicateCluster{
+		RepresentativeID: items[representative].ID,
+		Label:            items[representative].Text,
+		Members:          make([]DuplicateMember, 0, len(group)),
+		Authors:          make([]string, 0),
+		Cohesion:         total / float64(pairs),
+	}
+	ordered := append([]int{representative}, group...)
+	seenAuthors := make(map[string]bool)
+	for position, i := range ordered {
+		if position > 0 && i == representative {
+			continue
+		}
+		item := items[i]
+		cluster.Members = append(cluster.Members, DuplicateMember{
+			ID: item.ID, Text: item.Text, Author: item.Author, Similarity: similarities[representative][i],
+		})
+		if item.Author != "" && !seenAuthors[item.Author] {
+			seenAuthors[item.Author] = true
+			cluster.Authors = append(cluster.Authors, item.Author)
+		}
+	}
+	return cluster
+}
+
+// DuplicateMerge 将 MemberIDs 合并到 RepresentativeID；Label 非空时作为合并后的文本
+type DuplicateMerge struct {
+	RepresentativeID string   `json:"representative_id"`
+	MemberIDs        []string `json:"member_ids"`
+	Label            string   `json:"label"`
+}
+
+// MergeResult 一次合并的结果
+type MergeResult struct {
+	RepresentativeID string       `json:"representative_id"`
+	Label            string       `json:"label"`
+	Merged           []MergedItem `json:"merged"` // 本次合并的条目
+	Texts            []string     `json:"-"`      // 合并前代表条目与被合并条目的文本，用于合并投票选项
+}
+
+// MergeDuplicates 在房间中合并一组重复条目：被合并的条目从列表中删除，原文与作者记录在代表条目的
+// merged 中；代表条目缺少的描述与属性取自被合并的条目，问题的痛点强度与因素的权重取最大值
+func (r *Room) MergeDuplicates(kind string, merge DuplicateMerge, now time.Time) (*MergeResult, error) {
+	if len(merge.MemberIDs) == 0 {
+		return nil, fmt.Errorf("member_ids is required")
+	}
+	seen := map[string]bool{merge.RepresentativeID: true}
+	for _, id := range merge.MemberIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("card %s appears more than once in the merge", id)
+		}
+		seen[id] = true
+	}
+
+	if kind == DuplicateFactors {
+		return r.mergeFactors(merge, now)
+	}
+	cardType, ok := duplicateCardTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("invalid kind: %s", kind)
+	}
+	cards, _ := r.Foundation.List(cardType)
+
+	index := func(id string) int {
+		for i := range *cards {
+			if (*cards)[i].ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	rep := index(merge.RepresentativeID)
+	if rep < 0 {
+		return nil, fmt.Errorf("%s card not found: %s", cardType, merge.RepresentativeID)
+	}
+	members := make([]Card, 0, len(merge.MemberIDs))
+	for _, id := range merge.MemberIDs {
+		i := index(id)
+		if i < 0 {
+			return nil, fmt.Errorf("%s card not found: %s", cardType, id)
+		}
+		members = append(members, (*cards)[i])
+	}
+
+	representative := (*cards)[rep]
+	result := &MergeResult{RepresentativeID: representative.ID, Texts: []string{representative.Text}}
+	for _, member := range members {
+		result.Texts = append(result.Texts, member.Text)
+		result.Merged = append(result.Merged, MergedItem{
+			ID: member.ID, Text: member.Text, Author: member.Author, CreatedAt: &member.CreatedAt, MergedAt: now,
+		})
+		representative.Merged = append(representative.Merged, result.Merged[len(result.Merged)-1])
+		representative.Merged = append(representative.Merged, member.Merged...)
+
+		if representative.Description == "" {
+			representative.Description = member.Description
+		}
+		if member.PainIntensity > representative.PainIntensity {
+			representative.PainIntensity = member.PainIntensity
+		}
+		if representative.AffectedShare == "" {
+			representative.AffectedShare = member.AffectedShare
+		}
+		if representative.Kind == "" {
+			representative.Kind = member.Kind
+		}
+		if representative.Category == "" {
+			representative.Category = member.Category
+		}
+	}
+	if label := strings.TrimSpace(merge.Label); label != "" {
+		representative.Text = label
+	}
+	for _, card := range *cards {
+		if !seen[card.ID] && strings.EqualFold(strings.TrimSpace(card.Text), representative.Text) {
+			return nil, fmt.Errorf("%s card already exists: %s", cardType, card.Text)
+		}
+	}
+	representative.UpdatedAt = now
+
+	kept := make([]Card, 0, len(*cards)-len(members))
+	for _, card := range *cards {
+		if card.ID == representative.ID {
+			card = representative
+		} else if seen[card.ID] {
+			continue
+		}
+		card.Order = len(kept)
+		kept = append(kept, card)
+	}
+	*cards = kept
+
+	result.Label = representative.Text
+	return result, nil
+}
+
+// mergeFactors 合并差异化因素；产品在被合并因素上的得分在代表因素没有得分时沿用
+func (r *Room) mergeFactors(merge DuplicateMerge, now time.Time) (*MergeResult, error) {
+	find := func(id string) *DifferentiationFactor {
+		for _, list := range []*[]DifferentiationFactor{&r.Differentiation.ClassicFactors, &r.Differentiation.CustomFactors} {
+			for i := range *list {
+				if (*list)[i].ID == id {
+					return &(*list)[i]
+				}
+			}
+		}
+		return nil
+	}
+	representative := find(merge.RepresentativeID)
+	if representative == nil {
+		return nil, fmt.Errorf("factor not found: %s", merge.RepresentativeID)
+	}
+	members := make([]DifferentiationFactor, 0, len(merge.MemberIDs))
+	for _, id := range merge.MemberIDs {
+		member := find(id)
+		if member == nil {
+			return nil, fmt.Errorf("factor not found: %s", id)
+		}
+		members = append(members, *member)
+	}
+
+	label := strings.TrimSpace(merge.Label)
+	if label == "" {
+		label = representative.Name
+	}
+	for _, factor := range r.allFactors() {
+		if factor.ID != representative.ID && !containsString(merge.MemberIDs, factor.ID) &&
+			strings.EqualFold(strings.TrimSpace(factor.Name), label) {
+			return nil, fmt.Errorf("factor already exists: %s", factor.Name)
+		}
+	}
+
+	result := &MergeResult{RepresentativeID: representative.ID, Label: label, Texts: []string{representative.Name}}
+	for _, member := range members {
+		item := MergedItem{ID: member.ID, Text: member.Name, MergedAt: now}
+		result.Texts = append(result.Texts, member.Name)
+		result.Merged = append(result.Merged, item)
+		representative.Merged = append(append(representative.Merged, item), member.Merged...)
+		if representative.Description == "" {
+			representative.Description = member.Description
+		}
+		if member.Weight > representative.Weight {
+			representative.Weight = member.Weight
+		}
+
+		for i := range r.Differentiation.Matrix.Products {
+			scores := r.Differentiation.Matrix.Products[i].Scores
+			score, ok := scores[member.ID]
+			if !ok {
+				continue
+			}
+			if _, has := scores[representative.ID]; !has {
+				scores[representative.ID] = score
+			}
+			delete(scores, member.ID)
+		}
+	}
+	representative.Name = label
+
+	remove := func(list []DifferentiationFactor) []DifferentiationFactor {
+		kept := make([]DifferentiationFactor, 0, len(list))
+		for _, factor := range list {
+			if !containsString(merge.MemberIDs, factor.ID) {
+				kept = append(kept, factor)
+			}
+		}
+		return kept
+	}
+	r.Differentiation.ClassicFactors = remove(r.Differentiation.ClassicFactors)
+	r.Differentiation.CustomFactors = remove(r.Differentiation.CustomFactors)
+	return result, nil
+}
+
+// KeepMerged 沿用当前数据中因素的合并记录，网页端提交差异化数据时不包含这些记录
+func (d *Differentiation) KeepMerged(current *Differentiation) {
+	merged := make(map[string][]MergedItem)
+	for _, factor := range append(append([]DifferentiationFactor{}, current.ClassicFactors...), current.CustomFactors...) {
+		if len(factor.Merged) > 0 {
+			merged[factor.ID] = factor.Merged
+		}
+	}
+	for _, list := range []*[]DifferentiationFactor{&d.ClassicFactors, &d.CustomFactors} {
+		for i := range *list {
+			if (*list)[i].Merged == nil {
+				(*list)[i].Merged = merged[(*list)[i].ID]
+			}
+		}
+	}
+}
+
+// MergeOptions 将文本与 texts 中任一项相同的选项合并为一个以 label 为文本的选项，保留所有人的投票：
+// 同一用户在多个被合并选项上的投票合为一票，圆点数相加，排序投票保留最靠前的位次。
+// 已拍板的投票不做修改；返回投票是否有变化
+func (v *Vote) MergeOptions(texts []string, label string) bool {
+	if v.Status == VoteDecided {
+		return false
+	}
+	matches := func(text string) bool {
+		for _, candidate := range texts {
+			if strings.EqualFold(strings.TrimSpace(candidate), strings.TrimSpace(text)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	survivor := -1
+	for _, text := range texts {
+		for i, option := range v.Options {
+			if survivor < 0 && strings.EqualFold(strings.TrimSpace(option.Text), strings.TrimSpace(text)) {
+				survivor = i
+			}
+		}
+	}
+	if survivor < 0 {
+		return false
+	}
+
+	changed := v.Options[survivor].Text != label
+	v.Options[survivor].Text = label
+	byUser := make(map[string]int)
+	votes := make([]UserVote, 0)
+	add := func(vote UserVote) {
+		i, ok := byUser[vote.UserID]
+		if !ok {
+			byUser[vote.UserID] = len(votes)
+			votes = append(votes, vote)
+			return
+		}
+		existing := &votes[i]
+		if v.Mode() == VoteNoteAndVote {
+			existing.Weight += vote.Weight
+		} else if vote.Rank > 0 && (existing.Rank == 0 || vote.Rank < existing.Rank) {
+			existing.Rank = vote.Rank
+			existing.Weight = vote.Weight
+		}
+		if existing.Comment == "" {
+			existing.Comment = vote.Comment
+		}
+	}
+	for _, vote := range v.Options[survivor].Votes {
+		add(vote)
+	}
+
+	options := make([]VoteOption, 0, len(v.Options))
+	for i, option := range v.Options {
+		if i != survivor && matches(option.Text) {
+			for _, vote := range option.Votes {
+				add(vote)
+			}
+			changed = true
+			continue
+		}
+		options = append(options, option)
+	}
+	for i := range options {
+		if options[i].ID == v.Options[survivor].ID {
+			options[i].Votes = votes
+		}
+	}
+	v.Options = options
+	return changed
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
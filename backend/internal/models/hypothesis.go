@@ -0,0 +1,190 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 假设状态
+const (
+	HypothesisUntested    = "untested"
+	HypothesisValidated   = "validated"
+	HypothesisInvalidated = "invalidated"
+)
+
+// 假设置信度
+const (
+	HypothesisConfidenceLow    = "low"
+	HypothesisConfidenceMedium = "medium"
+	HypothesisConfidenceHigh   = "high"
+)
+
+// 假设来源
+const (
+	HypothesisSourceManual = "manual"
+	HypothesisSourceDraft  = "draft" // 由房间的基础信息与执行路径自动起草
+	HypothesisSourceAgent  = "agent" // 由 assumption_checker 记录
+)
+
+// HypothesisTemplate 创始假设模板，依次填入客户、问题、方案、竞争对手、差异化
+const HypothesisTemplate = "If we help %s solve %s with %s, they will choose it over %s because %s"
+
+// maxDraftHypotheses 一次最多起草的假设数量
+const maxDraftHypotheses = 12
+
+// Hypothesis 房间中一条待验证的创始假设
+type Hypothesis struct {
+	ID               string    `json:"id"`
+	RoomID           string    `json:"room_id"`
+	Statement        string    `json:"statement"`
+	Customer         string    `json:"customer,omitempty"`
+	Problem          string    `json:"problem,omitempty"`
+	Approach         string    `json:"approach,omitempty"`
+	PathID           string    `json:"path_id,omitempty"` // 对应的执行路径
+	Competition      string    `json:"competition,omitempty"`
+	Differentiation  string    `json:"differentiation,omitempty"`
+	Confidence       string    `json:"confidence"`
+	ValidationMethod string    `json:"validation_method,omitempty"` // 如用户访谈、原型测试
+	SuccessMetric    string    `json:"success_metric,omitempty"`    // 判定假设成立的指标
+	Status           string    `json:"status"`
+	Evidence         string    `json:"evidence,omitempty"` // 验证结果与依据
+	Source           string    `json:"source"`
+	AgentName        string    `json:"agent_name,omitempty"`
+	CreatedBy        string    `json:"created_by,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// NewHypothesis 创建未验证的假设
+func NewHypothesis(roomID string) *Hypothesis {
+	now := time.Now()
+	return &Hypothesis{
+		ID:         uuid.New().String(),
+		RoomID:     roomID,
+		Confidence: HypothesisConfidenceLow,
+		Status:     HypothesisUntested,
+		Source:     HypothesisSourceManual,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// HasTemplateParts 是否填写了模板的任一部分
+func (h *Hypothesis) HasTemplateParts() bool {
+	return h.Customer != "" || h.Problem != "" || h.Approach != "" || h.Competition != "" || h.Differentiation != ""
+}
+
+// BuildStatement 按模板生成陈述，未填写的部分保留 [customer] 等占位符
+func (h *Hypothesis) BuildStatement() string {
+	slot := func(value, placeholder string) string {
+		if strings.TrimSpace(value) == "" {
+			return "[" + placeholder + "]"
+		}
+		return strings.TrimSpace(value)
+	}
+	return fmt.Sprintf(HypothesisTemplate,
+		slot(h.Customer, "customer"),
+		slot(h.Problem, "problem"),
+		slot(h.Approach, "approach"),
+		slot(h.Competition, "competition"),
+		slot(h.Differentiation, "differentiation"))
+}
+
+// Validate 检查假设字段；未提供陈述时按模板生成
+func (h *Hypothesis) Validate() error {
+	h.Statement = strings.TrimSpace(h.Statement)
+	if h.Statement == "" && h.HasTemplateParts() {
+		h.Statement = h.BuildStatement()
+	}
+	if h.Statement == "" {
+		return fmt.Errorf("hypothesis statement or template parts are required")
+	}
+	switch h.Confidence {
+	case "":
+		h.Confidence = HypothesisConfidenceLow
+	case HypothesisConfidenceLow, HypothesisConfidenceMedium, HypothesisConfidenceHigh:
+	default:
+		return fmt.Errorf("invalid hypothesis confidence: %s", h.Confidence)
+	}
+	switch h.Status {
+	case "":
+		h.Status = HypothesisUntested
+	case HypothesisUntested, HypothesisValidated, HypothesisInvalidated:
+	default:
+		return fmt.Errorf("invalid hypothesis status: %s", h.Status)
+	}
+	return nil
+}
+
+// DraftHypotheses 由房间的目标客户、核心问题、执行路径、竞争对手与核心原则起草假设。
+// 已选定方案时只针对选定路径起草；每个客户与问题的组合一条，最多 maxDraftHypotheses 条
+func DraftHypotheses(room *Room) []*Hypothesis {
+	paths := room.Approach.Paths
+	for _, path := range room.Approach.Paths {
+		if path.ID == room.Approach.SelectedPath {
+			paths = []Path{path}
+			break
+		}
+	}
+	if len(paths) == 0 {
+		paths = []Path{{}}
+	}
+
+	customers := CardTexts(room.Foundation.Customers)
+	problems := CardTexts(room.Foundation.Problems)
+	competition := strings.Join(CardTexts(room.Foundation.Competition), ", ")
+	differentiation := strings.Join(nonEmpty(room.Differentiation.Principles), "; ")
+	if differentiation == "" {
+		var factors []string
+		for _, factor := range append(append([]DifferentiationFactor{}, room.Differentiation.ClassicFactors...),
+			room.Differentiation.CustomFactors...) {
+			factors = append(factors, factor.Name)
+		}
+		differentiation = strings.Join(nonEmpty(factors), ", ")
+	}
+	if len(customers) == 0 && len(problems) == 0 && paths[0].Name == "" {
+		return nil
+	}
+	if len(customers) == 0 {
+		customers = []string{""}
+	}
+	if len(problems) == 0 {
+		problems = []string{""}
+	}
+
+	var drafts []*Hypothesis
+	for _, path := range paths {
+		for _, customer := range customers {
+			for _, problem := range problems {
+				if len(drafts) == maxDraftHypotheses {
+					return drafts
+				}
+				h := NewHypothesis(room.ID)
+				h.Customer = customer
+				h.Problem = problem
+				h.Approach = path.Name
+				h.PathID = path.ID
+				h.Competition = competition
+				h.Differentiation = differentiation
+				h.Statement = h.BuildStatement()
+				h.Source = HypothesisSourceDraft
+				drafts = append(drafts, h)
+			}
+		}
+	}
+	return drafts
+}
+
+// nonEmpty 去掉空白项
+func nonEmpty(items []string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
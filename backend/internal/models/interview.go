@@ -0,0 +1,301 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 计分卡评级（红/黄/绿）
+const (
+	RatingRed    = "red"
+	RatingYellow = "yellow"
+	RatingGreen  = "green"
+)
+
+// 计分卡维度
+const (
+	DimensionCustomerFit      = "customer_fit"      // 客户匹配度
+	DimensionProblemFit       = "problem_fit"       // 问题契合度
+	DimensionSolutionAppeal   = "solution_appeal"   // 方案吸引力
+	DimensionDifferentiation  = "differentiation"   // 差异化感知
+	DimensionOverallResonance = "overall_resonance" // 整体共鸣度
+)
+
+// ScorecardDimensions 计分卡维度，按报告中的顺序排列
+var ScorecardDimensions = []string{
+	DimensionCustomerFit,
+	DimensionProblemFit,
+	DimensionSolutionAppeal,
+	DimensionDifferentiation,
+	DimensionOverallResonance,
+}
+
+// 建议状态的判定条件：至少 minValidationScorecards 张计分卡，平均得分达到阈值
+const (
+	minValidationScorecards = 3
+	validatedScore          = 0.7
+	invalidatedScore        = 0.3
+)
+
+// Interview Design Sprint 测试中的一场访谈（可包含多位受访者）
+type Interview struct {
+	ID          string       `json:"id"`
+	RoomID      string       `json:"room_id"`
+	Title       string       `json:"title"`
+	Interviewer string       `json:"interviewer,omitempty"`
+	ConductedAt time.Time    `json:"conducted_at"`
+	Notes       string       `json:"notes,omitempty"` // 访谈记录或转写文本
+	CreatedBy   string       `json:"created_by,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Scorecards  []*Scorecard `json:"scorecards,omitempty"`
+}
+
+// Scorecard 一位受访者的计分卡，关联到房间中被检验的假设
+type Scorecard struct {
+	ID               string    `json:"id"`
+	InterviewID      string    `json:"interview_id"`
+	RoomID           string    `json:"room_id"`
+	Interviewee      string    `json:"interviewee"`
+	Segment          string    `json:"segment,omitempty"` // 受访者所属的客户群
+	HypothesisIDs    []string  `json:"hypothesis_ids"`
+	CustomerFit      string    `json:"customer_fit,omitempty"`
+	ProblemFit       string    `json:"problem_fit,omitempty"`
+	SolutionAppeal   string    `json:"solution_appeal,omitempty"`
+	Differentiation  string    `json:"differentiation,omitempty"`
+	OverallResonance string    `json:"overall_resonance,omitempty"`
+	Notes            string    `json:"notes,omitempty"`
+	CreatedBy        string    `json:"created_by,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// NewInterview 创建访谈
+func NewInterview(roomID, title string) *Interview {
+	now := time.Now()
+	return &Interview{
+		ID:          uuid.New().String(),
+		RoomID:      roomID,
+		Title:       title,
+		ConductedAt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Validate 检查访谈字段
+func (i *Interview) Validate() error {
+	i.Title = strings.TrimSpace(i.Title)
+	if i.Title == "" {
+		return fmt.Errorf("interview title is required")
+	}
+	if i.ConductedAt.IsZero() {
+		i.ConductedAt = i.CreatedAt
+	}
+	return nil
+}
+
+// NewScorecard 为访谈中的受访者创建计分卡
+func NewScorecard(interview *Interview, interviewee string) *Scorecard {
+	now := time.Now()
+	return &Scorecard{
+		ID:            uuid.New().String(),
+		InterviewID:   interview.ID,
+		RoomID:        interview.RoomID,
+		Interviewee:   interviewee,
+		HypothesisIDs: make([]string, 0),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Ratings 按维度返回评级，未评的维度为空
+func (s *Scorecard) Ratings() map[string]string {
+	return map[string]string{
+		DimensionCustomerFit:      s.CustomerFit,
+		DimensionProblemFit:       s.ProblemFit,
+		DimensionSolutionAppeal:   s.SolutionAppeal,
+		DimensionDifferentiation:  s.Differentiation,
+		DimensionOverallResonance: s.OverallResonance,
+	}
+}
+
+// Validate 检查计分卡字段：需要受访者、至少一条关联假设和至少一个维度的评级
+func (s *Scorecard) Validate() error {
+	s.Interviewee = strings.TrimSpace(s.Interviewee)
+	if s.Interviewee == "" {
+		return fmt.Errorf("interviewee is required")
+	}
+	if len(s.HypothesisIDs) == 0 {
+		return fmt.Errorf("at least one hypothesis_id is required")
+	}
+
+	rated := 0
+	for _, dimension := range ScorecardDimensions {
+		switch s.Ratings()[dimension] {
+		case "":
+		case RatingRed, RatingYellow, RatingGreen:
+			rated++
+		default:
+			return fmt.Errorf("%s must be red, yellow or green", dimension)
+		}
+	}
+	if rated == 0 {
+		return fmt.Errorf("at least one dimension must be rated")
+	}
+	return nil
+}
+
+// Score 已评维度的平均得分：绿 1、黄 0.5、红 0
+func (s *Scorecard) Score() float64 {
+	total, rated := 0.0, 0
+	for _, rating := range s.Ratings() {
+		switch rating {
+		case RatingGreen:
+			total += 1
+		case RatingYellow:
+			total += 0.5
+		case RatingRed:
+		default:
+			continue
+		}
+		rated++
+	}
+	if rated == 0 {
+		return 0
+	}
+	return total / float64(rated)
+}
+
+// Tests 计分卡是否检验了该假设
+func (s *Scorecard) Tests(hypothesisID string) bool {
+	for _, id := range s.HypothesisIDs {
+		if id == hypothesisID {
+			return true
+		}
+	}
+	return false
+}
+
+// RatingTally 一个维度的红黄绿计数
+type RatingTally struct {
+	Red    int `json:"red"`
+	Yellow int `json:"yellow"`
+	Green  int `json:"green"`
+}
+
+// ValidationTrendPoint 按访谈时间排列的验证趋势
+type ValidationTrendPoint struct {
+	InterviewID     string    `json:"interview_id"`
+	Title           string    `json:"title"`
+	ConductedAt     time.Time `json:"conducted_at"`
+	Scorecards      int       `json:"scorecards"`
+	Score           float64   `json:"score"`            // 本场访谈的平均得分
+	CumulativeScore float64   `json:"cumulative_score"` // 截至本场的平均得分
+}
+
+// HypothesisValidation 一条假设在所有访谈中的验证情况
+type HypothesisValidation struct {
+	HypothesisID    string                  `json:"hypothesis_id"`
+	Statement       string                  `json:"statement"`
+	Status          string                  `json:"status"`
+	Interviews      int                     `json:"interviews"`
+	Scorecards      int                     `json:"scorecards"`
+	Dimensions      map[string]*RatingTally `json:"dimensions"`
+	Score           float64                 `json:"score"`  // 所有计分卡的平均得分（0-1）
+	Signal          string                  `json:"signal"` // 按得分对应的红/黄/绿，无计分卡时为空
+	SuggestedStatus string                  `json:"suggested_status"`
+	Trend           []ValidationTrendPoint  `json:"trend"`
+}
+
+// BuildHypothesisValidation 汇总检验该假设的计分卡，趋势按访谈时间排序
+func BuildHypothesisValidation(h *Hypothesis, interviews []*Interview, scorecards []*Scorecard) *HypothesisValidation {
+	validation := &HypothesisValidation{
+		HypothesisID:    h.ID,
+		Statement:       h.Statement,
+		Status:          h.Status,
+		Dimensions:      make(map[string]*RatingTally, len(ScorecardDimensions)),
+		SuggestedStatus: HypothesisUntested,
+		Trend:           make([]ValidationTrendPoint, 0),
+	}
+	for _, dimension := range ScorecardDimensions {
+		validation.Dimensions[dimension] = &RatingTally{}
+	}
+
+	byInterview := make(map[string][]*Scorecard)
+	for _, scorecard := range scorecards {
+		if !scorecard.Tests(h.ID) {
+			continue
+		}
+		byInterview[scorecard.InterviewID] = append(byInterview[scorecard.InterviewID], scorecard)
+		for dimension, rating := range scorecard.Ratings() {
+			tally := validation.Dimensions[dimension]
+			switch rating {
+			case RatingRed:
+				tally.Red++
+			case RatingYellow:
+				tally.Yellow++
+			case RatingGreen:
+				tally.Green++
+			}
+		}
+	}
+
+	ordered := append([]*Interview{}, interviews...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].ConductedAt.Before(ordered[j].ConductedAt)
+	})
+
+	total := 0.0
+	for _, interview := range ordered {
+		cards := byInterview[interview.ID]
+		if len(cards) == 0 {
+			continue
+		}
+		sum := 0.0
+		for _, scorecard := range cards {
+			sum += scorecard.Score()
+		}
+		total += sum
+		validation.Interviews++
+		validation.Scorecards += len(cards)
+		validation.Trend = append(validation.Trend, ValidationTrendPoint{
+			InterviewID:     interview.ID,
+			Title:           interview.Title,
+			ConductedAt:     interview.ConductedAt,
+			Scorecards:      len(cards),
+			Score:           roundScore(sum / float64(len(cards))),
+			CumulativeScore: roundScore(total / float64(validation.Scorecards)),
+		})
+	}
+
+	if validation.Scorecards == 0 {
+		return validation
+	}
+	validation.Score = roundScore(total / float64(validation.Scorecards))
+	switch {
+	case validation.Score >= validatedScore:
+		validation.Signal = RatingGreen
+	case validation.Score <= invalidatedScore:
+		validation.Signal = RatingRed
+	default:
+		validation.Signal = RatingYellow
+	}
+	if validation.Scorecards >= minValidationScorecards {
+		switch validation.Signal {
+		case RatingGreen:
+			validation.SuggestedStatus = HypothesisValidated
+		case RatingRed:
+			validation.SuggestedStatus = HypothesisInvalidated
+		}
+	}
+	return validation
+}
+
+func roundScore(score float64) float64 {
+	return float64(int(score*100+0.5)) / 100
+}
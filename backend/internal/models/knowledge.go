@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// 知识来源类型
+const (
+	KnowledgeSourceRoom       = "room"       // 已完成的 Sprint 房间
+	KnowledgeSourceDocument   = "document"   // 上传的资料，如访谈记录、市场报告
+	KnowledgeSourceAttachment = "attachment" // 房间附件，来源 ID 为附件 ID
+)
+
+// KnowledgeDocument 知识库中的一篇文档
+type KnowledgeDocument struct {
+	ID         string    `json:"id"`
+	SourceType string    `json:"source_type"`
+	SourceID   string    `json:"source_id,omitempty"` // 来源为房间时为房间 ID
+	RoomID     string    `json:"room_id,omitempty"`   // 上传资料所属的房间，可为空
+	Title      string    `json:"title"`
+	Content    string    `json:"content,omitempty"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	ChunkCount int       `json:"chunk_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// KnowledgeChunk 文档切分后的片段，向量为空时只参与关键词检索
+type KnowledgeChunk struct {
+	ID             string    `json:"id"`
+	DocumentID     string    `json:"document_id"`
+	Ordinal        int       `json:"ordinal"`
+	Text           string    `json:"text"`
+	Embedding      []float32 `json:"-"`
+	EmbeddingModel string    `json:"embedding_model,omitempty"`
+}
@@ -0,0 +1,284 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// 2x2 矩阵象限
+const (
+	QuadrantTopRight    = "top-right"
+	QuadrantTopLeft     = "top-left"
+	QuadrantBottomRight = "bottom-right"
+	QuadrantBottomLeft  = "bottom-left"
+)
+
+// MatrixScale 矩阵坐标与因素得分的上限（0-100），中线为一半
+const MatrixScale = 100
+
+const matrixMidline = MatrixScale / 2
+
+// maxAxisSuggestions 最多返回的坐标轴建议数量
+const maxAxisSuggestions = 5
+
+// quadrantAliases 网页端与旧数据中胜利象限的写法
+var quadrantAliases = map[string]string{
+	"top-right": QuadrantTopRight, "upper-right": QuadrantTopRight, "右上": QuadrantTopRight, "右上角": QuadrantTopRight,
+	"top-left": QuadrantTopLeft, "upper-left": QuadrantTopLeft, "左上": QuadrantTopLeft, "左上角": QuadrantTopLeft,
+	"bottom-right": QuadrantBottomRight, "lower-right": QuadrantBottomRight, "右下": QuadrantBottomRight, "右下角": QuadrantBottomRight,
+	"bottom-left": QuadrantBottomLeft, "lower-left": QuadrantBottomLeft, "左下": QuadrantBottomLeft, "左下角": QuadrantBottomLeft,
+}
+
+// NormalizeQuadrant 将胜利象限的各种写法统一为 top-right 等，无法识别时返回空
+func NormalizeQuadrant(value string) string {
+	key := strings.ToLower(strings.TrimSpace(value))
+	key = strings.NewReplacer("_", "-", " ", "-").Replace(key)
+	return quadrantAliases[key]
+}
+
+// QuadrantOf 坐标所在的象限；与网页端一致，落在中线上的点算作左侧或下方
+func QuadrantOf(x, y float64) string {
+	switch {
+	case x > matrixMidline && y > matrixMidline:
+		return QuadrantTopRight
+	case y > matrixMidline:
+		return QuadrantTopLeft
+	case x > matrixMidline:
+		return QuadrantBottomRight
+	default:
+		return QuadrantBottomLeft
+	}
+}
+
+// ValidateMatrix 检查产品坐标与因素得分范围
+func ValidateMatrix(matrix Matrix2x2) error {
+	outside := func(value float64) bool { return value < 0 || value > MatrixScale }
+	for _, product := range matrix.Products {
+		if outside(product.X) || outside(product.Y) {
+			return fmt.Errorf("position of product %s must be within 0-%d", product.Name, MatrixScale)
+		}
+		for factorID, score := range product.Scores {
+			if outside(score) {
+				return fmt.Errorf("score of product %s on factor %s must be within 0-%d", product.Name, factorID, MatrixScale)
+			}
+		}
+	}
+	return nil
+}
+
+// ProductQuadrant 产品在矩阵中的象限
+type ProductQuadrant struct {
+	Name         string  `json:"name"`
+	X            float64 `json:"x"`
+	Y            float64 `json:"y"`
+	IsUs         bool    `json:"is_us"`
+	Quadrant     string  `json:"quadrant"`
+	OnMidline    bool    `json:"on_midline"`     // 落在中线上，象限归属不明确
+	DistanceToUs float64 `json:"distance_to_us"` // 竞争对手与我们的距离
+}
+
+// AxisSuggestion 一组候选坐标轴。Invert 表示该轴需要反向（高分在左或下），
+// 以使我们的产品落在右上角
+type AxisSuggestion struct {
+	XFactorID         string  `json:"x_factor_id"`
+	XFactor           string  `json:"x_factor"`
+	InvertX           bool    `json:"invert_x"`
+	YFactorID         string  `json:"y_factor_id"`
+	YFactor           string  `json:"y_factor"`
+	InvertY           bool    `json:"invert_y"`
+	Current           bool    `json:"current"`     // 与当前坐标轴相同
+	Exclusive         bool    `json:"exclusive"`   // 我们独占右上角
+	Competitors       int     `json:"competitors"` // 在两个因素上都有得分、参与比较的竞争对手数
+	NearestCompetitor string  `json:"nearest_competitor"`
+	NearestDistance   float64 `json:"nearest_distance"` // 与最近竞争对手的距离
+	MeanDistance      float64 `json:"mean_distance"`    // 与竞争对手的平均距离
+}
+
+// MatrixAnalysis 2x2 矩阵分析
+type MatrixAnalysis struct {
+	XAxis             string              `json:"x_axis"`
+	YAxis             string              `json:"y_axis"`
+	WinningQuadrant   string              `json:"winning_quadrant"` // 统一写法后的胜利象限，未设置时按方法论取右上角
+	Products          []ProductQuadrant   `json:"products"`
+	Quadrants         map[string][]string `json:"quadrants"` // 各象限中的产品名称
+	Us                string              `json:"us,omitempty"`
+	UsQuadrant        string              `json:"us_quadrant,omitempty"`
+	InWinningQuadrant bool                `json:"in_winning_quadrant"`
+	Exclusive         bool                `json:"exclusive"`   // 我们独占胜利象限（方法论中的排他性检验）
+	SharedWith        []string            `json:"shared_with"` // 同处胜利象限的竞争对手
+	NearestCompetitor string              `json:"nearest_competitor,omitempty"`
+	NearestDistance   float64             `json:"nearest_distance"`
+	AxisSuggestions   []AxisSuggestion    `json:"axis_suggestions"`
+	Warnings          []string            `json:"warnings"`
+}
+
+// BuildMatrixAnalysis 计算各产品的象限、胜利象限的排他性与最近的竞争对手，
+// 并按产品在差异化因素上的得分建议坐标轴
+func BuildMatrixAnalysis(differentiation Differentiation) *MatrixAnalysis {
+	matrix := differentiation.Matrix
+	analysis := &MatrixAnalysis{
+		XAxis:           matrix.XAxis,
+		YAxis:           matrix.YAxis,
+		WinningQuadrant: NormalizeQuadrant(matrix.WinningQuadrant),
+		Products:        make([]ProductQuadrant, 0, len(matrix.Products)),
+		Quadrants: map[string][]string{
+			QuadrantTopRight: {}, QuadrantTopLeft: {}, QuadrantBottomRight: {}, QuadrantBottomLeft: {},
+		},
+		SharedWith:      make([]string, 0),
+		AxisSuggestions: make([]AxisSuggestion, 0),
+		Warnings:        make([]string, 0),
+	}
+	if analysis.WinningQuadrant == "" {
+		if strings.TrimSpace(matrix.WinningQuadrant) != "" {
+			analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("无法识别胜利象限“%s”，按右上角分析", matrix.WinningQuadrant))
+		}
+		analysis.WinningQuadrant = QuadrantTopRight
+	}
+
+	us := -1
+	for i, product := range matrix.Products {
+		quadrant := QuadrantOf(product.X, product.Y)
+		analysis.Products = append(analysis.Products, ProductQuadrant{
+			Name:      product.Name,
+			X:         product.X,
+			Y:         product.Y,
+			IsUs:      product.IsUs,
+			Quadrant:  quadrant,
+			OnMidline: product.X == matrixMidline || product.Y == matrixMidline,
+		})
+		analysis.Quadrants[quadrant] = append(analysis.Quadrants[quadrant], product.Name)
+		if product.IsUs {
+			if us >= 0 {
+				analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("多个产品标记为我们，只分析 %s", matrix.Products[us].Name))
+				continue
+			}
+			us = i
+		}
+	}
+
+	if us < 0 {
+		if len(matrix.Products) > 0 {
+			analysis.Warnings = append(analysis.Warnings, "没有产品标记为我们")
+		}
+	} else {
+		ours := &analysis.Products[us]
+		analysis.Us = ours.Name
+		analysis.UsQuadrant = ours.Quadrant
+		analysis.InWinningQuadrant = ours.Quadrant == analysis.WinningQuadrant
+		if ours.OnMidline {
+			analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("%s 落在中线上，象限归属不明确", ours.Name))
+		}
+
+		analysis.NearestDistance = -1
+		for i := range analysis.Products {
+			product := &analysis.Products[i]
+			if product.IsUs {
+				continue
+			}
+			product.DistanceToUs = round3(math.Hypot(product.X-ours.X, product.Y-ours.Y))
+			if analysis.NearestDistance < 0 || product.DistanceToUs < analysis.NearestDistance {
+				analysis.NearestCompetitor = product.Name
+				analysis.NearestDistance = product.DistanceToUs
+			}
+			if product.Quadrant == analysis.WinningQuadrant {
+				analysis.SharedWith = append(analysis.SharedWith, product.Name)
+			}
+		}
+		if analysis.NearestDistance < 0 {
+			analysis.NearestDistance = 0
+		}
+		analysis.Exclusive = analysis.InWinningQuadrant && !ours.OnMidline && len(analysis.SharedWith) == 0
+		analysis.AxisSuggestions = suggestAxes(differentiation, us)
+	}
+
+	return analysis
+}
+
+// suggestAxes 在每一对差异化因素上比较我们与竞争对手的得分，按参与比较的竞争对手数、
+// 是否独占右上角、与最近竞争对手的距离和平均距离排序
+func suggestAxes(differentiation Differentiation, us int) []AxisSuggestion {
+	factors := append(append([]DifferentiationFactor{}, differentiation.ClassicFactors...), differentiation.CustomFactors...)
+	products := differentiation.Matrix.Products
+	ours := products[us]
+
+	var suggestions []AxisSuggestion
+	for i, xFactor := range factors {
+		for _, yFactor := range factors[i+1:] {
+			usX, okX := ours.Scores[xFactor.ID]
+			usY, okY := ours.Scores[yFactor.ID]
+			if !okX || !okY || xFactor.ID == yFactor.ID {
+				continue
+			}
+
+			// 反向坐标轴不改变距离，只决定我们是否落在右上角
+			suggestion := AxisSuggestion{
+				XFactorID: xFactor.ID,
+				XFactor:   xFactor.Name,
+				InvertX:   usX < matrixMidline,
+				YFactorID: yFactor.ID,
+				YFactor:   yFactor.Name,
+				InvertY:   usY < matrixMidline,
+			}
+			orient := func(score float64, invert bool) float64 {
+				if invert {
+					return MatrixScale - score
+				}
+				return score
+			}
+
+			exclusive := orient(usX, suggestion.InvertX) != matrixMidline && orient(usY, suggestion.InvertY) != matrixMidline
+			total := 0.0
+			for j, product := range products {
+				x, okX := product.Scores[xFactor.ID]
+				y, okY := product.Scores[yFactor.ID]
+				if j == us || product.IsUs || !okX || !okY {
+					continue
+				}
+				distance := math.Hypot(x-usX, y-usY)
+				if suggestion.Competitors == 0 || distance < suggestion.NearestDistance {
+					suggestion.NearestCompetitor = product.Name
+					suggestion.NearestDistance = distance
+				}
+				total += distance
+				suggestion.Competitors++
+				if QuadrantOf(orient(x, suggestion.InvertX), orient(y, suggestion.InvertY)) == QuadrantTopRight {
+					exclusive = false
+				}
+			}
+			if suggestion.Competitors == 0 {
+				continue
+			}
+
+			suggestion.Exclusive = exclusive
+			suggestion.MeanDistance = round3(total / float64(suggestion.Competitors))
+			suggestion.NearestDistance = round3(suggestion.NearestDistance)
+			matrix := differentiation.Matrix
+			suggestion.Current = (strings.EqualFold(xFactor.Name, matrix.XAxis) && strings.EqualFold(yFactor.Name, matrix.YAxis)) ||
+				(strings.EqualFold(xFactor.Name, matrix.YAxis) && strings.EqualFold(yFactor.Name, matrix.XAxis))
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Competitors != b.Competitors {
+			return a.Competitors > b.Competitors
+		}
+		if a.Exclusive != b.Exclusive {
+			return a.Exclusive
+		}
+		if a.NearestDistance != b.NearestDistance {
+			return a.NearestDistance > b.NearestDistance
+		}
+		return a.MeanDistance > b.MeanDistance
+	})
+	if len(suggestions) > maxAxisSuggestions {
+		suggestions = suggestions[:maxAxisSuggestions]
+	}
+	if suggestions == nil {
+		suggestions = make([]AxisSuggestion, 0)
+	}
+	return suggestions
+}
@@ -0,0 +1,291 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Sprint 阶段，即 Room.Status：foundation → differentiation → approach → completed
+const (
+	PhaseFoundation      = "foundation"
+	PhaseDifferentiation = "differentiation"
+	PhaseApproach        = "approach"
+	PhaseCompleted       = "completed"
+)
+
+// phaseOrder 阶段顺序。只能前进或后退一个阶段，前进时需要满足当前阶段的退出条件
+var phaseOrder = []string{PhaseFoundation, PhaseDifferentiation, PhaseApproach, PhaseCompleted}
+
+// 退出条件的检查项
+const (
+	GateCustomers         = "customers"          // 目标客户数
+	GateProblems          = "problems"           // 客户问题数
+	GateCompetition       = "competition"        // 竞争对手数
+	GateAdvantages        = "advantages"         // 优势数
+	GateFactors           = "factors"            // 差异化因素数（经典与自定义）
+	GatePrinciples        = "principles"         // 项目原则数
+	GateMatrixAxes        = "matrix_axes"        // 2x2 矩阵两条轴都已命名
+	GateUsProduct         = "us_product"         // 矩阵中标出了我们的产品
+	GateExclusiveQuadrant = "exclusive_quadrant" // 我们独占胜利象限
+	GatePaths             = "paths"              // 执行路径数
+	GateMagicLenses       = "magic_lenses"       // 魔术镜头数
+	GateSelectedPath      = "selected_path"      // 已选定一条存在的路径
+	GateReasoning         = "reasoning"          // 已填写选择理由
+)
+
+// gateCountChecks 计数类检查项的说明，可以设置 min
+var gateCountChecks = map[string]string{
+	GateCustomers:   "目标客户",
+	GateProblems:    "客户问题",
+	GateCompetition: "竞争对手",
+	GateAdvantages:  "优势",
+	GateFactors:     "差异化因素",
+	GatePrinciples:  "项目原则",
+	GatePaths:       "执行路径",
+	GateMagicLenses: "魔术镜头",
+}
+
+// gateFlagChecks 是否类检查项的说明
+var gateFlagChecks = map[string]string{
+	GateMatrixAxes:        "2x2 矩阵的两条轴都已命名",
+	GateUsProduct:         "2x2 矩阵中标出了我们的产品",
+	GateExclusiveQuadrant: "我们独占 2x2 矩阵的胜利象限",
+	GateSelectedPath:      "已选定执行路径",
+	GateReasoning:         "已填写选择路径的理由",
+}
+
+// GateCriterion 一条退出条件；计数类检查项的 min 默认为 1
+type GateCriterion struct {
+	Check string `json:"check"`
+	Min   int    `json:"min,omitempty"`
+}
+
+// PhaseGates 每个阶段的退出条件，键为阶段
+type PhaseGates map[string][]GateCriterion
+
+// DefaultPhaseGates 默认的退出条件
+func DefaultPhaseGates() PhaseGates {
+	return PhaseGates{
+		PhaseFoundation: {
+			{Check: GateCustomers, Min: 1},
+			{Check: GateProblems, Min: 1},
+		},
+		PhaseDifferentiation: {
+			{Check: GateMatrixAxes},
+			{Check: GateUsProduct},
+		},
+		PhaseApproach: {
+			{Check: GateSelectedPath},
+			{Check: GateReasoning},
+		},
+	}
+}
+
+// ParsePhaseGates 解析 JSON 配置，例如 {"foundation":[{"check":"customers","min":2}]}。
+// 配置中出现的阶段替换默认条件，未出现的阶段保留默认条件，空列表表示不设条件
+func ParsePhaseGates(data []byte) (PhaseGates, error) {
+	var overrides PhaseGates
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse phase gates: %w", err)
+	}
+
+	gates := DefaultPhaseGates()
+	for phase, criteria := range overrides {
+		if phaseIndex(phase) < 0 || phase == PhaseCompleted {
+			return nil, fmt.Errorf("phase gates: unknown phase %q", phase)
+		}
+		for i, criterion := range criteria {
+			if err := criterion.validate(); err != nil {
+				return nil, fmt.Errorf("phase gates: %s[%d]: %w", phase, i, err)
+			}
+		}
+		gates[phase] = criteria
+	}
+	return gates, nil
+}
+
+func (g GateCriterion) validate() error {
+	if _, ok := gateCountChecks[g.Check]; ok {
+		if g.Min < 0 {
+			return fmt.Errorf("min must not be negative")
+		}
+		return nil
+	}
+	if _, ok := gateFlagChecks[g.Check]; ok {
+		if g.Min != 0 {
+			return fmt.Errorf("min does not apply to %s", g.Check)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown check %q", g.Check)
+}
+
+// GateCheck 一条退出条件的检查结果
+type GateCheck struct {
+	Check       string `json:"check"`
+	Description string `json:"description"`
+	Passed      bool   `json:"passed"`
+	Actual      int    `json:"actual"`             // 计数类为实际数量，是否类为 0 或 1
+	Required    int    `json:"required,omitempty"` // 计数类要求的最少数量
+}
+
+// PhaseGateResult 阶段变更的检查结果
+type PhaseGateResult struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Forward bool        `json:"forward"` // 前进时检查 From 阶段的退出条件，后退不检查
+	Passed  bool        `json:"passed"`
+	Checks  []GateCheck `json:"checks"`
+}
+
+// Unmet 未通过的检查项
+func (r *PhaseGateResult) Unmet() []GateCheck {
+	unmet := make([]GateCheck, 0)
+	for _, check := range r.Checks {
+		if !check.Passed {
+			unmet = append(unmet, check)
+		}
+	}
+	return unmet
+}
+
+// Failed 未通过的检查项说明
+func (r *PhaseGateResult) Failed() []string {
+	unmet := r.Unmet()
+	failed := make([]string, 0, len(unmet))
+	for _, check := range unmet {
+		failed = append(failed, check.Description)
+	}
+	return failed
+}
+
+// IsPhase 是否为支持的阶段
+func IsPhase(phase string) bool {
+	return phaseIndex(phase) >= 0
+}
+
+func phaseIndex(phase string) int {
+	for i, p := range phaseOrder {
+		if p == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+// CheckPhaseTransition 检查房间能否从当前阶段变更到 to。非法的变更返回错误；
+// 合法的变更返回检查结果，结果未通过时需要主持人强制推进
+func (r *Room) CheckPhaseTransition(to string, gates PhaseGates) (*PhaseGateResult, error) {
+	from := r.Status
+	if !IsPhase(to) {
+		return nil, fmt.Errorf("unknown phase: %s", to)
+	}
+	if from == to {
+		return nil, fmt.Errorf("room is already in phase %s", to)
+	}
+	step := phaseIndex(to) - phaseIndex(from)
+	if phaseIndex(from) < 0 || (step != 1 && step != -1) {
+		return nil, fmt.Errorf("cannot move room from %s to %s", from, to)
+	}
+
+	result := &PhaseGateResult{From: from, To: to, Forward: step == 1, Passed: true, Checks: make([]GateCheck, 0)}
+	if !result.Forward {
+		return result, nil
+	}
+	for _, criterion := range gates[from] {
+		check := r.evaluateGate(criterion)
+		result.Passed = result.Passed && check.Passed
+		result.Checks = append(result.Checks, check)
+	}
+	return result, nil
+}
+
+// evaluateGate 检查一条退出条件
+func (r *Room) evaluateGate(criterion GateCriterion) GateCheck {
+	if label, ok := gateCountChecks[criterion.Check]; ok {
+		required := criterion.Min
+		if required == 0 {
+			required = 1
+		}
+		actual := r.gateCount(criterion.Check)
+		return GateCheck{
+			Check:       criterion.Check,
+			Description: fmt.Sprintf("至少 %d 个%s", required, label),
+			Passed:      actual >= required,
+			Actual:      actual,
+			Required:    required,
+		}
+	}
+
+	passed := r.gateFlag(criterion.Check)
+	check := GateCheck{Check: criterion.Check, Description: gateFlagChecks[criterion.Check], Passed: passed}
+	if passed {
+		check.Actual = 1
+	}
+	return check
+}
+
+func (r *Room) gateCount(check string) int {
+	switch check {
+	case GateCustomers:
+		return countCards(r.Foundation.Customers)
+	case GateProblems:
+		return countCards(r.Foundation.Problems)
+	case GateCompetition:
+		return countCards(r.Foundation.Competition)
+	case GateAdvantages:
+		return countCards(r.Foundation.Advantages)
+	case GateFactors:
+		return len(r.Differentiation.ClassicFactors) + len(r.Differentiation.CustomFactors)
+	case GatePrinciples:
+		count := 0
+		for _, principle := range r.Differentiation.Principles {
+			if strings.TrimSpace(principle) != "" {
+				count++
+			}
+		}
+		return count
+	case GatePaths:
+		return len(r.Approach.Paths)
+	case GateMagicLenses:
+		return len(r.Approach.MagicLenses)
+	}
+	return 0
+}
+
+func (r *Room) gateFlag(check string) bool {
+	switch check {
+	case GateMatrixAxes:
+		matrix := r.Differentiation.Matrix
+		return strings.TrimSpace(matrix.XAxis) != "" && strings.TrimSpace(matrix.YAxis) != ""
+	case GateUsProduct:
+		for _, product := range r.Differentiation.Matrix.Products {
+			if product.IsUs {
+				return true
+			}
+		}
+	case GateExclusiveQuadrant:
+		return BuildMatrixAnalysis(r.Differentiation).Exclusive
+	case GateSelectedPath:
+		for _, path := range r.Approach.Paths {
+			if path.ID != "" && path.ID == r.Approach.SelectedPath {
+				return true
+			}
+		}
+	case GateReasoning:
+		return strings.TrimSpace(r.Approach.Reasoning) != ""
+	}
+	return false
+}
+
+// countCards 有内容的卡片数
+func countCards(cards []Card) int {
+	count := 0
+	for _, card := range cards {
+		if strings.TrimSpace(card.Text) != "" {
+			count++
+		}
+	}
+	return count
+}
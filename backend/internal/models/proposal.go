@@ -0,0 +1,159 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 提议类型
+const (
+	ProposalAddCustomer     = "add_customer"
+	ProposalAddProblem      = "add_problem"
+	ProposalAddFactor       = "add_factor"
+	ProposalPositionProduct = "position_product"
+	ProposalAddPath         = "add_path"
+)
+
+// 提议状态
+const (
+	ProposalPending  = "pending"
+	ProposalAccepted = "accepted"
+	ProposalRejected = "rejected"
+)
+
+// ProposalPayload 提议内容，按类型填写对应字段
+type ProposalPayload struct {
+	Text    string                 `json:"text,omitempty"`    // add_customer / add_problem
+	Factor  *DifferentiationFactor `json:"factor,omitempty"`  // add_factor
+	Product *ProductPosition       `json:"product,omitempty"` // position_product
+	Path    *Path                  `json:"path,omitempty"`    // add_path
+}
+
+// Proposal Agent 提出的、待主持人确认的结构化修改
+type Proposal struct {
+	ID          string     `json:"id"`
+	RoomID      string     `json:"room_id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	AgentName   string     `json:"agent_name"`
+	RequestedBy string     `json:"requested_by"` // 触发该 Agent 请求的用户
+	Rationale   string     `json:"rationale"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ProposalPayload
+}
+
+// NewProposal 创建待处理的提议
+func NewProposal(roomID, proposalType, agentName string) *Proposal {
+	return &Proposal{
+		ID:        uuid.New().String(),
+		RoomID:    roomID,
+		Type:      proposalType,
+		Status:    ProposalPending,
+		AgentName: agentName,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate 检查提议类型与内容是否匹配
+func (p *Proposal) Validate() error {
+	switch p.Type {
+	case ProposalAddCustomer, ProposalAddProblem:
+		if strings.TrimSpace(p.Text) == "" {
+			return fmt.Errorf("%s proposal requires text", p.Type)
+		}
+	case ProposalAddFactor:
+		if p.Factor == nil || strings.TrimSpace(p.Factor.Name) == "" {
+			return fmt.Errorf("%s proposal requires a factor name", p.Type)
+		}
+	case ProposalPositionProduct:
+		if p.Product == nil || strings.TrimSpace(p.Product.Name) == "" {
+			return fmt.Errorf("%s proposal requires a product name", p.Type)
+		}
+		if p.Product.X < 0 || p.Product.X > 100 || p.Product.Y < 0 || p.Product.Y > 100 {
+			return fmt.Errorf("%s proposal coordinates must be within 0-100", p.Type)
+		}
+	case ProposalAddPath:
+		if p.Path == nil || strings.TrimSpace(p.Path.Name) == "" {
+			return fmt.Errorf("%s proposal requires a path name", p.Type)
+		}
+	default:
+		return fmt.Errorf("unknown proposal type: %s", p.Type)
+	}
+	return nil
+}
+
+// Phase 返回提议所修改的阶段
+func (p *Proposal) Phase() string {
+	switch p.Type {
+	case ProposalAddCustomer, ProposalAddProblem:
+		return "foundation"
+	case ProposalAddFactor, ProposalPositionProduct:
+		return "differentiation"
+	case ProposalAddPath:
+		return "approach"
+	}
+	return ""
+}
+
+// ApplyTo 将提议应用到房间，返回被修改的阶段
+func (p *Proposal) ApplyTo(room *Room) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	switch p.Type {
+	case ProposalAddCustomer:
+		room.Foundation.Customers = appendCard(room.Foundation.Customers, CardCustomer, p.Text, p.AgentName)
+	case ProposalAddProblem:
+		room.Foundation.Problems = appendCard(room.Foundation.Problems, CardProblem, p.Text, p.AgentName)
+	case ProposalAddFactor:
+		factor := *p.Factor
+		if factor.ID == "" {
+			factor.ID = uuid.New().String()
+		}
+		room.Differentiation.CustomFactors = append(room.Differentiation.CustomFactors, factor)
+	case ProposalPositionProduct:
+		// 已存在同名产品时更新其位置
+		products := room.Differentiation.Matrix.Products
+		for i := range products {
+			if strings.EqualFold(products[i].Name, p.Product.Name) {
+				products[i].X = p.Product.X
+				products[i].Y = p.Product.Y
+				return p.Phase(), nil
+			}
+		}
+		room.Differentiation.Matrix.Products = append(products, *p.Product)
+	case ProposalAddPath:
+		path := *p.Path
+		if path.ID == "" {
+			path.ID = uuid.New().String()
+		}
+		if path.Pros == nil {
+			path.Pros = make([]string, 0)
+		}
+		if path.Cons == nil {
+			path.Cons = make([]string, 0)
+		}
+		room.Approach.Paths = append(room.Approach.Paths, path)
+	}
+
+	return p.Phase(), nil
+}
+
+// appendCard 追加卡片，已有同名卡片时保持不变
+func appendCard(cards []Card, cardType, text, author string) []Card {
+	text = strings.TrimSpace(text)
+	for _, existing := range cards {
+		if strings.EqualFold(existing.Text, text) {
+			return cards
+		}
+	}
+	card := NewCard(cardType, text, author)
+	card.Order = len(cards)
+	return append(cards, card)
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 风险状态
+const (
+	RiskOpen       = "open"
+	RiskMitigating = "mitigating"
+	RiskAccepted   = "accepted" // 接受风险，不再处理
+	RiskClosed     = "closed"
+)
+
+// 风险来源
+const (
+	RiskSourceManual = "manual"
+	RiskSourceAgent  = "agent"
+)
+
+// 风险等级，由得分（可能性 × 影响，1-25）划分
+const (
+	RiskLevelLow      = "low"
+	RiskLevelMedium   = "medium"
+	RiskLevelHigh     = "high"
+	RiskLevelCritical = "critical"
+)
+
+// RiskScaleMax 可能性与影响的最高等级（1-5）
+const RiskScaleMax = 5
+
+// Risk 房间风险登记表中的一条风险
+type Risk struct {
+	ID          string    `json:"id"`
+	RoomID      string    `json:"room_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Category    string    `json:"category"`
+	Likelihood  int       `json:"likelihood"` // 1-5
+	Impact      int       `json:"impact"`     // 1-5
+	Score       int       `json:"score"`      // 可能性 × 影响
+	Level       string    `json:"level"`
+	Owner       string    `json:"owner,omitempty"`
+	Mitigation  string    `json:"mitigation,omitempty"`
+	Status      string    `json:"status"`
+	PathID      string    `json:"path_id,omitempty"`    // 受威胁的执行路径
+	Assumption  string    `json:"assumption,omitempty"` // 受威胁的假设
+	Source      string    `json:"source"`
+	AgentName   string    `json:"agent_name,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewRisk 创建待处理的风险
+func NewRisk(roomID, title string) *Risk {
+	now := time.Now()
+	return &Risk{
+		ID:        uuid.New().String(),
+		RoomID:    roomID,
+		Title:     title,
+		Status:    RiskOpen,
+		Source:    RiskSourceManual,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate 检查风险字段并计算得分与等级
+func (r *Risk) Validate() error {
+	r.Title = strings.TrimSpace(r.Title)
+	if r.Title == "" {
+		return fmt.Errorf("risk title is required")
+	}
+	if r.Likelihood < 1 || r.Likelihood > RiskScaleMax {
+		return fmt.Errorf("likelihood must be within 1-%d", RiskScaleMax)
+	}
+	if r.Impact < 1 || r.Impact > RiskScaleMax {
+		return fmt.Errorf("impact must be within 1-%d", RiskScaleMax)
+	}
+	switch r.Status {
+	case "":
+		r.Status = RiskOpen
+	case RiskOpen, RiskMitigating, RiskAccepted, RiskClosed:
+	default:
+		return fmt.Errorf("invalid risk status: %s", r.Status)
+	}
+
+	r.Score = r.Likelihood * r.Impact
+	r.Level = RiskLevelFor(r.Score)
+	return nil
+}
+
+// IsActive 风险是否仍需关注（未关闭也未接受）
+func (r *Risk) IsActive() bool {
+	return r.Status == RiskOpen || r.Status == RiskMitigating
+}
+
+// RiskLevelFor 按得分划分风险等级
+func RiskLevelFor(score int) string {
+	switch {
+	case score >= 15:
+		return RiskLevelCritical
+	case score >= 10:
+		return RiskLevelHigh
+	case score >= 5:
+		return RiskLevelMedium
+	}
+	return RiskLevelLow
+}
+
+// RiskHeatmap 风险热力图：按可能性与影响聚合的风险数量
+type RiskHeatmap struct {
+	// Cells[likelihood-1][impact-1] 为该格的风险数量
+	Cells      [RiskScaleMax][RiskScaleMax]int `json:"cells"`
+	RiskIDs    map[string][]string             `json:"risk_ids"` // "likelihood,impact" -> 风险 ID
+	ByLevel    map[string]int                  `json:"by_level"`
+	ByCategory map[string]int                  `json:"by_category"`
+	Total      int                             `json:"total"`
+	MaxScore   int                             `json:"max_score"`
+}
+
+// BuildRiskHeatmap 聚合风险；includeInactive 为 false 时跳过已关闭和已接受的风险
+func BuildRiskHeatmap(risks []*Risk, includeInactive bool) *RiskHeatmap {
+	heatmap := &RiskHeatmap{
+		RiskIDs: make(map[string][]string),
+		ByLevel: map[string]int{
+			RiskLevelLow:      0,
+			RiskLevelMedium:   0,
+			RiskLevelHigh:     0,
+			RiskLevelCritical: 0,
+		},
+		ByCategory: make(map[string]int),
+	}
+
+	for _, risk := range risks {
+		if !includeInactive && !risk.IsActive() {
+			continue
+		}
+		if risk.Likelihood < 1 || risk.Likelihood > RiskScaleMax || risk.Impact < 1 || risk.Impact > RiskScaleMax {
+			continue
+		}
+
+		heatmap.Cells[risk.Likelihood-1][risk.Impact-1]++
+		key := fmt.Sprintf("%d,%d", risk.Likelihood, risk.Impact)
+		heatmap.RiskIDs[key] = append(heatmap.RiskIDs[key], risk.ID)
+		heatmap.ByLevel[RiskLevelFor(risk.Score)]++
+		category := risk.Category
+		if category == "" {
+			category = "未分类"
+		}
+		heatmap.ByCategory[category]++
+		heatmap.Total++
+		if risk.Score > heatmap.MaxScore {
+			heatmap.MaxScore = risk.Score
+		}
+	}
+
+	return heatmap
+}
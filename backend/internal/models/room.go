@@ -16,14 +16,15 @@ type Room struct {
 	Differentiation Differentiation `json:"differentiation"`
 	Approach    Approach  `json:"approach"`
 	Status      string    `json:"status"` // "foundation", "differentiation", "approach", "completed"
+	Settings    RoomSettings `json:"settings"`
 }
 
-// Foundation 第一阶段：基础信息
+// Foundation 第一阶段：基础信息，每一项都是一张卡片
 type Foundation struct {
-	Customers   []string `json:"customers"`
-	Problems    []string `json:"problems"`
-	Competition []string `json:"competition"`
-	Advantages  []string `json:"advantages"`
+	Customers   []Card `json:"customers"`
+	Problems    []Card `json:"problems"`
+	Competition []Card `json:"competition"`
+	Advantages  []Card `json:"advantages"`
 }
 
 // Differentiation 第二阶段：差异化
@@ -40,6 +41,7 @@ type DifferentiationFactor struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Weight      int    `json:"weight"`
+	Merged      []MergedItem `json:"merged,omitempty"` // 合并到该因素的重复因素
 }
 
 // Matrix2x2 2x2 分析矩阵
@@ -56,6 +58,7 @@ type ProductPosition struct {
 	X    float64 `json:"x"`
 	Y    float64 `json:"y"`
 	IsUs bool    `json:"is_us"`
+	Scores map[string]float64 `json:"scores,omitempty"` // 在各差异化因素上的得分（0-100），键为因素 ID
 }
 
 // Approach 第三阶段：方法
@@ -79,6 +82,7 @@ type Path struct {
 type MagicLens struct {
 	Name        string             `json:"name"`
 	Description string             `json:"description"`
+	Weight      *float64           `json:"weight,omitempty"` // 决策矩阵中的权重，未设置时为 1
 	Evaluations []PathEvaluation   `json:"evaluations"`
 }
 
@@ -99,10 +103,10 @@ func NewRoom(name, createdBy string) *Room {
 		UpdatedAt: time.Now(),
 		Status:    "foundation",
 		Foundation: Foundation{
-			Customers:   make([]string, 0),
-			Problems:    make([]string, 0),
-			Competition: make([]string, 0),
-			Advantages:  make([]string, 0),
+			Customers:   make([]Card, 0),
+			Problems:    make([]Card, 0),
+			Competition: make([]Card, 0),
+			Advantages:  make([]Card, 0),
 		},
 		Differentiation: Differentiation{
 			ClassicFactors: make([]DifferentiationFactor, 0),
@@ -119,5 +123,8 @@ func NewRoom(name, createdBy string) *Room {
 			Paths:       make([]Path, 0),
 			MagicLenses: make([]MagicLens, 0),
 		},
+		Settings: RoomSettings{
+			Agenda: make([]AgendaItem, 0),
+		},
 	}
 }
\ No newline at end of file
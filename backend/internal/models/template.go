@@ -0,0 +1,211 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AgendaItem 议程中的一个时间盒
+type AgendaItem struct {
+	Phase   string `json:"phase"` // 所属阶段：foundation/differentiation/approach
+	Title   string `json:"title"`
+	Minutes int    `json:"minutes"`
+}
+
+// AgentSettings 房间的 Agent 设置
+type AgentSettings struct {
+	Instructions string  `json:"instructions,omitempty"` // 行业打法说明，随房间上下文提供给 Agent
+	BudgetUSD    float64 `json:"budget_usd,omitempty"`   // 房间的 LLM 预算，0 表示使用默认预算
+}
+
+// RoomSettings 房间设置
+type RoomSettings struct {
+	Agenda     []AgendaItem  `json:"agenda"`
+	Agents     AgentSettings `json:"agents"`
+	TemplateID string        `json:"template_id,omitempty"` // 创建房间所用的模板
+	ClonedFrom string        `json:"cloned_from,omitempty"` // 克隆来源房间
+}
+
+// Validate 检查议程与 Agent 设置
+func (s *RoomSettings) Validate() error {
+	if s.Agenda == nil {
+		s.Agenda = make([]AgendaItem, 0)
+	}
+	for i := range s.Agenda {
+		item := &s.Agenda[i]
+		item.Title = strings.TrimSpace(item.Title)
+		if item.Title == "" {
+			return fmt.Errorf("agenda item %d: title is required", i+1)
+		}
+		if !IsPhase(item.Phase) || item.Phase == PhaseCompleted {
+			return fmt.Errorf("agenda item %d: invalid phase: %s", i+1, item.Phase)
+		}
+		if item.Minutes < 1 {
+			return fmt.Errorf("agenda item %d: minutes must be positive", i+1)
+		}
+	}
+	if s.Agents.BudgetUSD < 0 {
+		return fmt.Errorf("budget_usd must not be negative")
+	}
+	s.Agents.Instructions = strings.TrimSpace(s.Agents.Instructions)
+	return nil
+}
+
+// PhaseAgenda 某一阶段的议程
+func (s *RoomSettings) PhaseAgenda(phase string) []AgendaItem {
+	items := make([]AgendaItem, 0)
+	for _, item := range s.Agenda {
+		if item.Phase == phase {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// RoomTemplate 房间模板：行业打法中预先准备的经典差异化因素、魔术镜头、议程与 Agent 设置
+type RoomTemplate struct {
+	ID             string                  `json:"id"`
+	Name           string                  `json:"name"`
+	Description    string                  `json:"description"`
+	Industry       string                  `json:"industry"`
+	ClassicFactors []DifferentiationFactor `json:"classic_factors"`
+	MagicLenses    []MagicLens             `json:"magic_lenses"`
+	Agenda         []AgendaItem            `json:"agenda"`
+	Agents         AgentSettings           `json:"agents"`
+	CreatedBy      string                  `json:"created_by"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// NewRoomTemplate 创建模板
+func NewRoomTemplate(name, createdBy string) *RoomTemplate {
+	now := time.Now()
+	return &RoomTemplate{
+		ID:             uuid.New().String(),
+		Name:           name,
+		ClassicFactors: make([]DifferentiationFactor, 0),
+		MagicLenses:    make([]MagicLens, 0),
+		Agenda:         make([]AgendaItem, 0),
+		CreatedBy:      createdBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// Validate 检查模板字段；没有 ID 的因素补全 ID，魔术镜头不保留评分
+func (t *RoomTemplate) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return fmt.Errorf("template name is required")
+	}
+
+	if t.ClassicFactors == nil {
+		t.ClassicFactors = make([]DifferentiationFactor, 0)
+	}
+	names := make(map[string]bool)
+	for i := range t.ClassicFactors {
+		factor := &t.ClassicFactors[i]
+		factor.Name = strings.TrimSpace(factor.Name)
+		if factor.Name == "" {
+			return fmt.Errorf("factor %d: name is required", i+1)
+		}
+		key := strings.ToLower(factor.Name)
+		if names[key] {
+			return fmt.Errorf("duplicate factor: %s", factor.Name)
+		}
+		names[key] = true
+		if factor.ID == "" {
+			factor.ID = uuid.New().String()
+		}
+		factor.Merged = nil
+	}
+
+	if t.MagicLenses == nil {
+		t.MagicLenses = make([]MagicLens, 0)
+	}
+	names = make(map[string]bool)
+	for i := range t.MagicLenses {
+		lens := &t.MagicLenses[i]
+		lens.Name = strings.TrimSpace(lens.Name)
+		if lens.Name == "" {
+			return fmt.Errorf("magic lens %d: name is required", i+1)
+		}
+		key := strings.ToLower(lens.Name)
+		if names[key] {
+			return fmt.Errorf("duplicate magic lens: %s", lens.Name)
+		}
+		names[key] = true
+		if lens.Weight != nil && *lens.Weight < 0 {
+			return fmt.Errorf("magic lens %s: weight must not be negative", lens.Name)
+		}
+		lens.Evaluations = make([]PathEvaluation, 0)
+	}
+
+	settings := RoomSettings{Agenda: t.Agenda, Agents: t.Agents}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	t.Agenda, t.Agents = settings.Agenda, settings.Agents
+	return nil
+}
+
+// NewRoom 按模板创建房间
+func (t *RoomTemplate) NewRoom(name, createdBy string) *Room {
+	room := NewRoom(name, createdBy)
+	room.Differentiation.ClassicFactors = append(make([]DifferentiationFactor, 0, len(t.ClassicFactors)), t.ClassicFactors...)
+	for _, lens := range t.MagicLenses {
+		lens.Evaluations = make([]PathEvaluation, 0)
+		room.Approach.MagicLenses = append(room.Approach.MagicLenses, lens)
+	}
+	room.Settings = RoomSettings{
+		Agenda:     append(make([]AgendaItem, 0, len(t.Agenda)), t.Agenda...),
+		Agents:     t.Agents,
+		TemplateID: t.ID,
+	}
+	return room
+}
+
+// clonablePhases 克隆时可以保留的阶段
+var clonablePhases = []string{PhaseFoundation, PhaseDifferentiation, PhaseApproach}
+
+// Clone 将房间克隆为新房间：只保留 phases 中阶段的数据与房间设置，投票、成员与其他记录不会带到新房间。
+// 新房间从第一个未保留的阶段开始，全部保留时从 approach 开始
+func (r *Room) Clone(name, createdBy string, phases []string) (*Room, error) {
+	keep := make(map[string]bool, len(phases))
+	for _, phase := range phases {
+		valid := false
+		for _, p := range clonablePhases {
+			valid = valid || p == phase
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid phase to keep: %s", phase)
+		}
+		keep[phase] = true
+	}
+
+	clone := NewRoom(name, createdBy)
+	if keep[PhaseFoundation] {
+		clone.Foundation = r.Foundation
+	}
+	if keep[PhaseDifferentiation] {
+		clone.Differentiation = r.Differentiation
+	}
+	if keep[PhaseApproach] {
+		clone.Approach = r.Approach
+	}
+	clone.Settings = r.Settings
+	clone.Settings.Agenda = append(make([]AgendaItem, 0, len(r.Settings.Agenda)), r.Settings.Agenda...)
+	clone.Settings.ClonedFrom = r.ID
+
+	clone.Status = PhaseApproach
+	for _, phase := range clonablePhases {
+		if !keep[phase] {
+			clone.Status = phase
+			break
+		}
+	}
+	return clone, nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// LLMUsage 记录一次 LLM 调用的 token 用量及费用
+type LLMUsage struct {
+	ID               string    `json:"id"`
+	RoomID           string    `json:"room_id"`
+	UserID           string    `json:"user_id"`
+	AgentName        string    `json:"agent_name"`
+	SessionID        string    `json:"session_id"`
+	Model            string    `json:"model"`
+	CallType         string    `json:"call_type"` // "thought", "reflection", "final_answer", ...
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	Unpriced         bool      `json:"unpriced"` // 模型不在价格表中，费用为 0 或按 "*" 兜底价格计算
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// UsageSummary 按某个维度（房间、日期、Agent 等）汇总的用量
+type UsageSummary struct {
+	Key              string  `json:"key"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	UnpricedCalls    int     `json:"unpriced_calls"` // 未定价模型的调用次数，费用可能被低估
+}
+
+// 预算作用域
+const (
+	BudgetScopeRoom      = "room"
+	BudgetScopeWorkspace = "workspace"
+)
@@ -12,11 +12,14 @@ type Vote struct {
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Type        string    `json:"type"` // "note_and_vote", "ranking", "single_choice", "multiple_choice"
+	Settings    VoteSettings `json:"settings"`
 	Options     []VoteOption `json:"options"`
-	Status      string    `json:"status"` // "active", "completed", "cancelled"
+	Status      string    `json:"status"` // "draft", "open", "closed", "revealed", "decided"
 	CreatedBy   string    `json:"created_by"`
 	CreatedAt   time.Time `json:"created_at"`
 	EndedAt     *time.Time `json:"ended_at,omitempty"`
+	Decision    *VoteDecision `json:"decision,omitempty"`
+	Ballots     int       `json:"ballots"` // 已投票人数，由 PublicView 填写
 }
 
 // VoteOption 投票选项
@@ -31,7 +34,8 @@ type VoteOption struct {
 type UserVote struct {
 	UserID    string    `json:"user_id"`
 	UserName  string    `json:"user_name"`
-	Weight    int       `json:"weight"` // 投票权重
+	Weight    int       `json:"weight"` // 投票权重：圆点投票中为点数，排序投票中为 Borda 分
+	Rank      int       `json:"rank,omitempty"` // 排序投票中的偏好位次，从 1 开始
 	Comment   string    `json:"comment,omitempty"`
 	VotedAt   time.Time `json:"voted_at"`
 }
@@ -45,7 +49,7 @@ func NewVote(roomID, title, description, voteType, createdBy string) *Vote {
 		Description: description,
 		Type:        voteType,
 		Options:     make([]VoteOption, 0),
-		Status:      "active",
+		Status:      VoteOpen,
 		CreatedBy:   createdBy,
 		CreatedAt:   time.Now(),
 	}
@@ -62,35 +66,13 @@ func (v *Vote) AddOption(text, description string) {
 	v.Options = append(v.Options, option)
 }
 
-// AddVote 添加用户投票
-func (v *Vote) AddUserVote(optionID, userID, userName string, weight int, comment string) error {
-	for i := range v.Options {
-		if v.Options[i].ID == optionID {
-			userVote := UserVote{
-				UserID:   userID,
-				UserName: userName,
-				Weight:   weight,
-				Comment:  comment,
-				VotedAt:  time.Now(),
-			}
-			v.Options[i].Votes = append(v.Options[i].Votes, userVote)
-			return nil
-		}
-	}
-	return nil
-}
-
-// GetResults 获取投票结果
+// GetResults 获取投票结果：各选项按投票类型计算的得分（票数、点数或 Borda 分）
 func (v *Vote) GetResults() map[string]int {
 	results := make(map[string]int)
 	
-	for _, option := range v.Options {
-		totalScore := 0
-		for _, vote := range option.Votes {
-			totalScore += vote.Weight
-		}
-		results[option.ID] = totalScore
+	for _, option := range v.Tally().Options {
+		results[option.OptionID] = option.Score
 	}
 	
 	return results
-}
\ No newline at end of file
+}
@@ -0,0 +1,323 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 投票类型
+const (
+	VoteNoteAndVote    = "note_and_vote"   // 圆点投票：每人有固定点数，可分配到多个选项
+	VoteSingleChoice   = "single_choice"   // 单选：每人一票
+	VoteMultipleChoice = "multiple_choice" // 多选：每人最多选择 max_selections 个选项
+	VoteRanking        = "ranking"         // 排序：每人按偏好排列选项
+)
+
+// 排序投票决定名次的计票方法
+const (
+	RankingBorda         = "borda"
+	RankingInstantRunoff = "instant_runoff"
+)
+
+// DefaultDotBudget 圆点投票中每人默认的点数
+const DefaultDotBudget = 3
+
+// VoteSettings 投票规则
+type VoteSettings struct {
+	MaxSelections int    `json:"max_selections,omitempty"` // 多选投票最多选择的选项数，0 表示不限
+	DotBudget     int    `json:"dot_budget,omitempty"`     // 圆点投票中每人的点数
+	RankingMethod string `json:"ranking_method,omitempty"` // 排序投票的计票方法：borda/instant_runoff
+	Anonymous     bool   `json:"anonymous,omitempty"`      // 匿名投票：投票人只有审计可以看到
+	Decider       string `json:"decider,omitempty"`        // 决策者的用户 ID，未指定时为主持人
+}
+
+// IsVoteType 是否为支持的投票类型
+func IsVoteType(voteType string) bool {
+	switch voteType {
+	case VoteNoteAndVote, VoteSingleChoice, VoteMultipleChoice, VoteRanking:
+		return true
+	}
+	return false
+}
+
+// Mode 投票的计票方式。旧版网页端把投票主题（customers 等）写入了类型，这些投票按圆点投票计票
+func (v *Vote) Mode() string {
+	if IsVoteType(v.Type) {
+		return v.Type
+	}
+	return VoteNoteAndVote
+}
+
+// Validate 检查投票类型、选项与规则
+func (v *Vote) Validate() error {
+	if !IsVoteType(v.Type) {
+		return fmt.Errorf("invalid vote type: %s", v.Type)
+	}
+	if len(v.Options) < 2 {
+		return fmt.Errorf("vote needs at least 2 options")
+	}
+	seen := make(map[string]bool, len(v.Options))
+	for _, option := range v.Options {
+		key := strings.ToLower(strings.TrimSpace(option.Text))
+		if key == "" {
+			return fmt.Errorf("option text is required")
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate option: %s", option.Text)
+		}
+		seen[key] = true
+	}
+
+	settings := v.Settings
+	if settings.MaxSelections != 0 {
+		if v.Type != VoteMultipleChoice {
+			return fmt.Errorf("max_selections only applies to multiple_choice votes")
+		}
+		if settings.MaxSelections < 1 || settings.MaxSelections > len(v.Options) {
+			return fmt.Errorf("max_selections must be between 1 and %d", len(v.Options))
+		}
+	}
+	if settings.DotBudget != 0 {
+		if v.Type != VoteNoteAndVote {
+			return fmt.Errorf("dot_budget only applies to note_and_vote votes")
+		}
+		if settings.DotBudget < 1 {
+			return fmt.Errorf("dot_budget must be positive")
+		}
+	}
+	if settings.RankingMethod != "" {
+		if v.Type != VoteRanking {
+			return fmt.Errorf("ranking_method only applies to ranking votes")
+		}
+		if settings.RankingMethod != RankingBorda && settings.RankingMethod != RankingInstantRunoff {
+			return fmt.Errorf("invalid ranking_method: %s", settings.RankingMethod)
+		}
+	}
+	return nil
+}
+
+// ApplyDefaults 补全投票规则的默认值，并将旧版状态转为新状态
+func (v *Vote) ApplyDefaults() {
+	if status, ok := legacyVoteStatuses[v.Status]; ok {
+		v.Status = status
+	}
+	switch v.Mode() {
+	case VoteNoteAndVote:
+		if v.Settings.DotBudget == 0 {
+			v.Settings.DotBudget = DefaultDotBudget
+		}
+	case VoteRanking:
+		if v.Settings.RankingMethod == "" {
+			v.Settings.RankingMethod = RankingBorda
+		}
+	}
+}
+
+// Ballot 一位用户的完整选票
+type Ballot struct {
+	UserID    string
+	UserName  string
+	OptionIDs []string          // 选择的选项；排序投票中按偏好从高到低
+	Dots      map[string]int    // 圆点投票中每个选项的点数
+	Comments  map[string]string // 对选项的评论
+}
+
+// BallotOf 返回用户当前的选票，未投票时选项为空
+func (v *Vote) BallotOf(userID string) Ballot {
+	ballot := Ballot{UserID: userID, Dots: make(map[string]int), Comments: make(map[string]string)}
+	type pick struct {
+		optionID string
+		rank     int
+	}
+	var picks []pick
+	picked := make(map[string]bool)
+	for _, option := range v.Options {
+		for _, vote := range option.Votes {
+			if vote.UserID != userID {
+				continue
+			}
+			ballot.UserName = vote.UserName
+			if vote.Comment != "" {
+				ballot.Comments[option.ID] = vote.Comment
+			}
+			if !picked[option.ID] {
+				picked[option.ID] = true
+				picks = append(picks, pick{option.ID, vote.Rank})
+			}
+			ballot.Dots[option.ID] += vote.Weight
+		}
+	}
+
+	sort.SliceStable(picks, func(i, j int) bool { return picks[i].rank < picks[j].rank })
+	for _, p := range picks {
+		ballot.OptionIDs = append(ballot.OptionIDs, p.optionID)
+	}
+	return ballot
+}
+
+// AddUserVote 为单个选项投票（旧版接口），结果合并到用户当前的选票：单选时改投该选项，
+// 多选时加入选择，排序时排在已有偏好之后，圆点投票时在该选项上增加 weight 个点
+func (v *Vote) AddUserVote(optionID, userID, userName string, weight int, comment string) error {
+	if v.optionIndex(optionID) < 0 {
+		return fmt.Errorf("unknown option: %s", optionID)
+	}
+	mode := v.Mode()
+	if mode != VoteNoteAndVote && weight != 1 {
+		return fmt.Errorf("weight only applies to note_and_vote votes")
+	}
+
+	ballot := v.BallotOf(userID)
+	ballot.UserName = userName
+	if comment != "" {
+		ballot.Comments[optionID] = comment
+	}
+	switch mode {
+	case VoteSingleChoice:
+		ballot.OptionIDs = []string{optionID}
+	case VoteMultipleChoice, VoteRanking:
+		for _, id := range ballot.OptionIDs {
+			if id == optionID {
+				return fmt.Errorf("already voted for option %s", optionID)
+			}
+		}
+		ballot.OptionIDs = append(ballot.OptionIDs, optionID)
+	case VoteNoteAndVote:
+		if weight < 1 {
+			return fmt.Errorf("weight must be positive")
+		}
+		ballot.Dots[optionID] += weight
+		ballot.OptionIDs = nil
+	}
+	if mode != VoteNoteAndVote {
+		ballot.Dots = nil
+	}
+	return v.CastBallot(ballot)
+}
+
+// CastBallot 按投票类型校验选票，并替换该用户之前的选票
+func (v *Vote) CastBallot(ballot Ballot) error {
+	if v.Status != VoteOpen {
+		return fmt.Errorf("vote is not open for ballots: %s", v.Status)
+	}
+	if strings.TrimSpace(ballot.UserID) == "" {
+		return fmt.Errorf("user_id is required")
+	}
+
+	seen := make(map[string]bool, len(ballot.OptionIDs))
+	for _, id := range ballot.OptionIDs {
+		if v.optionIndex(id) < 0 {
+			return fmt.Errorf("unknown option: %s", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("option %s selected more than once", id)
+		}
+		seen[id] = true
+	}
+	for id, dots := range ballot.Dots {
+		if v.optionIndex(id) < 0 {
+			return fmt.Errorf("unknown option: %s", id)
+		}
+		if dots < 1 {
+			return fmt.Errorf("dots for option %s must be positive", id)
+		}
+	}
+
+	mode := v.Mode()
+	if mode != VoteNoteAndVote && len(ballot.Dots) > 0 {
+		return fmt.Errorf("dots only apply to note_and_vote votes")
+	}
+	switch mode {
+	case VoteSingleChoice:
+		if len(ballot.OptionIDs) != 1 {
+			return fmt.Errorf("single_choice vote accepts exactly one option")
+		}
+	case VoteMultipleChoice:
+		if len(ballot.OptionIDs) == 0 {
+			return fmt.Errorf("select at least one option")
+		}
+		if limit := v.Settings.MaxSelections; limit > 0 && len(ballot.OptionIDs) > limit {
+			return fmt.Errorf("multiple_choice vote accepts at most %d options", limit)
+		}
+	case VoteRanking:
+		if len(ballot.OptionIDs) == 0 {
+			return fmt.Errorf("rank at least one option")
+		}
+	case VoteNoteAndVote:
+		// 也可以用 option_ids 为每个选项投一个点
+		dots := make(map[string]int, len(ballot.Dots)+len(ballot.OptionIDs))
+		for id, count := range ballot.Dots {
+			dots[id] += count
+		}
+		for _, id := range ballot.OptionIDs {
+			dots[id]++
+		}
+		total := 0
+		for _, count := range dots {
+			total += count
+		}
+		if total == 0 {
+			return fmt.Errorf("place at least one dot")
+		}
+		if budget := v.Settings.DotBudget; budget > 0 && total > budget {
+			return fmt.Errorf("ballot uses %d dots, budget is %d", total, budget)
+		}
+		ballot.Dots = dots
+	}
+
+	v.removeBallot(ballot.UserID)
+	now := time.Now()
+	entry := func(optionID string, weight, rank int) {
+		i := v.optionIndex(optionID)
+		v.Options[i].Votes = append(v.Options[i].Votes, UserVote{
+			UserID:   ballot.UserID,
+			UserName: ballot.UserName,
+			Weight:   weight,
+			Rank:     rank,
+			Comment:  ballot.Comments[optionID],
+			VotedAt:  now,
+		})
+	}
+	switch mode {
+	case VoteNoteAndVote:
+		for _, option := range v.Options {
+			if dots := ballot.Dots[option.ID]; dots > 0 {
+				entry(option.ID, dots, 0)
+			}
+		}
+	case VoteRanking:
+		// 权重为 Borda 分：排第 r 位得 选项数-r 分
+		for position, id := range ballot.OptionIDs {
+			entry(id, len(v.Options)-position-1, position+1)
+		}
+	default:
+		for _, id := range ballot.OptionIDs {
+			entry(id, 1, 0)
+		}
+	}
+	return nil
+}
+
+// removeBallot 删除用户在所有选项上的投票
+func (v *Vote) removeBallot(userID string) {
+	for i := range v.Options {
+		votes := v.Options[i].Votes[:0]
+		for _, vote := range v.Options[i].Votes {
+			if vote.UserID != userID {
+				votes = append(votes, vote)
+			}
+		}
+		v.Options[i].Votes = votes
+	}
+}
+
+// optionIndex 选项的下标，不存在时返回 -1
+func (v *Vote) optionIndex(optionID string) int {
+	for i := range v.Options {
+		if v.Options[i].ID == optionID {
+			return i
+		}
+	}
+	return -1
+}
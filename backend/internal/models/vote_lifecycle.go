@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 投票状态：draft → open → closed → revealed → decided
+const (
+	VoteDraft    = "draft"    // 草稿，尚未开放投票
+	VoteOpen     = "open"     // 开放投票
+	VoteClosed   = "closed"   // 停止投票，结果尚未公布
+	VoteRevealed = "revealed" // 公布结果
+	VoteDecided  = "decided"  // 决策者已拍板
+)
+
+// voteTransitions 允许的状态变更；停止投票后、公布结果前可以重新开放
+var voteTransitions = map[string][]string{
+	VoteDraft:    {VoteOpen},
+	VoteOpen:     {VoteClosed},
+	VoteClosed:   {VoteOpen, VoteRevealed},
+	VoteRevealed: {VoteDecided},
+}
+
+// legacyVoteStatuses 旧版投票状态对应的新状态
+var legacyVoteStatuses = map[string]string{
+	"active":    VoteOpen,
+	"completed": VoteRevealed,
+}
+
+// VoteDecision 决策者的超级投票：最终决定与理由
+type VoteDecision struct {
+	OptionID   string    `json:"option_id"`
+	OptionText string    `json:"option_text"`
+	Rationale  string    `json:"rationale"`
+	DecidedBy  string    `json:"decided_by"`
+	DecidedAt  time.Time `json:"decided_at"`
+	Winner     string    `json:"winner,omitempty"` // 计票结果的第一名
+	Overrides  bool      `json:"overrides"`        // 决定与计票结果不同
+}
+
+// Transition 变更投票状态。进入 decided 需要通过 Decide 记录决策者的决定
+func (v *Vote) Transition(status string, now time.Time) error {
+	if status == VoteDecided {
+		return fmt.Errorf("a vote is decided by the decider's supervote")
+	}
+	allowed := false
+	for _, next := range voteTransitions[v.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("cannot move vote from %s to %s", v.Status, status)
+	}
+
+	v.Status = status
+	switch status {
+	case VoteOpen:
+		v.EndedAt = nil
+	case VoteClosed:
+		v.EndedAt = &now
+	}
+	return nil
+}
+
+// Decide 记录决策者的超级投票，只能在公布结果后进行
+func (v *Vote) Decide(optionID, deciderID, rationale string, now time.Time) error {
+	if v.Status != VoteRevealed {
+		return fmt.Errorf("vote must be revealed before the decision, current status: %s", v.Status)
+	}
+	index := v.optionIndex(optionID)
+	if index < 0 {
+		return fmt.Errorf("unknown option: %s", optionID)
+	}
+	rationale = strings.TrimSpace(rationale)
+	if rationale == "" {
+		return fmt.Errorf("rationale is required")
+	}
+
+	winner := v.Tally().Winner
+	v.Decision = &VoteDecision{
+		OptionID:   optionID,
+		OptionText: v.Options[index].Text,
+		Rationale:  rationale,
+		DecidedBy:  deciderID,
+		DecidedAt:  now,
+		Winner:     winner,
+		Overrides:  winner != "" && winner != optionID,
+	}
+	v.Status = VoteDecided
+	return nil
+}
+
+// Decider 决策者的用户 ID，未指定时由主持人决定
+func (v *Vote) Decider(facilitatorID string) string {
+	if v.Settings.Decider != "" {
+		return v.Settings.Decider
+	}
+	return facilitatorID
+}
+
+// ResultsVisible 结果是否已公布
+func (v *Vote) ResultsVisible() bool {
+	return v.Status == VoteRevealed || v.Status == VoteDecided
+}
+
+// BallotCount 已投票的人数
+func (v *Vote) BallotCount() int {
+	return len(v.rankedBallots())
+}
+
+// PublicView 返回给参与者看的投票副本：公布结果前只保留 viewerID 自己的选票，
+// 匿名投票中隐藏其他投票人的身份
+func (v *Vote) PublicView(viewerID string) *Vote {
+	view := *v
+	view.Ballots = v.BallotCount()
+	view.Options = make([]VoteOption, len(v.Options))
+	for i, option := range v.Options {
+		view.Options[i] = option
+		votes := make([]UserVote, 0, len(option.Votes))
+		for _, vote := range option.Votes {
+			own := viewerID != "" && vote.UserID == viewerID
+			if !own && !v.ResultsVisible() {
+				continue
+			}
+			if !own && v.Settings.Anonymous {
+				vote.UserID = ""
+				vote.UserName = ""
+			}
+			votes = append(votes, vote)
+		}
+		view.Options[i].Votes = votes
+	}
+	return &view
+}
@@ -0,0 +1,285 @@
+package models
+
+import "sort"
+
+// 单选、多选与圆点投票的计票方法；排序投票使用 RankingBorda 或 RankingInstantRunoff
+const (
+	TallyPlurality = "plurality" // 单选：得票最多者胜
+	TallyApproval  = "approval"  // 多选：被选择次数最多者胜
+	TallyDots      = "dots"      // 圆点投票：点数最多者胜
+)
+
+// 决定第一名的规则（VoteResult.DecidedBy）
+const (
+	DecidedByScore            = "score"             // 得分（票数、点数或 Borda 分）更高
+	DecidedByVoters           = "voters"            // 点数相同，投票人数更多
+	DecidedByFirstPreferences = "first_preferences" // Borda 分相同，排在第一位的次数更多
+	DecidedByMajority         = "majority"          // 即时决选中获得过半数的第一偏好
+	DecidedByBorda            = "borda"             // 即时决选最后两个选项票数相同，Borda 分更高
+	DecidedByOptionOrder      = "option_order"      // 所有规则都相同，按选项顺序决定，即平局
+)
+
+// OptionResult 一个选项的计票结果
+type OptionResult struct {
+	OptionID         string `json:"option_id"`
+	Text             string `json:"text"`
+	Rank             int    `json:"rank"`                        // 名次，从 1 开始
+	Score            int    `json:"score"`                       // 票数、点数或 Borda 分；即时决选中为当选或被淘汰那一轮的票数
+	Voters           int    `json:"voters"`                      // 投给该选项的人数
+	FirstPreferences int    `json:"first_preferences,omitempty"` // 排序投票中排在第一位的次数
+	Borda            int    `json:"borda,omitempty"`             // 排序投票中的 Borda 分：排第 r 位得 选项数-r 分
+}
+
+// RunoffRound 即时决选的一轮
+type RunoffRound struct {
+	Round      int            `json:"round"`
+	Counts     map[string]int `json:"counts"`    // 各剩余选项获得的第一偏好
+	Exhausted  int            `json:"exhausted"` // 偏好的选项均已淘汰的选票数
+	Elected    string         `json:"elected,omitempty"`
+	Eliminated string         `json:"eliminated,omitempty"`
+	TieBreak   string         `json:"tie_break,omitempty"` // 票数最少的选项不止一个时，决定淘汰的规则
+}
+
+// VoteResult 投票结果
+type VoteResult struct {
+	VoteID    string         `json:"vote_id"`
+	Type      string         `json:"type"`
+	Method    string         `json:"method"`
+	Ballots   int            `json:"ballots"` // 投票人数
+	Options   []OptionResult `json:"options"` // 按名次排序
+	Winner    string         `json:"winner,omitempty"`
+	DecidedBy string         `json:"decided_by,omitempty"`
+	Tie       bool           `json:"tie"` // 第一名与第二名在所有规则上都相同
+	Rounds    []RunoffRound  `json:"rounds,omitempty"`
+}
+
+// tallyKey 排名规则：依次比较，值大者靠前
+type tallyKey struct {
+	name  string
+	value func(OptionResult) int
+}
+
+var (
+	byScore            = tallyKey{DecidedByScore, func(o OptionResult) int { return o.Score }}
+	byVoters           = tallyKey{DecidedByVoters, func(o OptionResult) int { return o.Voters }}
+	byFirstPreferences = tallyKey{DecidedByFirstPreferences, func(o OptionResult) int { return o.FirstPreferences }}
+	byBorda            = tallyKey{DecidedByBorda, func(o OptionResult) int { return o.Borda }}
+)
+
+// Tally 按投票类型计票。同分时：单选与多选按选项顺序；圆点投票比较投票人数；
+// Borda 比较排在第一位的次数；即时决选中淘汰票数最少的选项，票数相同时淘汰 Borda 分较低、
+// 再相同时排在后面的选项。所有规则都相同时按选项顺序决定，并标记为平局
+func (v *Vote) Tally() *VoteResult {
+	mode := v.Mode()
+	result := &VoteResult{
+		VoteID:  v.ID,
+		Type:    v.Type,
+		Method:  tallyMethod(mode, v.Settings),
+		Options: make([]OptionResult, 0, len(v.Options)),
+	}
+
+	stats := make([]OptionResult, len(v.Options))
+	for i, option := range v.Options {
+		stats[i] = OptionResult{OptionID: option.ID, Text: option.Text}
+		voters := make(map[string]bool)
+		for _, vote := range option.Votes {
+			voters[vote.UserID] = true
+			if mode == VoteNoteAndVote {
+				stats[i].Score += vote.Weight
+			}
+		}
+		stats[i].Voters = len(voters)
+		if mode == VoteSingleChoice || mode == VoteMultipleChoice {
+			stats[i].Score = stats[i].Voters
+		}
+	}
+
+	ballots := v.rankedBallots()
+	result.Ballots = len(ballots)
+	if mode == VoteRanking {
+		for _, ballot := range ballots {
+			for position, index := range ballot {
+				stats[index].Borda += len(v.Options) - position - 1
+			}
+			stats[ballot[0]].FirstPreferences++
+		}
+		for i := range stats {
+			stats[i].Score = stats[i].Borda
+		}
+	}
+
+	order := make([]int, len(stats))
+	for i := range order {
+		order[i] = i
+	}
+	switch {
+	case len(ballots) == 0:
+		// 没有选票时按选项顺序列出
+	case result.Method == RankingInstantRunoff:
+		order, result.Rounds, result.DecidedBy = instantRunoff(stats, ballots)
+	default:
+		var keys []tallyKey
+		switch result.Method {
+		case TallyDots:
+			keys = []tallyKey{byScore, byVoters}
+		case RankingBorda:
+			keys = []tallyKey{byScore, byFirstPreferences}
+		default:
+			keys = []tallyKey{byScore}
+		}
+		sortOptions(order, stats, keys)
+		result.DecidedBy = DecidedByScore
+		if len(order) > 1 {
+			result.DecidedBy = decidingKey(stats[order[0]], stats[order[1]], keys)
+		}
+	}
+
+	for rank, index := range order {
+		stats[index].Rank = rank + 1
+		result.Options = append(result.Options, stats[index])
+	}
+	if len(ballots) > 0 && len(order) > 0 {
+		result.Winner = stats[order[0]].OptionID
+		result.Tie = result.DecidedBy == DecidedByOptionOrder
+	}
+	return result
+}
+
+// tallyMethod 投票类型对应的计票方法
+func tallyMethod(mode string, settings VoteSettings) string {
+	switch mode {
+	case VoteSingleChoice:
+		return TallyPlurality
+	case VoteMultipleChoice:
+		return TallyApproval
+	case VoteRanking:
+		if settings.RankingMethod == RankingInstantRunoff {
+			return RankingInstantRunoff
+		}
+		return RankingBorda
+	default:
+		return TallyDots
+	}
+}
+
+// rankedBallots 每位用户选择的选项下标，按偏好从高到低（非排序投票按选项顺序）
+func (v *Vote) rankedBallots() [][]int {
+	var users []string
+	seen := make(map[string]bool)
+	for _, option := range v.Options {
+		for _, vote := range option.Votes {
+			if !seen[vote.UserID] {
+				seen[vote.UserID] = true
+				users = append(users, vote.UserID)
+			}
+		}
+	}
+
+	ballots := make([][]int, 0, len(users))
+	for _, userID := range users {
+		ballot := v.BallotOf(userID)
+		indexes := make([]int, 0, len(ballot.OptionIDs))
+		for _, id := range ballot.OptionIDs {
+			indexes = append(indexes, v.optionIndex(id))
+		}
+		ballots = append(ballots, indexes)
+	}
+	return ballots
+}
+
+// sortOptions 按规则依次比较排序，全部相同时保持选项顺序
+func sortOptions(order []int, stats []OptionResult, keys []tallyKey) {
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := stats[order[i]], stats[order[j]]
+		for _, key := range keys {
+			if key.value(a) != key.value(b) {
+				return key.value(a) > key.value(b)
+			}
+		}
+		return false
+	})
+}
+
+// decidingKey 区分两个相邻选项的第一条规则
+func decidingKey(a, b OptionResult, keys []tallyKey) string {
+	for _, key := range keys {
+		if key.value(a) != key.value(b) {
+			return key.name
+		}
+	}
+	return DecidedByOptionOrder
+}
+
+// instantRunoff 即时决选：每轮统计剩余选项获得的第一偏好，过半数者当选，否则淘汰票数最少的选项。
+// 返回名次顺序（当选者、其余剩余选项、按淘汰顺序倒序的已淘汰选项）、各轮记录与决定第一名的规则
+func instantRunoff(stats []OptionResult, ballots [][]int) ([]int, []RunoffRound, string) {
+	remaining := make([]int, len(stats))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	var eliminated []int
+	var rounds []RunoffRound
+	lastTieBreak := ""
+
+	for round := 1; ; round++ {
+		counts := make([]int, len(stats))
+		exhausted := 0
+		for _, ballot := range ballots {
+			preference := -1
+			for _, index := range ballot {
+				if containsIndex(remaining, index) {
+					preference = index
+					break
+				}
+			}
+			if preference < 0 {
+				exhausted++
+				continue
+			}
+			counts[preference]++
+		}
+
+		current := RunoffRound{Round: round, Counts: make(map[string]int, len(remaining)), Exhausted: exhausted}
+		for _, index := range remaining {
+			current.Counts[stats[index].OptionID] = counts[index]
+			stats[index].Score = counts[index]
+		}
+		sortOptions(remaining, stats, []tallyKey{byScore, byBorda})
+
+		leader := remaining[0]
+		active := len(ballots) - exhausted
+		if len(remaining) == 1 || counts[leader]*2 > active {
+			current.Elected = stats[leader].OptionID
+			rounds = append(rounds, current)
+			// 只剩一个选项时，上一轮最后两个选项票数相同，第一名由淘汰规则决定
+			decidedBy := DecidedByMajority
+			if len(remaining) == 1 && lastTieBreak != "" {
+				decidedBy = lastTieBreak
+			}
+			order := append([]int{}, remaining...)
+			for i := len(eliminated) - 1; i >= 0; i-- {
+				order = append(order, eliminated[i])
+			}
+			return order, rounds, decidedBy
+		}
+
+		loser := remaining[len(remaining)-1]
+		if previous := stats[remaining[len(remaining)-2]]; previous.Score == stats[loser].Score {
+			current.TieBreak = decidingKey(previous, stats[loser], []tallyKey{byBorda})
+		}
+		lastTieBreak = current.TieBreak
+		current.Eliminated = stats[loser].OptionID
+		rounds = append(rounds, current)
+		remaining = remaining[:len(remaining)-1]
+		eliminated = append(eliminated, loser)
+	}
+}
+
+func containsIndex(indexes []int, index int) bool {
+	for _, i := range indexes {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
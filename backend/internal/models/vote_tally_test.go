@@ -0,0 +1,247 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// tallyVote builds an open vote with options a, b, c, ... and casts ballots for users u1, u2, ...
+func tallyVote(t *testing.T, voteType, rankingMethod string, options int, ballots []Ballot) *Vote {
+	t.Helper()
+	vote := &Vote{ID: "v1", Type: voteType, Status: VoteOpen, Settings: VoteSettings{RankingMethod: rankingMethod}}
+	for i := 0; i < options; i++ {
+		id := string(rune('a' + i))
+		vote.Options = append(vote.Options, VoteOption{ID: id, Text: id, Votes: make([]UserVote, 0)})
+	}
+	for i, ballot := range ballots {
+		ballot.UserID = "u" + string(rune('1'+i))
+		if err := vote.CastBallot(ballot); err != nil {
+			t.Fatalf("ballot %d: %v", i+1, err)
+		}
+	}
+	return vote
+}
+
+func ranked(ids ...string) Ballot {
+	return Ballot{OptionIDs: ids}
+}
+
+func dots(counts map[string]int) Ballot {
+	return Ballot{Dots: counts}
+}
+
+func TestVoteTally(t *testing.T) {
+	tests := []struct {
+		name      string
+		voteType  string
+		method    string
+		options   int
+		ballots   []Ballot
+		order     []string // option IDs by rank
+		scores    []int    // scores by rank
+		decidedBy string
+		tie       bool
+		rounds    []RunoffRound
+	}{
+		{
+			name:     "plurality",
+			voteType: VoteSingleChoice, options: 3,
+			ballots:   []Ballot{ranked("a"), ranked("b"), ranked("b")},
+			order:     []string{"b", "a", "c"},
+			scores:    []int{2, 1, 0},
+			decidedBy: DecidedByScore,
+		},
+		{
+			name:     "plurality tie keeps option order",
+			voteType: VoteSingleChoice, options: 3,
+			ballots:   []Ballot{ranked("b"), ranked("a")},
+			order:     []string{"a", "b", "c"},
+			scores:    []int{1, 1, 0},
+			decidedBy: DecidedByOptionOrder,
+			tie:       true,
+		},
+		{
+			name:     "approval",
+			voteType: VoteMultipleChoice, options: 3,
+			ballots:   []Ballot{ranked("a", "b"), ranked("b", "c"), ranked("b")},
+			order:     []string{"b", "a", "c"},
+			scores:    []int{3, 1, 1},
+			decidedBy: DecidedByScore,
+		},
+		{
+			name:     "dots",
+			voteType: VoteNoteAndVote, options: 3,
+			ballots:   []Ballot{dots(map[string]int{"c": 3}), dots(map[string]int{"a": 1, "c": 1}), dots(map[string]int{"b": 2})},
+			order:     []string{"c", "b", "a"},
+			scores:    []int{4, 2, 1},
+			decidedBy: DecidedByScore,
+		},
+		{
+			name:     "dots tie broken by voters",
+			voteType: VoteNoteAndVote, options: 3,
+			ballots:   []Ballot{dots(map[string]int{"a": 3}), dots(map[string]int{"b": 2, "c": 1}), dots(map[string]int{"b": 1})},
+			order:     []string{"b", "a", "c"},
+			scores:    []int{3, 3, 1},
+			decidedBy: DecidedByVoters,
+		},
+		{
+			name:     "dots tie keeps option order",
+			voteType: VoteNoteAndVote, options: 3,
+			ballots:   []Ballot{dots(map[string]int{"b": 2}), dots(map[string]int{"a": 2})},
+			order:     []string{"a", "b", "c"},
+			scores:    []int{2, 2, 0},
+			decidedBy: DecidedByOptionOrder,
+			tie:       true,
+		},
+		{
+			name:     "borda",
+			voteType: VoteRanking, method: RankingBorda, options: 3,
+			ballots:   []Ballot{ranked("a", "b", "c"), ranked("b", "a", "c"), ranked("c", "b", "a")},
+			order:     []string{"b", "a", "c"},
+			scores:    []int{4, 3, 2},
+			decidedBy: DecidedByScore,
+		},
+		{
+			name:     "borda tie broken by first preferences",
+			voteType: VoteRanking, method: RankingBorda, options: 3,
+			ballots:   []Ballot{ranked("b", "a", "c"), ranked("a", "c", "b"), ranked("b", "a", "c")},
+			order:     []string{"b", "a", "c"},
+			scores:    []int{4, 4, 1},
+			decidedBy: DecidedByFirstPreferences,
+		},
+		{
+			name:     "borda tie keeps option order",
+			voteType: VoteRanking, method: RankingBorda, options: 3,
+			ballots:   []Ballot{ranked("b", "a"), ranked("a", "b")},
+			order:     []string{"a", "b", "c"},
+			scores:    []int{3, 3, 0},
+			decidedBy: DecidedByOptionOrder,
+			tie:       true,
+		},
+		{
+			name:     "instant runoff majority in the first round",
+			voteType: VoteRanking, method: RankingInstantRunoff, options: 3,
+			ballots:   []Ballot{ranked("a"), ranked("a"), ranked("b")},
+			order:     []string{"a", "b", "c"},
+			scores:    []int{2, 1, 0},
+			decidedBy: DecidedByMajority,
+			rounds: []RunoffRound{
+				{Round: 1, Counts: map[string]int{"a": 2, "b": 1, "c": 0}, Elected: "a"},
+			},
+		},
+		{
+			name:     "instant runoff eliminates until a majority with exhausted ballots",
+			voteType: VoteRanking, method: RankingInstantRunoff, options: 4,
+			ballots: []Ballot{
+				ranked("a"), ranked("a"), ranked("a"), ranked("b", "a"),
+				ranked("c"), ranked("c"), ranked("d"),
+			},
+			order:     []string{"a", "c", "b", "d"},
+			scores:    []int{4, 2, 1, 1},
+			decidedBy: DecidedByMajority,
+			rounds: []RunoffRound{
+				{Round: 1, Counts: map[string]int{"a": 3, "b": 1, "c": 2, "d": 1}, Eliminated: "d", TieBreak: DecidedByOptionOrder},
+				{Round: 2, Counts: map[string]int{"a": 3, "b": 1, "c": 2}, Exhausted: 1, Eliminated: "b"},
+				{Round: 3, Counts: map[string]int{"a": 4, "c": 2}, Exhausted: 1, Elected: "a"},
+			},
+		},
+		{
+			name:     "instant runoff final tie broken by borda",
+			voteType: VoteRanking, method: RankingInstantRunoff, options: 4,
+			ballots: []Ballot{
+				ranked("a"), ranked("a"), ranked("b", "a"), ranked("c"),
+				ranked("c", "b"), ranked("c"), ranked("d", "b"),
+			},
+			order:     []string{"c", "a", "b", "d"},
+			scores:    []int{3, 3, 2, 1},
+			decidedBy: DecidedByBorda,
+			rounds: []RunoffRound{
+				{Round: 1, Counts: map[string]int{"a": 2, "b": 1, "c": 3, "d": 1}, Eliminated: "d", TieBreak: DecidedByBorda},
+				{Round: 2, Counts: map[string]int{"a": 2, "b": 2, "c": 3}, Eliminated: "b", TieBreak: DecidedByBorda},
+				{Round: 3, Counts: map[string]int{"a": 3, "c": 3}, Exhausted: 1, Eliminated: "a", TieBreak: DecidedByBorda},
+				{Round: 4, Counts: map[string]int{"c": 3}, Exhausted: 4, Elected: "c"},
+			},
+		},
+		{
+			name:     "instant runoff final tie keeps option order",
+			voteType: VoteRanking, method: RankingInstantRunoff, options: 2,
+			ballots:   []Ballot{ranked("b", "a"), ranked("a", "b")},
+			order:     []string{"a", "b"},
+			scores:    []int{2, 1},
+			decidedBy: DecidedByOptionOrder,
+			tie:       true,
+			rounds: []RunoffRound{
+				{Round: 1, Counts: map[string]int{"a": 1, "b": 1}, Eliminated: "b", TieBreak: DecidedByOptionOrder},
+				{Round: 2, Counts: map[string]int{"a": 2}, Elected: "a"},
+			},
+		},
+		{
+			name:     "plurality without ballots",
+			voteType: VoteSingleChoice, options: 3,
+			order:  []string{"a", "b", "c"},
+			scores: []int{0, 0, 0},
+		},
+		{
+			name:     "instant runoff without ballots",
+			voteType: VoteRanking, method: RankingInstantRunoff, options: 3,
+			order:  []string{"a", "b", "c"},
+			scores: []int{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tallyVote(t, tt.voteType, tt.method, tt.options, tt.ballots).Tally()
+
+			if result.Ballots != len(tt.ballots) {
+				t.Errorf("ballots = %d, want %d", result.Ballots, len(tt.ballots))
+			}
+			order := make([]string, 0, len(result.Options))
+			scores := make([]int, 0, len(result.Options))
+			for i, option := range result.Options {
+				if option.Rank != i+1 {
+					t.Errorf("option %s has rank %d at position %d", option.OptionID, option.Rank, i+1)
+				}
+				order = append(order, option.OptionID)
+				scores = append(scores, option.Score)
+			}
+			if !reflect.DeepEqual(order, tt.order) || !reflect.DeepEqual(scores, tt.scores) {
+				t.Errorf("order %v scores %v, want %v %v", order, scores, tt.order, tt.scores)
+			}
+
+			winner := ""
+			if len(tt.ballots) > 0 {
+				winner = tt.order[0]
+			}
+			if result.Winner != winner || result.DecidedBy != tt.decidedBy || result.Tie != tt.tie {
+				t.Errorf("winner %q decided_by %q tie %v, want %q %q %v",
+					result.Winner, result.DecidedBy, result.Tie, winner, tt.decidedBy, tt.tie)
+			}
+			if !reflect.DeepEqual(result.Rounds, tt.rounds) {
+				t.Errorf("rounds = %+v, want %+v", result.Rounds, tt.rounds)
+			}
+		})
+	}
+}
+
+func TestVoteTallyMethod(t *testing.T) {
+	tests := []struct {
+		voteType string
+		method   string
+		want     string
+	}{
+		{VoteSingleChoice, "", TallyPlurality},
+		{VoteMultipleChoice, "", TallyApproval},
+		{VoteNoteAndVote, "", TallyDots},
+		{"legacy", "", TallyDots},
+		{VoteRanking, "", RankingBorda},
+		{VoteRanking, RankingBorda, RankingBorda},
+		{VoteRanking, RankingInstantRunoff, RankingInstantRunoff},
+	}
+	for _, tt := range tests {
+		vote := &Vote{Type: tt.voteType, Settings: VoteSettings{RankingMethod: tt.method}}
+		if got := vote.Tally().Method; got != tt.want {
+			t.Errorf("%s/%s: method = %s, want %s", tt.voteType, tt.method, got, tt.want)
+		}
+	}
+}
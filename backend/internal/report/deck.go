@@ -0,0 +1,404 @@
+package report
+
+import (
+	"fmt"
+	"foundation-sprint/internal/models"
+	"strings"
+	"time"
+)
+
+// Slide geometry in EMU (914400 per inch) on a 16:9 slide
+const (
+	slideWidth  = 12192000
+	slideHeight = 6858000
+	slideMargin = 609600
+	bodyTop     = 1371600
+	bodyHeight  = slideHeight - bodyTop - 685800
+	bodyWidth   = slideWidth - 2*slideMargin
+)
+
+// Bullets on a slide before the rest are summarised in one line; hypotheses carry two nested bullets each
+const (
+	deckMaxItems      = 7
+	deckMaxHypotheses = 4
+)
+
+// Font sizes in hundredths of a point
+const (
+	sizeCover    = 4400
+	sizeTitle    = 3200
+	sizeLead     = 2800
+	sizeBullet   = 2000
+	sizeDetail   = 1600
+	sizeLabel    = 1400
+	sizeCaption  = 1200
+	sizeFootnote = 1000
+)
+
+// deckRun is a run of text with its formatting; Heading runs use the theme's heading font
+type deckRun struct {
+	Text    string
+	Size    int
+	Bold    bool
+	Color   string
+	Heading bool
+}
+
+// deckParagraph is a paragraph of a text box
+type deckParagraph struct {
+	Runs        []deckRun
+	Align       string // l, ctr or r
+	Bullet      bool
+	Indent      int64 // left margin of bullets
+	SpaceBefore int   // hundredths of a point
+}
+
+// deckShape is a preset shape, optionally holding text
+type deckShape struct {
+	ID         int
+	Name       string
+	Geometry   string // rect, ellipse or line
+	X, Y, W, H int64
+	Fill       string
+	FillAlpha  int // opacity in thousandths of a percent; 0 is opaque
+	Line       string
+	LineWidth  int64
+	Dashed     bool
+	Anchor     string // t, ctr or b
+	Paragraphs []deckParagraph
+}
+
+// deckSlide is one slide; ID and RelID link it into the presentation part
+type deckSlide struct {
+	ID     int
+	RelID  string
+	Number int
+	Shapes []deckShape
+}
+
+// deck is the content of a pitch deck package
+type deck struct {
+	Title           string
+	Theme           DeckTheme
+	Slides          []*deckSlide
+	GeneratedAt     time.Time
+	TemplateVersion string
+}
+
+// buildDeck lays out the investor pitch of a sprint: cover, target customer, problem, competition,
+// advantages, the 2x2, principles, the chosen path with its Magic Lens scores and the hypotheses to
+// test next
+func buildDeck(sprint *Sprint, theme DeckTheme, generatedAt time.Time) *deck {
+	room := sprint.Room
+	d := &deck{Title: room.Name + " - 投资人路演", Theme: theme, GeneratedAt: generatedAt}
+
+	d.coverSlide(room.Name)
+	d.cardSlide("目标客户", room.Foundation.Customers)
+	d.cardSlide("核心问题", room.Foundation.Problems)
+	d.cardSlide("竞争格局", room.Foundation.Competition)
+	d.cardSlide("我们的优势", room.Foundation.Advantages)
+	d.matrixSlide(room.Differentiation)
+	principles := make([]Item, 0, len(room.Differentiation.Principles))
+	for _, principle := range room.Differentiation.Principles {
+		principles = append(principles, Item{Text: Text{Plain: principle}})
+	}
+	d.listSlide("核心原则", principles, deckMaxItems)
+	d.pathSlide(room.Approach)
+	d.hypothesesSlide(sprint.Hypotheses)
+
+	for i, slide := range d.Slides {
+		slide.ID = 256 + i
+		slide.RelID = fmt.Sprintf("rId%d", 4+i)
+		slide.Number = i + 1
+		if i > 0 {
+			d.footer(slide, room.Name, len(d.Slides))
+		}
+	}
+	return d
+}
+
+func (s *deckSlide) add(shape deckShape) {
+	shape.ID = len(s.Shapes) + 2
+	if shape.Name == "" {
+		shape.Name = fmt.Sprintf("Shape %d", shape.ID)
+	}
+	if shape.Anchor == "" {
+		shape.Anchor = "t"
+	}
+	s.Shapes = append(s.Shapes, shape)
+}
+
+func run(text string, size int, color string) deckRun {
+	return deckRun{Text: text, Size: size, Color: color}
+}
+
+func paragraph(align string, runs ...deckRun) deckParagraph {
+	return deckParagraph{Runs: runs, Align: align}
+}
+
+// textBox is a frameless text shape
+func textBox(name string, x, y, w, h int64, anchor string, paragraphs ...deckParagraph) deckShape {
+	return deckShape{Name: name, Geometry: "rect", X: x, Y: y, W: w, H: h, Anchor: anchor, Paragraphs: paragraphs}
+}
+
+// rect is a filled rectangle without an outline
+func rect(x, y, w, h int64, fill string, alpha int) deckShape {
+	return deckShape{Geometry: "rect", X: x, Y: y, W: w, H: h, Fill: fill, FillAlpha: alpha}
+}
+
+// multiline splits text into one paragraph per line
+func multiline(text string, size int, color string) []deckParagraph {
+	paragraphs := make([]deckParagraph, 0)
+	for _, line := range strings.Split(text, "\n") {
+		paragraphs = append(paragraphs, paragraph("l", run(line, size, color)))
+	}
+	return paragraphs
+}
+
+// newSlide starts a content slide with an accent bar beside its title
+func (d *deck) newSlide(title string) *deckSlide {
+	slide := &deckSlide{}
+	slide.add(rect(slideMargin, 457200, 76200, 548640, d.Theme.Accent, 0))
+	titleRun := run(title, sizeTitle, d.Theme.Text)
+	titleRun.Bold, titleRun.Heading = true, true
+	slide.add(textBox("Title", slideMargin+228600, 457200, bodyWidth-228600, 548640, "ctr", paragraph("l", titleRun)))
+	d.Slides = append(d.Slides, slide)
+	return slide
+}
+
+func (d *deck) footer(slide *deckSlide, roomName string, total int) {
+	top := int64(slideHeight - 502920)
+	slide.add(textBox("Footer", slideMargin, top, bodyWidth/2, 274320, "ctr",
+		paragraph("l", run(roomName, sizeFootnote, d.Theme.Muted))))
+	slide.add(textBox("Slide Number", slideMargin+bodyWidth/2, top, bodyWidth/2, 274320, "ctr",
+		paragraph("r", run(fmt.Sprintf("%d / %d", slide.Number, total), sizeFootnote, d.Theme.Muted))))
+}
+
+func (d *deck) coverSlide(roomName string) {
+	slide := &deckSlide{}
+	slide.add(rect(0, 0, 182880, slideHeight, d.Theme.Accent, 0))
+	nameRun := run(roomName, sizeCover, d.Theme.Text)
+	nameRun.Bold, nameRun.Heading = true, true
+	slide.add(textBox("Title", 1219200, 1737360, slideWidth-2438400, 1737360, "b", paragraph("l", nameRun)))
+	slide.add(textBox("Subtitle", 1219200, 3611880, slideWidth-2438400, 548640, "t",
+		paragraph("l", run("Foundation Sprint · 投资人路演", sizeBullet, d.Theme.Accent))))
+	slide.add(textBox("Date", 1219200, 4206240, slideWidth-2438400, 457200, "t",
+		paragraph("l", run(d.GeneratedAt.Format("2006-01-02"), sizeLabel, d.Theme.Muted))))
+	d.Slides = append(d.Slides, slide)
+}
+
+// bullets lays out items with their children as nested bullets, summarising what does not fit
+func (d *deck) bullets(items []Item, limit int) []deckParagraph {
+	if len(items) == 0 {
+		return []deckParagraph{paragraph("l", run("待确定", sizeBullet, d.Theme.Muted))}
+	}
+	paragraphs := make([]deckParagraph, 0, len(items))
+	for i, item := range items {
+		if i == limit {
+			more := paragraph("l", run(fmt.Sprintf("另有 %d 项", len(items)-limit), sizeDetail, d.Theme.Muted))
+			more.SpaceBefore = 1200
+			paragraphs = append(paragraphs, more)
+			break
+		}
+		runs := make([]deckRun, 0, 2)
+		if item.Strong != "" {
+			strong := run(item.Strong, sizeBullet, d.Theme.Text)
+			strong.Bold = true
+			runs = append(runs, strong)
+		}
+		if item.Plain != "" {
+			runs = append(runs, run(item.Plain, sizeBullet, d.Theme.Text))
+		}
+		bullet := deckParagraph{Runs: runs, Align: "l", Bullet: true, Indent: 342900, SpaceBefore: 1200}
+		paragraphs = append(paragraphs, bullet)
+		for _, child := range item.Children {
+			nested := deckParagraph{Runs: []deckRun{run(child, sizeDetail, d.Theme.Muted)}, Align: "l", Bullet: true, Indent: 800100, SpaceBefore: 300}
+			paragraphs = append(paragraphs, nested)
+		}
+	}
+	return paragraphs
+}
+
+func (d *deck) listSlide(title string, items []Item, limit int) {
+	slide := d.newSlide(title)
+	slide.add(textBox("Content", slideMargin, bodyTop, bodyWidth, bodyHeight, "t", d.bullets(items, limit)...))
+}
+
+// cardSlide lists foundation cards, each led by its text in bold
+func (d *deck) cardSlide(title string, cards []models.Card) {
+	items := make([]Item, 0, len(cards))
+	for _, card := range cards {
+		items = append(items, Item{Text: Text{Strong: card.Text, Plain: strings.TrimPrefix(cardLine(card), card.Text)}})
+	}
+	d.listSlide(title, items, deckMaxItems)
+}
+
+// matrixSlide draws the 2x2 with the winning quadrant shaded and each product as a dot at its position
+func (d *deck) matrixSlide(differentiation models.Differentiation) {
+	const (
+		plotLeft   = 3048000
+		plotTop    = 1463040
+		plotWidth  = 7772400
+		plotHeight = 4206240
+		dot        = 274320
+		labelWidth = 1828800
+	)
+	theme := d.Theme
+	matrix := differentiation.Matrix
+	analysis := models.BuildMatrixAnalysis(differentiation)
+	slide := d.newSlide("差异化定位")
+
+	quadrantX, quadrantY := int64(plotLeft+plotWidth/2), int64(plotTop)
+	switch analysis.WinningQuadrant {
+	case models.QuadrantTopLeft:
+		quadrantX = plotLeft
+	case models.QuadrantBottomRight:
+		quadrantY = plotTop + plotHeight/2
+	case models.QuadrantBottomLeft:
+		quadrantX, quadrantY = plotLeft, plotTop+plotHeight/2
+	}
+	slide.add(rect(quadrantX, quadrantY, plotWidth/2, plotHeight/2, theme.Accent, 12000))
+	slide.add(deckShape{Name: "Plot Area", Geometry: "rect", X: plotLeft, Y: plotTop, W: plotWidth, H: plotHeight, Line: theme.Muted, LineWidth: 12700})
+	slide.add(deckShape{Name: "X Midline", Geometry: "line", X: plotLeft, Y: plotTop + plotHeight/2, W: plotWidth, Line: theme.Muted, LineWidth: 9525, Dashed: true})
+	slide.add(deckShape{Name: "Y Midline", Geometry: "line", X: plotLeft + plotWidth/2, Y: plotTop, H: plotHeight, Line: theme.Muted, LineWidth: 9525, Dashed: true})
+
+	slide.add(textBox("X Axis", plotLeft, plotTop+plotHeight+45720, plotWidth, 320040, "t",
+		paragraph("r", run(orPending(matrix.XAxis)+" →", sizeLabel, theme.Text))))
+	legend := []deckParagraph{
+		paragraph("r", run("↑ "+orPending(matrix.YAxis), sizeLabel, theme.Text)),
+		paragraph("l", run("● ", sizeLabel, theme.Us), run("我们", sizeCaption, theme.Text)),
+		paragraph("l", run("● ", sizeLabel, theme.Competitor), run("竞争对手", sizeCaption, theme.Text)),
+	}
+	legend[1].SpaceBefore = 2400
+	if analysis.Us != "" {
+		verdict := paragraph("l", run(exclusivityLine(analysis), sizeCaption, theme.Muted))
+		verdict.SpaceBefore = 1800
+		legend = append(legend, verdict)
+	}
+	slide.add(textBox("Legend", slideMargin, plotTop, plotLeft-slideMargin-228600, plotHeight, "t", legend...))
+
+	for _, product := range matrix.Products {
+		x := plotLeft + int64(clampPercent(product.X)/100*plotWidth)
+		y := plotTop + int64((100-clampPercent(product.Y))/100*plotHeight)
+		color, label := theme.Competitor, run(product.Name, sizeCaption, theme.Text)
+		if product.IsUs {
+			color, label.Bold = theme.Us, true
+		}
+		slide.add(deckShape{Name: product.Name, Geometry: "ellipse", X: x - dot/2, Y: y - dot/2, W: dot, H: dot, Fill: color})
+
+		// Labels sit right of the dot unless they would run off the plot
+		labelX, align := x+dot/2+45720, "l"
+		if labelX+labelWidth > plotLeft+plotWidth {
+			labelX, align = x-dot/2-45720-labelWidth, "r"
+		}
+		slide.add(textBox(product.Name+" Label", labelX, y-dot/2, labelWidth, dot, "ctr", paragraph(align, label)))
+	}
+}
+
+// pathSlide shows the chosen path and its reasoning beside its score under each Magic Lens
+func (d *deck) pathSlide(approach models.Approach) {
+	const (
+		columnWidth = 5029200
+		scoresLeft  = slideMargin + columnWidth + 457200
+		scoresWidth = slideWidth - slideMargin - scoresLeft
+		rowHeight   = 548640
+		lensWidth   = 1645920
+		barWidth    = scoresWidth - lensWidth - 640080
+	)
+	theme := d.Theme
+	slide := d.newSlide("选定路径")
+
+	var chosen *models.Path
+	for i := range approach.Paths {
+		if approach.Paths[i].ID == approach.SelectedPath {
+			chosen = &approach.Paths[i]
+		}
+	}
+	name := run("待确定", sizeLead, theme.Accent)
+	name.Bold, name.Heading = true, true
+	text := []deckParagraph{paragraph("l", name)}
+	if chosen != nil {
+		text[0].Runs[0].Text = chosen.Name
+		if chosen.Description != "" {
+			text = append(text, multiline(chosen.Description, sizeDetail, theme.Text)...)
+		}
+	}
+	reasoningTitle := paragraph("l", run("决策理由", sizeLabel, theme.Muted))
+	reasoningTitle.SpaceBefore = 1800
+	text = append(text, reasoningTitle)
+	text = append(text, multiline(orPending(approach.Reasoning), sizeDetail, theme.Text)...)
+	slide.add(textBox("Chosen Path", slideMargin, bodyTop, columnWidth, bodyHeight, "t", text...))
+
+	header := run("魔术镜头评分", sizeLabel, theme.Muted)
+	header.Bold = true
+	slide.add(textBox("Magic Lenses", scoresLeft, bodyTop, scoresWidth, 365760, "t", paragraph("l", header)))
+	if chosen == nil || len(approach.MagicLenses) == 0 {
+		slide.add(textBox("No Scores", scoresLeft, bodyTop+457200, scoresWidth, 365760, "t",
+			paragraph("l", run("尚未使用魔术镜头评分", sizeDetail, theme.Muted))))
+		return
+	}
+
+	y := int64(bodyTop + 457200)
+	for i, lens := range approach.MagicLenses {
+		if i == deckMaxItems {
+			break
+		}
+		label := []deckRun{run(lens.Name, sizeLabel, theme.Text)}
+		if weight := lens.EffectiveWeight(); weight != 1 {
+			label = append(label, run(fmt.Sprintf(" ×%g", weight), sizeCaption, theme.Muted))
+		}
+		slide.add(textBox(lens.Name, scoresLeft, y, lensWidth, 320040, "ctr", paragraph("l", label...)))
+
+		barX := int64(scoresLeft + lensWidth)
+		slide.add(rect(barX, y+91440, barWidth, 137160, theme.Muted, 30000))
+		score := "—"
+		for _, evaluation := range lens.Evaluations {
+			if evaluation.PathID == chosen.ID {
+				score = fmt.Sprintf("%.1f", evaluation.Score)
+				if width := int64(evaluation.Score / models.MagicLensScoreMax * barWidth); width > 0 {
+					slide.add(rect(barX, y+91440, width, 137160, theme.Accent, 0))
+				}
+			}
+		}
+		slide.add(textBox(lens.Name+" Score", barX+barWidth+91440, y, 548640, 320040, "ctr",
+			paragraph("r", run(score, sizeLabel, theme.Text))))
+		y += rowHeight
+	}
+
+	decision := models.BuildApproachDecision(approach)
+	for _, path := range decision.Paths {
+		if path.PathID != chosen.ID || path.Rank == 0 {
+			continue
+		}
+		summary := paragraph("l",
+			run(fmt.Sprintf("加权得分 %.2f / %d", path.WeightedScore, models.MagicLensScoreMax), sizeDetail, theme.Text),
+			run(fmt.Sprintf(" · %d 条路径中排第 %d", len(decision.Paths), path.Rank), sizeCaption, theme.Muted))
+		summary.Runs[0].Bold = true
+		slide.add(textBox("Weighted Score", scoresLeft, y+91440, scoresWidth, 365760, "t", summary))
+	}
+}
+
+// hypothesesSlide lists the hypotheses still to be tested with how each one will be tested
+func (d *deck) hypothesesSlide(hypotheses []*models.Hypothesis) {
+	items := make([]Item, 0)
+	for _, h := range hypotheses {
+		if h.Status != models.HypothesisUntested {
+			continue
+		}
+		items = append(items, Item{Text: Text{Strong: h.Statement}, Children: []string{
+			"验证方法：" + orPending(h.ValidationMethod),
+			"成功指标：" + orPending(h.SuccessMetric),
+		}})
+	}
+	d.listSlide("下一步：待验证的假设", items, deckMaxHypotheses)
+}
+
+func clampPercent(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+	if value > 100 {
+		return 100
+	}
+	return value
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultDeckTheme is the theme used when none is requested
+const DefaultDeckTheme = "light"
+
+// DeckTheme is the colour and font configuration of a pitch deck. Colours are six-digit hex RGB
+type DeckTheme struct {
+	Background    string `json:"background"`
+	Text          string `json:"text"`
+	Muted         string `json:"muted"`      // secondary text, axes and footers
+	Accent        string `json:"accent"`     // title bars, the winning quadrant and score bars
+	Us            string `json:"us"`         // our product on the 2x2
+	Competitor    string `json:"competitor"` // other products on the 2x2
+	HeadingFont   string `json:"heading_font"`
+	BodyFont      string `json:"body_font"`
+	EastAsianFont string `json:"east_asian_font"`
+}
+
+// DefaultDeckThemes returns the built-in themes
+func DefaultDeckThemes() map[string]DeckTheme {
+	return map[string]DeckTheme{
+		"light": {
+			Background:    "FFFFFF",
+			Text:          "1F2937",
+			Muted:         "9CA3AF",
+			Accent:        "2563EB",
+			Us:            "F97316",
+			Competitor:    "64748B",
+			HeadingFont:   "Calibri",
+			BodyFont:      "Calibri",
+			EastAsianFont: "Microsoft YaHei",
+		},
+		"dark": {
+			Background:    "111827",
+			Text:          "F9FAFB",
+			Muted:         "6B7280",
+			Accent:        "60A5FA",
+			Us:            "FB923C",
+			Competitor:    "94A3B8",
+			HeadingFont:   "Calibri",
+			BodyFont:      "Calibri",
+			EastAsianFont: "Microsoft YaHei",
+		},
+	}
+}
+
+// ParseDeckThemes reads a JSON object of named themes. Listed themes are added to the built-in ones,
+// replacing a built-in theme of the same name; fields left out are taken from the default theme
+func ParseDeckThemes(data []byte) (map[string]DeckTheme, error) {
+	var configured map[string]DeckTheme
+	if err := json.Unmarshal(data, &configured); err != nil {
+		return nil, fmt.Errorf("invalid deck themes: %w", err)
+	}
+
+	themes := DefaultDeckThemes()
+	fallback := themes[DefaultDeckTheme]
+	for name, theme := range configured {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("deck theme name is required")
+		}
+		theme.fill(fallback)
+		if err := theme.validate(); err != nil {
+			return nil, fmt.Errorf("deck theme %s: %w", name, err)
+		}
+		themes[name] = theme
+	}
+	return themes, nil
+}
+
+// DeckThemeNames lists theme names in alphabetical order
+func DeckThemeNames(themes map[string]DeckTheme) []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fill takes the fields left out from fallback
+func (t *DeckTheme) fill(fallback DeckTheme) {
+	fields := []struct {
+		value    *string
+		fallback string
+	}{
+		{&t.Background, fallback.Background},
+		{&t.Text, fallback.Text},
+		{&t.Muted, fallback.Muted},
+		{&t.Accent, fallback.Accent},
+		{&t.Us, fallback.Us},
+		{&t.Competitor, fallback.Competitor},
+		{&t.HeadingFont, fallback.HeadingFont},
+		{&t.BodyFont, fallback.BodyFont},
+		{&t.EastAsianFont, fallback.EastAsianFont},
+	}
+	for _, field := range fields {
+		*field.value = strings.TrimSpace(*field.value)
+		if *field.value == "" {
+			*field.value = field.fallback
+		}
+	}
+}
+
+// validate normalises colours to upper-case hex without a leading #
+func (t *DeckTheme) validate() error {
+	colors := []struct {
+		name  string
+		value *string
+	}{
+		{"background", &t.Background},
+		{"text", &t.Text},
+		{"muted", &t.Muted},
+		{"accent", &t.Accent},
+		{"us", &t.Us},
+		{"competitor", &t.Competitor},
+	}
+	for _, color := range colors {
+		value := strings.ToUpper(strings.TrimPrefix(*color.value, "#"))
+		if len(value) != 6 || strings.Trim(value, "0123456789ABCDEF") != "" {
+			return fmt.Errorf("%s must be a six-digit hex colour: %s", color.name, *color.value)
+		}
+		*color.value = value
+	}
+	return nil
+}
@@ -0,0 +1,549 @@
+package report
+
+import (
+	"fmt"
+	"foundation-sprint/internal/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Block kinds of a report section
+const (
+	BlockHeading   = "heading"   // subsection title
+	BlockParagraph = "paragraph" // text, may span several lines
+	BlockList      = "list"      // bullets with optional nested bullets
+	BlockRanking   = "ranking"   // ranked items; tied items share a rank
+)
+
+// Text is a run of text with an optional bold lead, rendered as the lead followed by the plain text
+type Text struct {
+	Strong string
+	Plain  string
+}
+
+// Item is an entry of a list or ranking
+type Item struct {
+	Text
+	Rank     int      // ranking only
+	Children []string // nested bullets
+}
+
+// Block is one piece of section content
+type Block struct {
+	Kind string
+	Text
+	Items []Item
+}
+
+// Section is a top-level part of the report
+type Section struct {
+	Title  string
+	Blocks []Block
+}
+
+// Document is the format-neutral content of a report; every format renders the same document
+type Document struct {
+	Title           string
+	Summary         string
+	Sections        []Section
+	GeneratedAt     time.Time
+	TemplateVersion string
+}
+
+// Build lays out the sprint in the same structure as the browser report, followed by the records
+// kept with the room: hypotheses, interview validation, risks, votes and accepted agent proposals
+func Build(sprint *Sprint, generatedAt time.Time) *Document {
+	room := sprint.Room
+	doc := &Document{
+		Title:       fmt.Sprintf("%s - Foundation Sprint 报告", room.Name),
+		Summary:     fmt.Sprintf("当前阶段：%s", room.Status),
+		GeneratedAt: generatedAt,
+	}
+
+	foundation := Section{Title: "基础信息"}
+	foundation.cards("目标客户", room.Foundation.Customers)
+	foundation.cards("核心问题", room.Foundation.Problems)
+	foundation.cards("竞争对手", room.Foundation.Competition)
+	foundation.cards("团队优势", room.Foundation.Advantages)
+	doc.Sections = append(doc.Sections, foundation)
+
+	differentiation := Section{Title: "差异化定位"}
+	differentiation.bullets("核心原则", room.Differentiation.Principles)
+	factors := append(append([]models.DifferentiationFactor{}, room.Differentiation.ClassicFactors...),
+		room.Differentiation.CustomFactors...)
+	if len(factors) > 0 {
+		items := make([]Item, 0, len(factors))
+		for _, factor := range factors {
+			items = append(items, namedItem(factor.Name, factor.Description))
+		}
+		differentiation.heading("差异化因素")
+		differentiation.list(items)
+	}
+	differentiation.matrix(room.Differentiation)
+	doc.Sections = append(doc.Sections, differentiation)
+
+	doc.Sections = append(doc.Sections, approachSection(room))
+
+	if len(sprint.Hypotheses) > 0 {
+		doc.Sections = append(doc.Sections, hypothesesSection(sprint.Hypotheses))
+	}
+	if len(sprint.Scorecards) > 0 {
+		doc.Sections = append(doc.Sections, validationSection(sprint))
+	}
+	if len(sprint.Risks) > 0 {
+		doc.Sections = append(doc.Sections, riskSection(room, sprint.Risks))
+	}
+	if len(sprint.Votes) > 0 {
+		votes := Section{Title: "投票结果"}
+		for _, vote := range sprint.Votes {
+			votes.vote(vote)
+		}
+		doc.Sections = append(doc.Sections, votes)
+	}
+	if len(sprint.Proposals) > 0 {
+		doc.Sections = append(doc.Sections, agentSection(sprint.Proposals))
+	}
+	return doc
+}
+
+func (s *Section) heading(title string) {
+	s.Blocks = append(s.Blocks, Block{Kind: BlockHeading, Text: Text{Plain: title}})
+}
+
+func (s *Section) paragraph(text Text) {
+	s.Blocks = append(s.Blocks, Block{Kind: BlockParagraph, Text: text})
+}
+
+func (s *Section) list(items []Item) {
+	s.Blocks = append(s.Blocks, Block{Kind: BlockList, Items: items})
+}
+
+// bullets adds a titled list, marking empty subsections as pending
+func (s *Section) bullets(title string, values []string) {
+	s.heading(title)
+	if len(values) == 0 {
+		s.paragraph(Text{Plain: "待确定"})
+		return
+	}
+	items := make([]Item, 0, len(values))
+	for _, value := range values {
+		items = append(items, Item{Text: Text{Plain: value}})
+	}
+	s.list(items)
+}
+
+// namedItem is a bold name with an optional description
+func namedItem(name, description string) Item {
+	item := Item{Text: Text{Strong: name}}
+	if description != "" {
+		item.Plain = "：" + description
+	}
+	return item
+}
+
+var quadrantLabels = map[string]string{
+	models.QuadrantTopRight:    "右上",
+	models.QuadrantTopLeft:     "左上",
+	models.QuadrantBottomRight: "右下",
+	models.QuadrantBottomLeft:  "左下",
+}
+
+// matrix adds the 2x2 positions and the exclusivity test of the winning quadrant
+func (s *Section) matrix(differentiation models.Differentiation) {
+	matrix := differentiation.Matrix
+	if matrix.XAxis == "" && matrix.YAxis == "" && len(matrix.Products) == 0 {
+		return
+	}
+
+	items := []Item{
+		{Text: Text{Plain: "X 轴：" + orPending(matrix.XAxis)}},
+		{Text: Text{Plain: "Y 轴：" + orPending(matrix.YAxis)}},
+	}
+	for _, product := range matrix.Products {
+		marker := ""
+		if product.IsUs {
+			marker = "（我们）"
+		}
+		items = append(items, Item{Text: Text{Plain: fmt.Sprintf("%s%s：(%.0f, %.0f)", product.Name, marker, product.X, product.Y)}})
+	}
+
+	analysis := models.BuildMatrixAnalysis(differentiation)
+	if analysis.Us != "" {
+		items = append(items, Item{Text: Text{Plain: exclusivityLine(analysis)}})
+		if analysis.NearestCompetitor != "" {
+			items = append(items, Item{Text: Text{Plain: fmt.Sprintf("最近的竞争对手：%s（距离 %.1f）", analysis.NearestCompetitor, analysis.NearestDistance)}})
+		}
+	}
+
+	s.heading("2x2 矩阵")
+	s.list(items)
+}
+
+// exclusivityLine states whether our product holds the winning quadrant alone
+func exclusivityLine(analysis *models.MatrixAnalysis) string {
+	winning := quadrantLabels[analysis.WinningQuadrant]
+	switch {
+	case analysis.Exclusive:
+		return fmt.Sprintf("胜利象限（%s）：%s 独占", winning, analysis.Us)
+	case analysis.InWinningQuadrant && len(analysis.SharedWith) > 0:
+		return fmt.Sprintf("胜利象限（%s）：%s 与 %s 共享，未通过排他性检验", winning, analysis.Us, strings.Join(analysis.SharedWith, "、"))
+	case analysis.InWinningQuadrant:
+		return fmt.Sprintf("胜利象限（%s）：%s 落在中线上，未通过排他性检验", winning, analysis.Us)
+	default:
+		return fmt.Sprintf("胜利象限（%s）：%s 位于%s，未进入胜利象限", winning, analysis.Us, quadrantLabels[analysis.UsQuadrant])
+	}
+}
+
+var affectedShareLabels = map[string]string{
+	models.AffectedFew:  "少数客户",
+	models.AffectedSome: "部分客户",
+	models.AffectedMany: "多数客户",
+	models.AffectedMost: "绝大多数客户",
+}
+
+var competitionKindLabels = map[string]string{
+	models.CompetitionDirect:      "直接竞品",
+	models.CompetitionAlternative: "替代方案",
+	models.CompetitionWorkaround:  "变通做法",
+}
+
+var advantageCategoryLabels = map[string]string{
+	models.AdvantageTechnical:  "技术",
+	models.AdvantageInsight:    "洞察",
+	models.AdvantageResource:   "资源",
+	models.AdvantageMotivation: "动力",
+}
+
+// cards adds foundation cards with their attributes and description
+func (s *Section) cards(title string, cards []models.Card) {
+	s.heading(title)
+	if len(cards) == 0 {
+		s.paragraph(Text{Plain: "待确定"})
+		return
+	}
+	items := make([]Item, 0, len(cards))
+	for _, card := range cards {
+		items = append(items, Item{Text: Text{Plain: cardLine(card)}})
+	}
+	s.list(items)
+}
+
+// cardLine is a card's text followed by its attributes and description
+func cardLine(card models.Card) string {
+	var attributes []string
+	if card.PainIntensity != 0 {
+		attributes = append(attributes, fmt.Sprintf("痛点强度 %d/%d", card.PainIntensity, models.PainIntensityMax))
+	}
+	if card.AffectedShare != "" {
+		attributes = append(attributes, "影响"+labelOr(affectedShareLabels, card.AffectedShare))
+	}
+	if card.Kind != "" {
+		attributes = append(attributes, labelOr(competitionKindLabels, card.Kind))
+	}
+	if card.Category != "" {
+		attributes = append(attributes, labelOr(advantageCategoryLabels, card.Category))
+	}
+	line := card.Text
+	if len(attributes) > 0 {
+		line += "（" + strings.Join(attributes, "，") + "）"
+	}
+	if card.Description != "" {
+		line += "：" + card.Description
+	}
+	return line
+}
+
+// approachSection covers the candidate paths, the magic lens scores and the chosen path
+func approachSection(room *models.Room) Section {
+	section := Section{Title: "执行路径"}
+	if len(room.Approach.Paths) > 0 {
+		items := make([]Item, 0, len(room.Approach.Paths))
+		for _, path := range room.Approach.Paths {
+			item := namedItem(path.Name, path.Description)
+			if len(path.Pros) > 0 {
+				item.Children = append(item.Children, "优势："+strings.Join(path.Pros, "；"))
+			}
+			if len(path.Cons) > 0 {
+				item.Children = append(item.Children, "劣势："+strings.Join(path.Cons, "；"))
+			}
+			items = append(items, item)
+		}
+		section.heading("候选路径")
+		section.list(items)
+	}
+
+	decision := models.BuildApproachDecision(room.Approach)
+	ranked := make([]Item, 0, len(decision.Paths))
+	for _, path := range decision.Paths {
+		if path.Rank == 0 {
+			continue
+		}
+		text := fmt.Sprintf("%s：加权得分 %.2f / %d", path.Name, path.WeightedScore, models.MagicLensScoreMax)
+		if path.Coverage < 1 {
+			text += fmt.Sprintf("（已评镜头权重占 %.0f%%）", path.Coverage*100)
+		}
+		ranked = append(ranked, Item{Text: Text{Plain: text}, Rank: path.Rank})
+	}
+	if len(ranked) > 0 {
+		section.heading("魔术镜头评估")
+		section.Blocks = append(section.Blocks, Block{Kind: BlockRanking, Items: ranked})
+	}
+
+	selected := ""
+	for _, path := range room.Approach.Paths {
+		if path.ID == room.Approach.SelectedPath {
+			selected = path.Name
+		}
+	}
+	section.heading("选定方案")
+	section.paragraph(Text{Plain: orPending(selected)})
+	section.heading("决策理由")
+	section.paragraph(Text{Plain: orPending(room.Approach.Reasoning)})
+	return section
+}
+
+var tallyMethodLabels = map[string]string{
+	models.TallyPlurality:       "单选",
+	models.TallyApproval:        "多选",
+	models.TallyDots:            "圆点投票",
+	models.RankingBorda:         "排序（Borda 计分）",
+	models.RankingInstantRunoff: "排序（即时决选）",
+}
+
+var scoreUnitLabels = map[string]string{
+	models.TallyPlurality:       "票",
+	models.TallyApproval:        "票",
+	models.TallyDots:            "点",
+	models.RankingBorda:         "分",
+	models.RankingInstantRunoff: "票",
+}
+
+// vote adds a vote's options in rank order under its tally method, and the decider's final decision
+func (s *Section) vote(vote *models.Vote) {
+	s.heading(fmt.Sprintf("%s（%s）", vote.Title, vote.Status))
+
+	if !vote.ResultsVisible() {
+		s.paragraph(Text{Plain: fmt.Sprintf("已有 %d 人投票，结果将在公布后显示。", vote.BallotCount())})
+		return
+	}
+
+	result := vote.Tally()
+	summary := fmt.Sprintf("%s，%d 人投票。", labelOr(tallyMethodLabels, result.Method), result.Ballots)
+	if len(result.Rounds) > 0 {
+		summary += fmt.Sprintf("即时决选共 %d 轮，票数为各选项当选或被淘汰那一轮获得的第一偏好。", len(result.Rounds))
+	}
+	if result.Tie {
+		summary += "第一名与第二名在所有计票规则上相同，按选项顺序决定。"
+	}
+	s.paragraph(Text{Plain: summary})
+
+	items := make([]Item, 0, len(result.Options))
+	for _, option := range result.Options {
+		items = append(items, Item{
+			Text: Text{Plain: fmt.Sprintf("%s：%d %s", option.Text, option.Score, scoreUnitLabels[result.Method])},
+			Rank: option.Rank,
+		})
+	}
+	s.Blocks = append(s.Blocks, Block{Kind: BlockRanking, Items: items})
+
+	if decision := vote.Decision; decision != nil {
+		text := fmt.Sprintf("：%s。理由：%s", decision.OptionText, decision.Rationale)
+		if decision.Overrides {
+			text += "（决策者没有采纳投票第一名）"
+		}
+		s.paragraph(Text{Strong: "最终决定", Plain: text})
+	}
+}
+
+var hypothesisStatusLabels = map[string]string{
+	models.HypothesisUntested:    "待验证",
+	models.HypothesisValidated:   "已验证",
+	models.HypothesisInvalidated: "已证伪",
+}
+
+var confidenceLabels = map[string]string{
+	models.HypothesisConfidenceLow:    "低",
+	models.HypothesisConfidenceMedium: "中",
+	models.HypothesisConfidenceHigh:   "高",
+}
+
+// hypothesesSection lists the founding hypotheses with how each one is tested; untested ones are
+// the hand-off to the Design Sprint
+func hypothesesSection(hypotheses []*models.Hypothesis) Section {
+	items := make([]Item, 0, len(hypotheses))
+	for _, h := range hypotheses {
+		item := Item{Text: Text{Strong: h.Statement}, Children: []string{
+			fmt.Sprintf("状态：%s；置信度：%s", labelOr(hypothesisStatusLabels, h.Status), labelOr(confidenceLabels, h.Confidence)),
+			"验证方法：" + orPending(h.ValidationMethod),
+			"成功指标：" + orPending(h.SuccessMetric),
+		}}
+		if h.Evidence != "" {
+			item.Children = append(item.Children, "验证结果："+h.Evidence)
+		}
+		items = append(items, item)
+	}
+	section := Section{Title: "创始假设"}
+	section.list(items)
+	return section
+}
+
+var ratingLabels = map[string]string{
+	models.RatingRed:    "红",
+	models.RatingYellow: "黄",
+	models.RatingGreen:  "绿",
+}
+
+var dimensionLabels = map[string]string{
+	models.DimensionCustomerFit:      "客户匹配度",
+	models.DimensionProblemFit:       "问题契合度",
+	models.DimensionSolutionAppeal:   "方案吸引力",
+	models.DimensionDifferentiation:  "差异化感知",
+	models.DimensionOverallResonance: "整体共鸣度",
+}
+
+// validationSection adds each tested hypothesis's scorecard tallies and its score trend across interviews
+func validationSection(sprint *Sprint) Section {
+	section := Section{Title: "访谈验证"}
+	section.paragraph(Text{Plain: fmt.Sprintf("共 %d 场访谈，%d 张计分卡。得分：绿 1、黄 0.5、红 0。", len(sprint.Interviews), len(sprint.Scorecards))})
+
+	for _, v := range sprint.Validations() {
+		if v.Scorecards == 0 {
+			continue
+		}
+		items := []Item{{Text: Text{Plain: fmt.Sprintf("%d 场访谈，%d 张计分卡，平均得分 %.2f（%s），建议状态：%s，当前状态：%s",
+			v.Interviews, v.Scorecards, v.Score, labelOr(ratingLabels, v.Signal),
+			labelOr(hypothesisStatusLabels, v.SuggestedStatus), labelOr(hypothesisStatusLabels, v.Status))}}}
+		for _, dimension := range models.ScorecardDimensions {
+			tally := v.Dimensions[dimension]
+			if tally.Red+tally.Yellow+tally.Green == 0 {
+				continue
+			}
+			items = append(items, Item{Text: Text{Plain: fmt.Sprintf("%s：绿 %d / 黄 %d / 红 %d", dimensionLabels[dimension], tally.Green, tally.Yellow, tally.Red)}})
+		}
+		points := make([]string, 0, len(v.Trend))
+		for _, point := range v.Trend {
+			points = append(points, fmt.Sprintf("%s %.2f（累计 %.2f）", point.ConductedAt.Format("2006-01-02"), point.Score, point.CumulativeScore))
+		}
+		items = append(items, Item{Text: Text{Plain: "趋势：" + strings.Join(points, " → ")}})
+
+		section.heading(v.Statement)
+		section.list(items)
+	}
+	return section
+}
+
+var riskLevelLabels = map[string]string{
+	models.RiskLevelLow:      "低",
+	models.RiskLevelMedium:   "中",
+	models.RiskLevelHigh:     "高",
+	models.RiskLevelCritical: "严重",
+}
+
+var riskStatusLabels = map[string]string{
+	models.RiskOpen:       "待处理",
+	models.RiskMitigating: "缓解中",
+	models.RiskAccepted:   "已接受",
+	models.RiskClosed:     "已关闭",
+}
+
+// riskSection lists the risk register from the highest score down, with what each risk threatens
+func riskSection(room *models.Room, risks []*models.Risk) Section {
+	pathNames := make(map[string]string, len(room.Approach.Paths))
+	for _, path := range room.Approach.Paths {
+		pathNames[path.ID] = path.Name
+	}
+
+	sorted := append([]*models.Risk{}, risks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+	items := make([]Item, 0, len(sorted))
+	for _, risk := range sorted {
+		item := Item{Text: Text{
+			Strong: risk.Title,
+			Plain:  fmt.Sprintf("（%s · 可能性 %d × 影响 %d = %d）", labelOr(riskLevelLabels, risk.Level), risk.Likelihood, risk.Impact, risk.Score),
+		}}
+		details := []string{"状态：" + labelOr(riskStatusLabels, risk.Status)}
+		if risk.Category != "" {
+			details = append(details, "类别："+risk.Category)
+		}
+		if risk.Owner != "" {
+			details = append(details, "负责人："+risk.Owner)
+		}
+		item.Children = append(item.Children, strings.Join(details, "；"))
+		if name, ok := pathNames[risk.PathID]; ok {
+			item.Children = append(item.Children, "威胁路径："+name)
+		}
+		if risk.Assumption != "" {
+			item.Children = append(item.Children, "威胁假设："+risk.Assumption)
+		}
+		if risk.Mitigation != "" {
+			item.Children = append(item.Children, "缓解措施："+risk.Mitigation)
+		}
+		items = append(items, item)
+	}
+	section := Section{Title: "风险登记表"}
+	section.list(items)
+	return section
+}
+
+// agentSection lists the agent proposals the facilitator accepted, oldest first
+func agentSection(proposals []*models.Proposal) Section {
+	sorted := append([]*models.Proposal{}, proposals...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+	items := make([]Item, 0, len(sorted))
+	for _, proposal := range sorted {
+		item := Item{Text: Text{Strong: proposal.AgentName, Plain: "：" + proposalSummary(proposal)}}
+		if proposal.Rationale != "" {
+			item.Children = append(item.Children, "理由："+proposal.Rationale)
+		}
+		if proposal.DecidedBy != "" {
+			item.Children = append(item.Children, "采纳人："+proposal.DecidedBy)
+		}
+		items = append(items, item)
+	}
+	section := Section{Title: "Agent 亮点"}
+	section.paragraph(Text{Plain: fmt.Sprintf("主持人采纳了 %d 条 Agent 提议。", len(sorted))})
+	section.list(items)
+	return section
+}
+
+// proposalSummary describes what an accepted proposal changed
+func proposalSummary(proposal *models.Proposal) string {
+	switch proposal.Type {
+	case models.ProposalAddCustomer:
+		return fmt.Sprintf("新增目标客户「%s」", proposal.Text)
+	case models.ProposalAddProblem:
+		return fmt.Sprintf("新增核心问题「%s」", proposal.Text)
+	case models.ProposalAddFactor:
+		if proposal.Factor != nil {
+			return fmt.Sprintf("新增差异化因素「%s」", proposal.Factor.Name)
+		}
+	case models.ProposalPositionProduct:
+		if proposal.Product != nil {
+			return fmt.Sprintf("将「%s」定位在 (%.0f, %.0f)", proposal.Product.Name, proposal.Product.X, proposal.Product.Y)
+		}
+	case models.ProposalAddPath:
+		if proposal.Path != nil {
+			return fmt.Sprintf("新增执行路径「%s」", proposal.Path.Name)
+		}
+	}
+	return proposal.Type
+}
+
+func labelOr(labels map[string]string, value string) string {
+	if label, ok := labels[value]; ok {
+		return label
+	}
+	return value
+}
+
+func orPending(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "待确定"
+	}
+	return value
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"archive/zip"
+	"io"
+	"text/template"
+)
+
+// docxParts maps the parts of the Word package to the templates that produce them, in the order they
+// are written; [Content_Types].xml comes first as the OOXML packaging convention expects
+var docxParts = []struct {
+	name     string
+	template string
+}{
+	{"[Content_Types].xml", "content_types.xml.tmpl"},
+	{"_rels/.rels", "rels.xml.tmpl"},
+	{"docProps/core.xml", "core.xml.tmpl"},
+	{"word/_rels/document.xml.rels", "document_rels.xml.tmpl"},
+	{"word/styles.xml", "styles.xml.tmpl"},
+	{"word/numbering.xml", "numbering.xml.tmpl"},
+	{"word/document.xml", "document.xml.tmpl"},
+}
+
+// writeDOCX writes doc as a Word (OOXML) package. Entries are stamped with the generation time
+// so the same document always produces the same bytes
+func writeDOCX(w io.Writer, tmpl *template.Template, doc *Document) error {
+	archive := zip.NewWriter(w)
+	for _, part := range docxParts {
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     part.name,
+			Method:   zip.Deflate,
+			Modified: doc.GeneratedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tmpl.ExecuteTemplate(entry, part.template, doc); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
@@ -0,0 +1,85 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+)
+
+// pptxParts maps the fixed parts of the PowerPoint package to their templates; slides follow them
+var pptxParts = []struct {
+	name     string
+	template string
+}{
+	{"[Content_Types].xml", "content_types.xml.tmpl"},
+	{"_rels/.rels", "rels.xml.tmpl"},
+	{"docProps/core.xml", "core.xml.tmpl"},
+	{"ppt/presentation.xml", "presentation.xml.tmpl"},
+	{"ppt/_rels/presentation.xml.rels", "presentation_rels.xml.tmpl"},
+	{"ppt/presProps.xml", "pres_props.xml.tmpl"},
+	{"ppt/theme/theme1.xml", "theme.xml.tmpl"},
+	{"ppt/slideMasters/slideMaster1.xml", "slide_master.xml.tmpl"},
+	{"ppt/slideMasters/_rels/slideMaster1.xml.rels", "slide_master_rels.xml.tmpl"},
+	{"ppt/slideLayouts/slideLayout1.xml", "slide_layout.xml.tmpl"},
+	{"ppt/slideLayouts/_rels/slideLayout1.xml.rels", "slide_layout_rels.xml.tmpl"},
+}
+
+// PitchDeck renders the sprint as an investor pitch deck (PPTX) in theme, with the given template
+// version; an empty version uses the latest. Fields left out of theme are taken from the default theme
+func PitchDeck(sprint *Sprint, theme DeckTheme, version string, generatedAt time.Time) (*Output, error) {
+	if version == "" {
+		version = LatestVersion
+	}
+	set, ok := templateSets[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown template version: %s", version)
+	}
+	theme.fill(DefaultDeckThemes()[DefaultDeckTheme])
+	if err := theme.validate(); err != nil {
+		return nil, fmt.Errorf("invalid deck theme: %w", err)
+	}
+	d := buildDeck(sprint, theme, generatedAt)
+	d.TemplateVersion = version
+
+	var buf bytes.Buffer
+	if err := writePPTX(&buf, set.pptx, d); err != nil {
+		return nil, fmt.Errorf("failed to render pptx: %w", err)
+	}
+	return &Output{
+		Format:      "pptx",
+		ContentType: "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+		Extension:   ".pptx",
+		Body:        buf.Bytes(),
+	}, nil
+}
+
+// writePPTX writes d as a PowerPoint (OOXML) package. Entries are stamped with the generation time
+// so the same deck always produces the same bytes
+func writePPTX(w io.Writer, tmpl *template.Template, d *deck) error {
+	archive := zip.NewWriter(w)
+	write := func(name, templateName string, data interface{}) error {
+		entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: d.GeneratedAt})
+		if err != nil {
+			return err
+		}
+		return tmpl.ExecuteTemplate(entry, templateName, data)
+	}
+
+	for _, part := range pptxParts {
+		if err := write(part.name, part.template, d); err != nil {
+			return err
+		}
+	}
+	for _, slide := range d.Slides {
+		if err := write(fmt.Sprintf("ppt/slides/slide%d.xml", slide.Number), "slide.xml.tmpl", slide); err != nil {
+			return err
+		}
+		if err := write(fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", slide.Number), "slide_rels.xml.tmpl", slide); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
@@ -0,0 +1,214 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"foundation-sprint/internal/models"
+	"io"
+	"path"
+	"strings"
+	"testing"
+)
+
+// goldenSprint is a completed room that fills every slide of the pitch deck
+func goldenSprint() *Sprint {
+	weight := 2.0
+	return &Sprint{
+		Room: &models.Room{
+			Name:   "Acme <Sprint> & Co",
+			Status: models.PhaseCompleted,
+			Foundation: models.Foundation{
+				Customers:   []models.Card{{Text: "中小企业主", Description: "没有专职 IT"}},
+				Problems:    []models.Card{{Text: "对账耗时", PainIntensity: 8}},
+				Competition: []models.Card{{Text: "Excel", Kind: "workaround"}},
+				Advantages:  []models.Card{{Text: "银行直连"}},
+			},
+			Differentiation: models.Differentiation{
+				Principles: []string{"自动优先于手动", "清晰优先于全面"},
+				Matrix: models.Matrix2x2{
+					XAxis: "自动化程度",
+					YAxis: "易用性",
+					Products: []models.ProductPosition{
+						{Name: "Acme", X: 85, Y: 80, IsUs: true},
+						{Name: "Excel", X: 20, Y: 60},
+						{Name: "ERP", X: 70, Y: 15},
+					},
+				},
+			},
+			Approach: models.Approach{
+				Paths: []models.Path{
+					{ID: "p1", Name: "银行插件", Description: "先接入三家银行\n再扩展到支付平台"},
+					{ID: "p2", Name: "独立应用"},
+				},
+				MagicLenses: []models.MagicLens{
+					{Name: "客户", Evaluations: []models.PathEvaluation{{PathID: "p1", Score: 4}, {PathID: "p2", Score: 3}}},
+					{Name: "增长", Weight: &weight, Evaluations: []models.PathEvaluation{{PathID: "p1", Score: 3.5}, {PathID: "p2", Score: 5}}},
+				},
+				SelectedPath: "p1",
+				Reasoning:    "客户访谈中对账痛点最强",
+			},
+		},
+		Hypotheses: []*models.Hypothesis{
+			{Statement: "店主愿意每月付费 99 元", Status: models.HypothesisUntested, ValidationMethod: "落地页预售", SuccessMetric: "转化率 ≥ 5%"},
+			{Statement: "已验证的假设不进入路演", Status: models.HypothesisValidated},
+		},
+	}
+}
+
+// unzipPackage reads every entry of a zip package by name
+func unzipPackage(t *testing.T, body []byte) map[string][]byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("output is not a zip package: %v", err)
+	}
+	parts := make(map[string][]byte, len(archive.File))
+	for _, file := range archive.File {
+		entry, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(entry)
+		entry.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", file.Name, err)
+		}
+		parts[file.Name] = content
+	}
+	return parts
+}
+
+func TestPitchDeckGolden(t *testing.T) {
+	for _, version := range Versions {
+		t.Run(version, func(t *testing.T) {
+			output, err := PitchDeck(goldenSprint(), DefaultDeckThemes()[DefaultDeckTheme], version, goldenTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertGolden(t, version, "pitch_deck.pptx.golden", unzipParts(t, output.Body, goldenTime))
+		})
+	}
+}
+
+// TestPitchDeckStructure checks the package is self-consistent: every relationship target exists and
+// every part has a content type
+func TestPitchDeckStructure(t *testing.T) {
+	output, err := PitchDeck(goldenSprint(), DefaultDeckThemes()["dark"], "", goldenTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := unzipPackage(t, output.Body)
+
+	var types struct {
+		Defaults []struct {
+			Extension string `xml:"Extension,attr"`
+		} `xml:"Default"`
+		Overrides []struct {
+			PartName string `xml:"PartName,attr"`
+		} `xml:"Override"`
+	}
+	if err := xml.Unmarshal(parts["[Content_Types].xml"], &types); err != nil {
+		t.Fatalf("[Content_Types].xml: %v", err)
+	}
+	typed := make(map[string]bool)
+	for _, d := range types.Defaults {
+		typed["."+d.Extension] = true
+	}
+	for _, o := range types.Overrides {
+		name := strings.TrimPrefix(o.PartName, "/")
+		if _, ok := parts[name]; !ok {
+			t.Errorf("[Content_Types].xml overrides missing part %s", name)
+		}
+		typed[name] = true
+	}
+
+	slides := 0
+	for name, content := range parts {
+		if name == "[Content_Types].xml" {
+			continue
+		}
+		if !typed[name] && !typed[path.Ext(name)] {
+			t.Errorf("%s has no content type", name)
+		}
+		if strings.HasPrefix(name, "ppt/slides/slide") {
+			slides++
+		}
+		if !strings.HasSuffix(name, ".rels") {
+			continue
+		}
+
+		var rels struct {
+			Relationships []struct {
+				Target     string `xml:"Target,attr"`
+				TargetMode string `xml:"TargetMode,attr"`
+			} `xml:"Relationship"`
+		}
+		if err := xml.Unmarshal(content, &rels); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		// Targets are relative to the folder holding the _rels folder
+		base := path.Dir(path.Dir(name))
+		for _, rel := range rels.Relationships {
+			if rel.TargetMode == "External" {
+				continue
+			}
+			target := strings.TrimPrefix(path.Join(base, rel.Target), "/")
+			if _, ok := parts[target]; !ok {
+				t.Errorf("%s points at missing part %s", name, target)
+			}
+		}
+	}
+
+	// cover, four foundation slides, 2x2, principles, chosen path and hypotheses
+	if slides != 9 {
+		t.Errorf("deck has %d slides, want 9", slides)
+	}
+	if !strings.Contains(string(parts["ppt/slides/slide1.xml"]), "Acme &lt;Sprint&gt; &amp; Co") {
+		t.Error("cover slide does not carry the escaped room name")
+	}
+}
+
+func TestPitchDeckFillsPartialTheme(t *testing.T) {
+	light := DefaultDeckThemes()[DefaultDeckTheme]
+	want, err := PitchDeck(goldenSprint(), light, "", goldenTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zero, err := PitchDeck(goldenSprint(), DeckTheme{}, "", goldenTime)
+	if err != nil {
+		t.Fatalf("zero theme: %v", err)
+	}
+	if !bytes.Equal(zero.Body, want.Body) {
+		t.Error("zero theme: want the default theme's deck")
+	}
+
+	partial, err := PitchDeck(goldenSprint(), DeckTheme{Accent: "#ff0000", HeadingFont: "Georgia"}, "", goldenTime)
+	if err != nil {
+		t.Fatalf("partial theme: %v", err)
+	}
+	for name, content := range unzipPackage(t, partial.Body) {
+		if bytes.Contains(content, []byte(`val=""`)) || bytes.Contains(content, []byte(`<a:latin typeface=""`)) {
+			t.Errorf("%s has an empty colour or font", name)
+		}
+	}
+	theme := string(unzipPackage(t, partial.Body)["ppt/theme/theme1.xml"])
+	for _, fragment := range []string{
+		`<a:accent1><a:srgbClr val="FF0000"/>`,
+		`<a:dk1><a:srgbClr val="` + light.Text + `"/>`,
+		`<a:majorFont><a:latin typeface="Georgia"/>`,
+		`<a:minorFont><a:latin typeface="` + light.BodyFont + `"/>`,
+	} {
+		if !strings.Contains(theme, fragment) {
+			t.Errorf("theme1.xml lacks %s", fragment)
+		}
+	}
+
+	if _, err := PitchDeck(goldenSprint(), DeckTheme{Background: "white"}, "", goldenTime); err == nil {
+		t.Error("invalid colour: want error")
+	}
+	if _, err := PitchDeck(goldenSprint(), light, "v0", goldenTime); err == nil {
+		t.Error("unknown template version: want error")
+	}
+}
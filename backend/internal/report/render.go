@@ -0,0 +1,146 @@
+// Package report renders a sprint room as a shareable report.
+package report
+
+import (
+	"bytes"
+	"embed"
+	"encoding/xml"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Report formats
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatDOCX     = "docx"
+)
+
+// Formats lists the supported report formats
+var Formats = []string{FormatMarkdown, FormatHTML, FormatDOCX}
+
+// Versions lists the template versions, oldest first. A new version is a new directory under
+// templates/; released versions stay unchanged so automation pinned to one keeps its layout
+var Versions = []string{"v1"}
+
+// LatestVersion is the template version used when none is requested
+var LatestVersion = Versions[len(Versions)-1]
+
+//go:embed templates
+var templateFS embed.FS
+
+// Output is a rendered report
+type Output struct {
+	Format      string
+	ContentType string
+	Extension   string
+	Body        []byte
+}
+
+// templateSet holds the parsed templates of one version
+type templateSet struct {
+	markdown *template.Template
+	html     *htmltemplate.Template
+	docx     *template.Template
+	pptx     *template.Template
+}
+
+var templateSets = make(map[string]*templateSet, len(Versions))
+
+func init() {
+	for _, version := range Versions {
+		dir := "templates/" + version
+		funcs := template.FuncMap{"xml": escapeXML, "lines": lines}
+		templateSets[version] = &templateSet{
+			markdown: template.Must(template.New("report.md.tmpl").ParseFS(templateFS, dir+"/report.md.tmpl")),
+			html:     htmltemplate.Must(htmltemplate.New("report.html.tmpl").ParseFS(templateFS, dir+"/report.html.tmpl")),
+			docx:     template.Must(template.New("docx").Funcs(funcs).ParseFS(templateFS, dir+"/docx/*.tmpl")),
+			pptx:     template.Must(template.New("pptx").Funcs(funcs).ParseFS(templateFS, dir+"/pptx/*.tmpl")),
+		}
+	}
+}
+
+// IsFormat reports whether format is a supported report format
+func IsFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVersion reports whether version is a known template version
+func IsVersion(version string) bool {
+	_, ok := templateSets[version]
+	return ok
+}
+
+// Render renders the sprint in format with the given template version; an empty version uses the latest
+func Render(sprint *Sprint, format, version string, generatedAt time.Time) (*Output, error) {
+	if version == "" {
+		version = LatestVersion
+	}
+	if !IsVersion(version) {
+		return nil, fmt.Errorf("unknown template version: %s", version)
+	}
+	doc := Build(sprint, generatedAt)
+	doc.TemplateVersion = version
+	return renderDocument(doc, format)
+}
+
+// renderDocument renders doc in format with the templates of doc.TemplateVersion
+func renderDocument(doc *Document, format string) (*Output, error) {
+	set, ok := templateSets[doc.TemplateVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown template version: %s", doc.TemplateVersion)
+	}
+
+	var buf bytes.Buffer
+	output := &Output{Format: format}
+	switch format {
+	case FormatMarkdown:
+		output.ContentType, output.Extension = "text/markdown; charset=utf-8", ".md"
+		if err := set.markdown.Execute(&buf, doc); err != nil {
+			return nil, fmt.Errorf("failed to render markdown: %w", err)
+		}
+	case FormatHTML:
+		output.ContentType, output.Extension = "text/html; charset=utf-8", ".html"
+		if err := set.html.Execute(&buf, doc); err != nil {
+			return nil, fmt.Errorf("failed to render html: %w", err)
+		}
+	case FormatDOCX:
+		output.ContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+		output.Extension = ".docx"
+		if err := writeDOCX(&buf, set.docx, doc); err != nil {
+			return nil, fmt.Errorf("failed to render docx: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	output.Body = buf.Bytes()
+	return output, nil
+}
+
+// Markdown renders the sprint with the latest Markdown template
+func Markdown(sprint *Sprint, generatedAt time.Time) (string, error) {
+	output, err := Render(sprint, FormatMarkdown, "", generatedAt)
+	if err != nil {
+		return "", err
+	}
+	return string(output.Body), nil
+}
+
+func escapeXML(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+// lines splits multi-line text so templates can emit explicit line breaks
+func lines(value string) []string {
+	return strings.Split(value, "\n")
+}
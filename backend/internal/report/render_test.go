@@ -0,0 +1,217 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden files in testdata")
+
+// goldenTime pins the generation time so rendered output is stable
+var goldenTime = time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+
+// reportGoldens lists the golden files every template version must have
+var reportGoldens = []string{"report.md.golden", "report.html.golden", "report.docx.golden", "pitch_deck.pptx.golden"}
+
+// goldenDocument covers every block kind, multi-line text and characters that need escaping
+func goldenDocument(version string) *Document {
+	return &Document{
+		Title:   "Acme <Sprint> & Co - Foundation Sprint 报告",
+		Summary: "当前阶段：completed",
+		Sections: []Section{
+			{
+				Title: "基础信息",
+				Blocks: []Block{
+					{Kind: BlockHeading, Text: Text{Plain: "目标客户"}},
+					{Kind: BlockList, Items: []Item{
+						{Text: Text{Strong: "中小企业主", Plain: "：没有专职 IT"}},
+						{Text: Text{Plain: "独立开发者 \"side projects\""}, Children: []string{"👍 3", "作者：alice & bob"}},
+					}},
+					{Kind: BlockParagraph, Text: Text{Strong: "备注：", Plain: "第一行\n第二行 <b>不是标签</b>"}},
+				},
+			},
+			{
+				Title: "投票结果",
+				Blocks: []Block{
+					{Kind: BlockHeading, Text: Text{Plain: "选择方案"}},
+					{Kind: BlockRanking, Items: []Item{
+						{Rank: 1, Text: Text{Strong: "方案 A", Plain: "（5 票）"}},
+						{Rank: 1, Text: Text{Strong: "方案 B", Plain: "（5 票）"}},
+						{Rank: 3, Text: Text{Strong: "方案 C", Plain: "（1 票）"}},
+					}},
+				},
+			},
+			{Title: "空章节"},
+		},
+		GeneratedAt:     goldenTime,
+		TemplateVersion: version,
+	}
+}
+
+// assertGolden compares got with testdata/<version>/<name>, rewriting the file with -update
+func assertGolden(t *testing.T, version, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", version, name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden %s (run go test ./internal/report -update): %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from its golden; if the change is intended, add a new template version "+
+			"or run go test ./internal/report -update\n%s", path, firstDifference(string(want), string(got)))
+	}
+}
+
+// firstDifference describes the first line where want and got differ
+func firstDifference(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %q\n  got:  %q", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+// unzipParts lists the entries of an OOXML package in order, each followed by its XML, so package
+// goldens diff as text. It also checks every entry is well-formed XML stamped with the pinned
+// generation time
+func unzipParts(t *testing.T, body []byte, generatedAt time.Time) []byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("output is not a zip package: %v", err)
+	}
+
+	var out bytes.Buffer
+	for _, file := range archive.File {
+		if !file.Modified.Equal(generatedAt) {
+			t.Errorf("%s modified %v, want %v", file.Name, file.Modified, generatedAt)
+		}
+		entry, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(entry)
+		entry.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", file.Name, err)
+		}
+		if err := checkWellFormed(content); err != nil {
+			t.Errorf("%s is not well-formed XML: %v", file.Name, err)
+		}
+		fmt.Fprintf(&out, "=== %s ===\n%s\n", file.Name, content)
+	}
+	return out.Bytes()
+}
+
+// checkWellFormed parses content as XML
+func checkWellFormed(content []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	for _, version := range Versions {
+		for _, format := range Formats {
+			t.Run(version+"/"+format, func(t *testing.T) {
+				output, err := renderDocument(goldenDocument(version), format)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				body := output.Body
+				if format == FormatDOCX {
+					body = unzipParts(t, body, goldenTime)
+				}
+				assertGolden(t, version, "report."+format+".golden", body)
+			})
+		}
+	}
+}
+
+func TestRenderIsDeterministic(t *testing.T) {
+	for _, format := range Formats {
+		first, err := renderDocument(goldenDocument(LatestVersion), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := renderDocument(goldenDocument(LatestVersion), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(first.Body, second.Body) {
+			t.Errorf("%s: rendering the same document twice produced different bytes", format)
+		}
+	}
+}
+
+// TestGoldensCoverEveryVersion fails when a template version is added without goldens, or goldens
+// are left behind for a version that no longer exists
+func TestGoldensCoverEveryVersion(t *testing.T) {
+	if *update {
+		t.Skip("goldens are being regenerated")
+	}
+
+	for _, version := range Versions {
+		for _, name := range reportGoldens {
+			if _, err := os.Stat(filepath.Join("testdata", version, name)); err != nil {
+				t.Errorf("template version %s has no golden %s (run go test ./internal/report -update)", version, name)
+			}
+		}
+	}
+
+	entries, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !IsVersion(entry.Name()) {
+			t.Errorf("testdata/%s holds goldens for an unknown template version", entry.Name())
+		}
+	}
+}
+
+func TestRenderRejectsUnknownInput(t *testing.T) {
+	if _, err := renderDocument(goldenDocument("v0"), FormatMarkdown); err == nil {
+		t.Error("unknown template version: want error")
+	}
+	if _, err := renderDocument(goldenDocument(LatestVersion), "pdf"); err == nil {
+		t.Error("unknown format: want error")
+	}
+	if _, err := Render(&Sprint{}, FormatMarkdown, "v0", goldenTime); err == nil {
+		t.Error("Render with unknown template version: want error")
+	}
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"foundation-sprint/internal/database"
+	"foundation-sprint/internal/models"
+)
+
+// Sprint is everything a report covers for one room
+type Sprint struct {
+	Room       *models.Room
+	Votes      []*models.Vote
+	Risks      []*models.Risk
+	Hypotheses []*models.Hypothesis
+	Interviews []*models.Interview
+	Scorecards []*models.Scorecard
+	Proposals  []*models.Proposal // agent proposals the facilitator accepted
+}
+
+// Validations summarises the interview scorecards of each hypothesis
+func (s *Sprint) Validations() []*models.HypothesisValidation {
+	validations := make([]*models.HypothesisValidation, 0, len(s.Hypotheses))
+	for _, hypothesis := range s.Hypotheses {
+		validations = append(validations, models.BuildHypothesisValidation(hypothesis, s.Interviews, s.Scorecards))
+	}
+	return validations
+}
+
+// Load reads the room and the records reported with it; the error is "not found" when the room does not exist
+func Load(ctx context.Context, db database.Database, roomID string) (*Sprint, error) {
+	room, err := db.Rooms().Get(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := db.VoteSessions().GetByRoom(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list votes: %w", err)
+	}
+	risks, err := db.Risks().GetByRoom(ctx, roomID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risks: %w", err)
+	}
+	hypotheses, err := db.Hypotheses().GetByRoom(ctx, roomID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hypotheses: %w", err)
+	}
+	interviews, err := db.Interviews().GetByRoom(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interviews: %w", err)
+	}
+	scorecards, err := db.Scorecards().GetByRoom(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scorecards: %w", err)
+	}
+	proposals, err := db.Proposals().GetByRoom(ctx, roomID, models.ProposalAccepted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list proposals: %w", err)
+	}
+
+	return &Sprint{
+		Room:       room,
+		Votes:      votes,
+		Risks:      risks,
+		Hypotheses: hypotheses,
+		Interviews: interviews,
+		Scorecards: scorecards,
+		Proposals:  proposals,
+	}, nil
+}
@@ -0,0 +1,76 @@
+// Package storage keeps uploaded files in a blob store: the local filesystem or an S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned when a key does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque files by key
+type BlobStore interface {
+	// Put stores size bytes read from body under key, replacing any existing blob
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens a blob; callers must close it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes a blob; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	defaultStore BlobStore
+	storeOnce    sync.Once
+	storeErr     error
+)
+
+// GetBlobStore returns the blob store configured by the environment
+func GetBlobStore() (BlobStore, error) {
+	storeOnce.Do(func() {
+		defaultStore, storeErr = NewBlobStoreFromEnv()
+	})
+	return defaultStore, storeErr
+}
+
+// NewBlobStoreFromEnv creates the store selected by BLOB_STORE: local (default) or s3
+func NewBlobStoreFromEnv() (BlobStore, error) {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("BLOB_STORE"))) {
+	case "", "local":
+		dir := os.Getenv("BLOB_LOCAL_DIR")
+		if dir == "" {
+			dir = "./data/blobs"
+		}
+		return NewLocalStore(dir)
+
+	case "s3":
+		config := S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       os.Getenv("S3_PATH_STYLE") != "false",
+		}
+		return NewS3Store(config)
+	}
+	return nil, fmt.Errorf("unsupported blob store: %s", os.Getenv("BLOB_STORE"))
+}
+
+// validateKey rejects keys that could escape the store's root
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid blob key: %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid blob key: %q", key)
+		}
+	}
+	return nil
+}
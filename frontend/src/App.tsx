@@ -10,42 +10,57 @@ import { FoundationSprintIntro } from '@/components/FoundationSprintIntro'
 import { Toaster } from '@/components/ui/toaster'
 import { apiClient } from '@/lib/api/client'
 import { webSocketService } from '@/lib/websocket'
-import type { Room } from '@/lib/api/types'
+import type { Room, Foundation, FoundationCard, GateCheck } from '@/lib/api/types'
 import { Alert, AlertDescription } from '@/components/ui/alert'
 import { AlertCircle, Loader2, MessageCircle } from 'lucide-react'
 import { Button } from '@/components/ui/button'
 import { useLanguage } from '@/contexts/LanguageContext'
 
-// Convert backend data format to frontend format for compatibility
-const convertToFrontendFoundationData = (foundation: any) => {
+// Convert backend cards to frontend format for compatibility
+const convertToFrontendFoundationData = (foundation: Foundation) => {
   return {
-    customers: foundation.customers || [],
-    problems: foundation.problems?.map((problem: string, index: number) => ({
-      id: `problem-${index}`,
-      description: problem,
-      severity: 5,
-      impact: 'some' as const,
+    customers: foundation.customers?.map((customer: FoundationCard) => customer.text) || [],
+    problems: foundation.problems?.map((problem: FoundationCard) => ({
+      id: problem.id,
+      description: problem.text,
+      severity: problem.pain_intensity || 5,
+      impact: problem.affected_share || ('some' as const),
     })) || [],
-    competition: foundation.competition?.map((comp: string, index: number) => ({
-      id: `comp-${index}`,
-      name: comp,
-      type: 'direct' as const,
-      description: comp,
+    competition: foundation.competition?.map((comp: FoundationCard) => ({
+      id: comp.id,
+      name: comp.text,
+      type: comp.kind || ('direct' as const),
+      description: comp.description || comp.text,
     })) || [],
-    advantages: foundation.advantages?.map((adv: string, index: number) => ({
-      id: `adv-${index}`,
-      description: adv,
-      category: 'technical' as const,
+    advantages: foundation.advantages?.map((adv: FoundationCard) => ({
+      id: adv.id,
+      description: adv.text,
+      category: adv.category || ('technical' as const),
     })) || [],
   }
 }
 
+// Customers are sent as plain text; the backend matches them to existing cards by text
 const convertToBackendFoundationData = (data: any) => {
   return {
     customers: data.customers || [],
-    problems: data.problems?.map((p: any) => p.description || p) || [],
-    competition: data.competition?.map((c: any) => c.name || c.description || c) || [],
-    advantages: data.advantages?.map((a: any) => a.description || a) || [],
+    problems: data.problems?.map((p: any) => ({
+      id: p.id,
+      text: p.description,
+      pain_intensity: p.severity,
+      affected_share: p.impact,
+    })) || [],
+    competition: data.competition?.map((c: any) => ({
+      id: c.id,
+      text: c.name || c.description,
+      description: c.description && c.description !== c.name ? c.description : undefined,
+      kind: c.type,
+    })) || [],
+    advantages: data.advantages?.map((a: any) => ({
+      id: a.id,
+      text: a.description,
+      category: a.category,
+    })) || [],
   }
 }
 
@@ -280,6 +295,7 @@ function App() {
         case 'differentiation_update':
         case 'approach_update':
         case 'status_update':
+        case 'phase_changed':
           // Only refresh room data when OTHER users make changes
           // Skip if the message is from current user to prevent loops
           if (message.data?.userId !== currentUserId) {
@@ -451,18 +467,18 @@ function App() {
     setLoading(true)
     
     try {
-      const updatedRoom = await apiClient.updateRoomStatus(currentRoom.id, nextStatus)
+      // The server checks the exit criteria and broadcasts phase_changed to other users
+      const updatedRoom = await apiClient.updateRoomStatus(currentRoom.id, nextStatus, currentUserId)
       console.log('✅ 房间状态更新成功:', updatedRoom.status)
       setCurrentRoom(updatedRoom)
-      
-      // Broadcast status change to other users
-      webSocketService.send('status_update', { 
-        status: nextStatus, 
-        userId: currentUserId 
-      })
     } catch (error) {
       console.error('❌ 状态更新失败:', error)
-      setError(error instanceof Error ? error.message : '切换阶段失败')
+      const unmet = (error as { unmet?: GateCheck[] }).unmet
+      if (unmet?.length) {
+        setError(`未满足退出条件：${unmet.map(check => check.description).join('；')}`)
+      } else {
+        setError(error instanceof Error ? error.message : '切换阶段失败')
+      }
     } finally {
       setLoading(false)
     }
@@ -599,9 +615,9 @@ function App() {
                             <h4 className="font-medium text-blue-700 mb-2">🎯 目标客户</h4>
                             <div className="text-sm text-gray-600">
                               {currentRoom.foundation.customers.length > 0 ? 
-                                currentRoom.foundation.customers.map((customer, i) => (
-                                  <span key={i} className="inline-block bg-blue-100 text-blue-800 px-2 py-1 rounded-md mr-2 mb-1">
-                                    {customer}
+                                currentRoom.foundation.customers.map((customer) => (
+                                  <span key={customer.id} className="inline-block bg-blue-100 text-blue-800 px-2 py-1 rounded-md mr-2 mb-1">
+                                    {customer.text}
                                   </span>
                                 )) : 
                                 <span className="text-gray-400 italic">待完善</span>
@@ -613,9 +629,9 @@ function App() {
                             <h4 className="font-medium text-red-700 mb-2">❗ 解决问题</h4>
                             <div className="text-sm text-gray-600">
                               {currentRoom.foundation.problems.length > 0 ? 
-                                currentRoom.foundation.problems.map((problem, i) => (
-                                  <div key={i} className="bg-red-50 text-red-800 p-2 rounded-md mb-2">
-                                    {problem}
+                                currentRoom.foundation.problems.map((problem) => (
+                                  <div key={problem.id} className="bg-red-50 text-red-800 p-2 rounded-md mb-2">
+                                    {problem.text}
                                   </div>
                                 )) : 
                                 <span className="text-gray-400 italic">待完善</span>
@@ -59,7 +59,7 @@ export const VoteDialog: React.FC<VoteDialogProps> = ({
 
   const fetchVotes = async () => {
     try {
-      const response = await fetch(`/api/v1/collaboration/rooms/${roomId}/votes`);
+      const response = await fetch(`/api/v1/collaboration/rooms/${roomId}/votes?user_id=${encodeURIComponent(currentUserId)}`);
       if (response.ok) {
         const data = await response.json();
         setVotes(data || []);
@@ -95,7 +95,7 @@ export const VoteDialog: React.FC<VoteDialogProps> = ({
         body: JSON.stringify({
           title: voteForm.title,
           description: voteForm.description,
-          type: voteSubject,
+          type: 'note_and_vote',
           options: validOptions,
           created_by: currentUserName,
         }),
@@ -156,6 +156,13 @@ export const VoteDialog: React.FC<VoteDialogProps> = ({
         toast({
           title: '投票成功',
         });
+      } else {
+        const data = await response.json().catch(() => ({}));
+        toast({
+          title: '投票失败',
+          description: data.error,
+          variant: 'destructive',
+        });
       }
     } catch (error) {
       toast({
@@ -128,16 +128,16 @@ ${t('export.executiveSummaryContent')}
 ## 👥 ${t('foundation.title')} (Foundation)
 
 ### ${t('foundation.customers')}
-${room.foundation.customers.map(c => `- ${c}`).join('\n') || `- *${t('export.toBeCompleted')}*`}
+${room.foundation.customers.map(c => `- ${c.text}`).join('\n') || `- *${t('export.toBeCompleted')}*`}
 
 ### ${t('foundation.problems')}
-${room.foundation.problems.map(p => `- ${p}`).join('\n') || `- *${t('export.toBeCompleted')}*`}
+${room.foundation.problems.map(p => `- ${p.text}`).join('\n') || `- *${t('export.toBeCompleted')}*`}
 
 ### ${t('foundation.competition')}
-${room.foundation.competition.map(c => `- ${c}`).join('\n') || `- *${t('export.toBeCompleted')}*`}
+${room.foundation.competition.map(c => `- ${c.text}`).join('\n') || `- *${t('export.toBeCompleted')}*`}
 
 ### ${t('foundation.advantages')}
-${room.foundation.advantages.map(a => `- ${a}`).join('\n') || `- *${t('export.toBeCompleted')}*`}
+${room.foundation.advantages.map(a => `- ${a.text}`).join('\n') || `- *${t('export.toBeCompleted')}*`}
 
 ## 🎨 ${t('differentiation.title')} (Differentiation)
 
@@ -274,9 +274,9 @@ ${lens.evaluations.map(evaluation => {
 
 我们刚刚完成了 ${room.name} 的 Foundation Sprint，想与你分享我们的成果：
 
-🎯 目标客户: ${room.foundation.customers.slice(0, 2).join(', ')}${room.foundation.customers.length > 2 ? '...' : ''}
+🎯 目标客户: ${room.foundation.customers.slice(0, 2).map(c => c.text).join(', ')}${room.foundation.customers.length > 2 ? '...' : ''}
 
-🔥 核心问题: ${room.foundation.problems.slice(0, 2).join(', ')}${room.foundation.problems.length > 2 ? '...' : ''}
+🔥 核心问题: ${room.foundation.problems.slice(0, 2).map(p => p.text).join(', ')}${room.foundation.problems.length > 2 ? '...' : ''}
 
 ✨ 差异化原则: ${room.differentiation.principles.slice(0, 2).join(', ')}${room.differentiation.principles.length > 2 ? '...' : ''}
 
@@ -318,16 +318,16 @@ ${reportData.executiveSummary}
 ## 基础信息
 
 ### 目标客户
-${(room.foundation.customers || []).map(c => `- ${c}`).join('\n')}
+${(room.foundation.customers || []).map(c => `- ${c.text}`).join('\n')}
 
 ### 核心问题
-${(room.foundation.problems || []).map(p => `- ${p}`).join('\n')}
+${(room.foundation.problems || []).map(p => `- ${p.text}`).join('\n')}
 
 ### 竞争对手
-${(room.foundation.competition || []).map(c => `- ${c}`).join('\n')}
+${(room.foundation.competition || []).map(c => `- ${c.text}`).join('\n')}
 
 ### 团队优势
-${(room.foundation.advantages || []).map(a => `- ${a}`).join('\n')}
+${(room.foundation.advantages || []).map(a => `- ${a.text}`).join('\n')}
 
 ## 差异化定位
 
@@ -460,8 +460,8 @@ ${reportData.nextSteps.map(s => `- ${s}`).join('\n')}
               <div className="card bg-gradient-to-br from-blue-50 to-blue-100 border-l-4 border-blue-500">
                 <h3 className="card-title text-blue-700">目标客户</h3>
                 <ul className="list">
-                  {(room.foundation.customers || []).map((customer, index) => (
-                    <li key={index}>{customer}</li>
+                  {(room.foundation.customers || []).map((customer) => (
+                    <li key={customer.id}>{customer.text}</li>
                   ))}
                 </ul>
               </div>
@@ -469,8 +469,8 @@ ${reportData.nextSteps.map(s => `- ${s}`).join('\n')}
               <div className="card bg-gradient-to-br from-red-50 to-red-100 border-l-4 border-red-500">
                 <h3 className="card-title text-red-700">核心问题</h3>
                 <ul className="list">
-                  {(room.foundation.problems || []).map((problem, index) => (
-                    <li key={index}>{problem}</li>
+                  {(room.foundation.problems || []).map((problem) => (
+                    <li key={problem.id}>{problem.text}</li>
                   ))}
                 </ul>
               </div>
@@ -478,8 +478,8 @@ ${reportData.nextSteps.map(s => `- ${s}`).join('\n')}
               <div className="card bg-gradient-to-br from-yellow-50 to-yellow-100 border-l-4 border-yellow-500">
                 <h3 className="card-title text-yellow-700">竞争对手</h3>
                 <ul className="list">
-                  {(room.foundation.competition || []).map((comp, index) => (
-                    <li key={index}>{comp}</li>
+                  {(room.foundation.competition || []).map((comp) => (
+                    <li key={comp.id}>{comp.text}</li>
                   ))}
                 </ul>
               </div>
@@ -487,8 +487,8 @@ ${reportData.nextSteps.map(s => `- ${s}`).join('\n')}
               <div className="card bg-gradient-to-br from-green-50 to-green-100 border-l-4 border-green-500">
                 <h3 className="card-title text-green-700">团队优势</h3>
                 <ul className="list">
-                  {(room.foundation.advantages || []).map((adv, index) => (
-                    <li key={index}>{adv}</li>
+                  {(room.foundation.advantages || []).map((adv) => (
+                    <li key={adv.id}>{adv.text}</li>
                   ))}
                 </ul>
               </div>
@@ -38,7 +38,11 @@ class ApiClient {
           error: `HTTP ${response.status}: ${response.statusText}`,
         }));
         
-        throw new Error(errorData.error || `Request failed with status ${response.status}`);
+        // Keep the unmet exit criteria so callers can list them
+        throw Object.assign(
+          new Error(errorData.error || `Request failed with status ${response.status}`),
+          { unmet: errorData.unmet }
+        );
       }
 
       return await response.json();
@@ -93,11 +97,17 @@ class ApiClient {
 
   async updateRoomStatus(
     roomId: string, 
-    status: 'foundation' | 'differentiation' | 'approach' | 'completed'
+    status: 'foundation' | 'differentiation' | 'approach' | 'completed',
+    userId?: string,
+    override?: { reason: string }
   ): Promise<Room> {
     return this.request<Room>(`/foundation/rooms/${roomId}/status`, {
       method: 'PUT',
-      body: JSON.stringify({ status }),
+      body: JSON.stringify({
+        status,
+        user_id: userId,
+        ...(override ? { override: true, reason: override.reason } : {}),
+      }),
     });
   }
 
@@ -123,6 +133,16 @@ class ApiClient {
     });
   }
 
+  // Server-side report download URL
+  getRoomReportUrl(roomId: string, format: 'md' | 'html' | 'docx' = 'md'): string {
+    return `${this.baseUrl}/foundation/rooms/${roomId}/report?format=${format}`;
+  }
+
+  // Pitch deck (PPTX) download URL, available once the room is completed
+  getPitchDeckUrl(roomId: string, theme: string = 'light'): string {
+    return `${this.baseUrl}/foundation/rooms/${roomId}/pitch-deck?theme=${encodeURIComponent(theme)}`;
+  }
+
   // WebSocket URL generator
   getWebSocketUrl(roomId: string, userId: string): string {
     const wsProtocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
@@ -10,13 +10,45 @@ export interface Room {
   differentiation: Differentiation;
   approach: Approach;
   status: 'foundation' | 'differentiation' | 'approach' | 'completed';
+  settings?: RoomSettings;
+}
+
+export interface AgendaItem {
+  phase: 'foundation' | 'differentiation' | 'approach';
+  title: string;
+  minutes: number;
+}
+
+export interface RoomSettings {
+  agenda: AgendaItem[];
+  agents: {
+    instructions?: string;
+    budget_usd?: number;
+  };
+  template_id?: string;
+  cloned_from?: string;
+}
+
+export interface FoundationCard {
+  id: string;
+  type: 'customer' | 'problem' | 'competition' | 'advantage';
+  text: string;
+  description?: string;
+  author?: string;
+  order: number;
+  created_at: string;
+  updated_at: string;
+  pain_intensity?: number; // problem cards, 1-10
+  affected_share?: 'few' | 'some' | 'many' | 'most'; // problem cards
+  kind?: 'direct' | 'alternative' | 'workaround'; // competition cards
+  category?: 'technical' | 'insight' | 'resource' | 'motivation'; // advantage cards
 }
 
 export interface Foundation {
-  customers: string[];
-  problems: string[];
-  competition: string[];
-  advantages: string[];
+  customers: FoundationCard[];
+  problems: FoundationCard[];
+  competition: FoundationCard[];
+  advantages: FoundationCard[];
 }
 
 export interface Differentiation {
@@ -45,6 +77,7 @@ export interface ProductPosition {
   x: number;
   y: number;
   is_us: boolean;
+  scores?: Record<string, number>; // factor id -> 0-100
 }
 
 export interface Approach {
@@ -127,6 +160,7 @@ export interface WebSocketMessage {
 export interface CreateRoomRequest {
   name: string;
   created_by: string;
+  template_id?: string;
 }
 
 export interface ApiResponse<T> {
@@ -137,4 +171,14 @@ export interface ApiResponse<T> {
 export interface ApiError {
   error: string;
   details?: string;
+  unmet?: GateCheck[];
+}
+
+// Exit criterion returned by the server when a phase change is rejected
+export interface GateCheck {
+  check: string;
+  description: string;
+  passed: boolean;
+  actual: number;
+  required?: number;
 }
\ No newline at end of file
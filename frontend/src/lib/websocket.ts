@@ -5,6 +5,7 @@ export type WebSocketEventType =
   | 'differentiation_update'
   | 'approach_update'
   | 'status_update'
+  | 'phase_changed'
   | 'user_join'
   | 'user_leave'
   | 'user_list'